@@ -0,0 +1,187 @@
+// Package client is a Go client for the Persons API.
+//
+//	c, err := client.New("http://localhost:8080")
+//	p, err := c.Create(ctx, client.PersonInput{Name: "Alice", Age: 30, Hobbies: []string{"chess"}})
+//	if errors.Is(err, client.ErrValidation) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const personsPath = "/api/v1/persons"
+
+// Client calls the Persons API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	retry      retryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. The default client
+// times out after 30 seconds.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetry sets how often a request is retried and the bounds of the
+// exponential backoff between attempts. maxRetries 0 disables retries.
+func WithRetry(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.retry = retryPolicy{maxRetries: maxRetries, minBackoff: minBackoff, maxBackoff: maxBackoff}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// New returns a Client for the API served at baseURL, e.g.
+// "https://persons.example.com".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("client: invalid base URL %q", baseURL)
+	}
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "persons-api-go-client",
+		retry:      defaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Create adds a person and returns it with its assigned ID.
+func (c *Client) Create(ctx context.Context, in PersonInput) (Person, error) {
+	var p Person
+	err := c.do(ctx, http.MethodPost, personsPath, in, &p)
+	return p, err
+}
+
+// Get returns the person with the given ID.
+func (c *Client) Get(ctx context.Context, id uuid.UUID) (Person, error) {
+	var p Person
+	err := c.do(ctx, http.MethodGet, personPath(id), nil, &p)
+	return p, err
+}
+
+// List returns one page of persons. Pages start at 0.
+func (c *Client) List(ctx context.Context, page, size int) (Page, error) {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("size", strconv.Itoa(size))
+	var p Page
+	err := c.do(ctx, http.MethodGet, personsPath+"?"+q.Encode(), nil, &p)
+	return p, err
+}
+
+// Update replaces every field of the person with the given ID. Empty
+// optional fields are sent as null, which removes them; use Patch to keep
+// the fields not set.
+func (c *Client) Update(ctx context.Context, id uuid.UUID, in PersonInput) (Person, error) {
+	var p Person
+	err := c.do(ctx, http.MethodPut, personPath(id), in.replacement(), &p)
+	return p, err
+}
+
+// Patch changes only the fields set in patch.
+func (c *Client) Patch(ctx context.Context, id uuid.UUID, patch PersonPatch) (Person, error) {
+	var p Person
+	err := c.do(ctx, http.MethodPatch, personPath(id), patch, &p)
+	return p, err
+}
+
+// Delete removes the person with the given ID.
+func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, personPath(id), nil, nil)
+}
+
+func personPath(id uuid.UUID) string {
+	return personsPath + "/" + id.String()
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("client: encoding request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, payload)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= c.retry.maxRetries || !idempotent(method) {
+				return err
+			}
+			if err := c.retry.wait(ctx, attempt, ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("client: reading response: %w", err)
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			if attempt < c.retry.maxRetries && retryable(method, resp.StatusCode) {
+				if err := c.retry.wait(ctx, attempt, resp.Header.Get("Retry-After")); err != nil {
+					return err
+				}
+				continue
+			}
+			return newError(resp.StatusCode, data)
+		}
+		if out == nil || len(data) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("client: decoding response: %w", err)
+		}
+		return nil
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	return c.httpClient.Do(req)
+}
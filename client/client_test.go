@@ -0,0 +1,114 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/client"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+	personSvc := person.NewPersonSvc(repository.NewRepository())
+	app := web.NewApp(8080, slog.Default(), personSvc, customvalidator.NewCustomValidator(validator.New()))
+	server := httptest.NewServer(app.Handler())
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_CRUD(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, client.PersonInput{Name: "Alice", Age: 30, Hobbies: []string{"chess"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+
+	got, err := c.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	updated, err := c.Update(ctx, created.ID, client.PersonInput{Name: "Alice Smith", Age: 31, Hobbies: []string{"go"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Smith", updated.Name)
+
+	// Update also removes the optional fields it is not given.
+	_, err = c.Patch(ctx, created.ID, client.PersonPatch{Email: client.String("alice@example.com"), Phone: client.String("+14155550100")})
+	require.NoError(t, err)
+	updated, err = c.Update(ctx, created.ID, client.PersonInput{Name: "Alice Smith", Age: 31, Hobbies: []string{"go"}, Phone: "+14155550101"})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Email)
+	assert.Equal(t, "+14155550101", updated.Phone)
+	updated, err = c.Update(ctx, created.ID, client.PersonInput{Name: "Alice Smith", Age: 31, Hobbies: []string{"go"}})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Phone)
+
+	patched, err := c.Patch(ctx, created.ID, client.PersonPatch{Age: client.Int32(32)})
+	require.NoError(t, err)
+	assert.Equal(t, client.Person{ID: created.ID, Name: "Alice Smith", Age: 32, Hobbies: []string{"go"}}, patched)
+
+	require.NoError(t, c.Delete(ctx, created.ID))
+	_, err = c.Get(ctx, created.ID)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	assert.ErrorIs(t, c.Delete(ctx, created.ID), client.ErrNotFound)
+}
+
+func TestClient_ValidationError(t *testing.T) {
+	c := newClient(t)
+
+	_, err := c.Create(context.Background(), client.PersonInput{Age: 200, Hobbies: []string{"chess"}})
+	require.ErrorIs(t, err, client.ErrValidation)
+	assert.NotErrorIs(t, err, client.ErrNotFound)
+
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 422, apiErr.StatusCode)
+	assert.Contains(t, apiErr.Fields, "name")
+	assert.Contains(t, apiErr.Fields, "age")
+}
+
+func TestClient_All(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	want := map[uuid.UUID]bool{}
+	for i := 0; i < 25; i++ {
+		p, err := c.Create(ctx, client.PersonInput{Name: fmt.Sprintf("person %02d", i), Age: 20, Hobbies: []string{"chess"}})
+		require.NoError(t, err)
+		want[p.ID] = true
+	}
+
+	page, err := c.List(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.Len(t, page.Persons, 5)
+	assert.Equal(t, int32(25), page.Meta.TotalRecords)
+
+	seen := map[uuid.UUID]bool{}
+	it := c.All(ctx, 10)
+	for it.Next() {
+		seen[it.Person().ID] = true
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, want, seen)
+}
+
+func TestClient_AllEmpty(t *testing.T) {
+	it := newClient(t).All(context.Background(), 10)
+	assert.False(t, it.Next())
+	assert.NoError(t, it.Err())
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotFound matches errors for persons that do not exist.
+	ErrNotFound = errors.New("client: not found")
+	// ErrValidation matches errors for input the API rejected; the
+	// offending fields are in Error.Fields.
+	ErrValidation = errors.New("client: validation failed")
+)
+
+// Error is returned for responses with a 4xx or 5xx status. Use errors.Is
+// with ErrNotFound or ErrValidation, or errors.As to inspect it.
+type Error struct {
+	StatusCode int
+	Message    string
+	// Fields maps invalid field names to the reason they were rejected.
+	Fields map[string]string
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if len(e.Fields) > 0 {
+		fields := make([]string, 0, len(e.Fields))
+		for field, reason := range e.Fields {
+			fields = append(fields, field+": "+reason)
+		}
+		sort.Strings(fields)
+		msg = strings.Join(fields, "; ")
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("client: %d %s", e.StatusCode, msg)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity || e.StatusCode == http.StatusBadRequest
+	}
+	return false
+}
+
+// newError decodes the API's JSON error bodies, falling back to the raw
+// body for plain text errors.
+func newError(status int, body []byte) *Error {
+	e := &Error{StatusCode: status}
+	var decoded struct {
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	if json.Unmarshal(body, &decoded) != nil {
+		e.Message = strings.TrimSpace(string(body))
+		return e
+	}
+	e.Message = decoded.Message
+	if len(decoded.Errors) > 0 {
+		_ = json.Unmarshal(decoded.Errors, &e.Fields)
+	}
+	return e
+}
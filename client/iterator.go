@@ -0,0 +1,61 @@
+package client
+
+import "context"
+
+// Iterator walks every person page by page. Use it like bufio.Scanner:
+//
+//	it := c.All(ctx, 100)
+//	for it.Next() {
+//		p := it.Person()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator struct {
+	client   *Client
+	ctx      context.Context
+	pageSize int
+	page     int
+	lastPage int
+	buf      []Person
+	current  Person
+	err      error
+	started  bool
+}
+
+// All returns an Iterator over all persons, fetching pageSize at a time.
+func (c *Client) All(ctx context.Context, pageSize int) *Iterator {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	return &Iterator{client: c, ctx: ctx, pageSize: pageSize}
+}
+
+// Next advances to the next person, fetching the next page when needed. It
+// returns false when every person was visited or a request failed.
+func (it *Iterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || (it.started && it.page >= it.lastPage) {
+			return false
+		}
+		page, err := it.client.List(it.ctx, it.page, it.pageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.started = true
+		it.page++
+		it.lastPage = int(page.Meta.LastPage)
+		it.buf = page.Persons
+	}
+	it.current, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Person returns the person Next advanced to.
+func (it *Iterator) Person() Person {
+	return it.current
+}
+
+// Err returns the first error met while iterating.
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type retryPolicy struct {
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{maxRetries: 3, minBackoff: 100 * time.Millisecond, maxBackoff: 5 * time.Second}
+
+// idempotent reports whether a request can be repeated without side effects
+// when its outcome is unknown.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a failed response is worth another attempt. 429
+// means the request was not processed and is always retried; server errors
+// only for idempotent methods so a create is never applied twice.
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	return status >= http.StatusInternalServerError && idempotent(method)
+}
+
+// backoff returns the delay before retry attempt+1: exponential with full
+// jitter, or the server's Retry-After when given, capped at maxBackoff.
+func (p retryPolicy) backoff(attempt int, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return min(d, p.maxBackoff)
+	}
+	d := p.minBackoff << attempt
+	if d <= 0 || d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func (p retryPolicy) wait(ctx context.Context, attempt int, retryAfter string) error {
+	t := time.NewTimer(p.backoff(attempt, retryAfter))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
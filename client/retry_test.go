@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer fails the first failures requests with status, asking to
+// retry immediately on 429.
+func flakyServer(t *testing.T, failures int32, status int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + uuid.NewString() + `","name":"Alice","age":30,"hobbies":["chess"]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+	require.NoError(t, err)
+	return c, &calls
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int32
+		status        int
+		create        bool
+		wantErr       bool
+		expectedCalls int32
+	}{
+		{"get retried on 503", 2, http.StatusServiceUnavailable, false, false, 3},
+		{"get gives up after max retries", 10, http.StatusBadGateway, false, true, 4},
+		{"create retried on 429", 1, http.StatusTooManyRequests, true, false, 2},
+		{"create not retried on 500", 1, http.StatusInternalServerError, true, true, 1},
+		{"not retried on 404", 1, http.StatusNotFound, false, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, calls := flakyServer(t, tt.failures, tt.status)
+			var err error
+			if tt.create {
+				_, err = c.Create(context.Background(), PersonInput{Name: "Alice", Age: 30, Hobbies: []string{"chess"}})
+			} else {
+				_, err = c.Get(context.Background(), uuid.New())
+			}
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	c, _ := flakyServer(t, 10, http.StatusServiceUnavailable)
+	c.retry = retryPolicy{maxRetries: 5, minBackoff: time.Hour, maxBackoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Get(ctx, uuid.New())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBackoff(t *testing.T) {
+	p := retryPolicy{maxRetries: 5, minBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+
+	for attempt := 0; attempt < 8; attempt++ {
+		d := p.backoff(attempt, "")
+		assert.LessOrEqual(t, d, p.maxBackoff)
+		assert.Greater(t, d, time.Duration(0))
+	}
+	assert.Equal(t, 0*time.Second, p.backoff(0, "0"))
+	assert.Equal(t, p.maxBackoff, p.backoff(0, "120"), "Retry-After is capped")
+}
@@ -0,0 +1,107 @@
+package client
+
+import "github.com/google/uuid"
+
+type Person struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Age     int32     `json:"age"`
+	Hobbies []string  `json:"hobbies"`
+	Email   string    `json:"email,omitempty"`
+	Phone   string    `json:"phone,omitempty"`
+	// BirthDate is formatted as YYYY-MM-DD.
+	BirthDate string   `json:"birthDate,omitempty"`
+	Address   *Address `json:"address,omitempty"`
+	// Attributes are the custom fields defined by the tenant's schema.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type Address struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postalCode"`
+	Region     string `json:"region,omitempty"`
+	// Country is an ISO 3166-1 alpha-2 code.
+	Country string `json:"country"`
+}
+
+// PersonInput holds every field of a person for Create and Update. Age is
+// ignored by the server when BirthDate is set.
+type PersonInput struct {
+	Name       string         `json:"name"`
+	Age        int32          `json:"age"`
+	Hobbies    []string       `json:"hobbies"`
+	Email      string         `json:"email,omitempty"`
+	Phone      string         `json:"phone,omitempty"`
+	BirthDate  string         `json:"birthDate,omitempty"`
+	Address    *Address       `json:"address,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// personReplacement is a PersonInput with the empty optional fields sent
+// as null, since PUT keeps the fields it does not receive.
+type personReplacement struct {
+	Name       string         `json:"name"`
+	Age        int32          `json:"age"`
+	Hobbies    []string       `json:"hobbies"`
+	Email      *string        `json:"email"`
+	Phone      *string        `json:"phone"`
+	BirthDate  *string        `json:"birthDate"`
+	Address    *Address       `json:"address"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+func (in PersonInput) replacement() personReplacement {
+	orNull := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	r := personReplacement{
+		Name:      in.Name,
+		Age:       in.Age,
+		Hobbies:   in.Hobbies,
+		Email:     orNull(in.Email),
+		Phone:     orNull(in.Phone),
+		BirthDate: orNull(in.BirthDate),
+		Address:   in.Address,
+	}
+	if len(in.Attributes) > 0 {
+		r.Attributes = in.Attributes
+	}
+	return r
+}
+
+// PersonPatch holds the fields to change with Patch; nil fields are left
+// untouched. String, Int32 and Strings help building it.
+type PersonPatch struct {
+	Name      *string   `json:"name,omitempty"`
+	Age       *int32    `json:"age,omitempty"`
+	Hobbies   *[]string `json:"hobbies,omitempty"`
+	Email     *string   `json:"email,omitempty"`
+	Phone     *string   `json:"phone,omitempty"`
+	BirthDate *string   `json:"birthDate,omitempty"`
+	Address   *Address  `json:"address,omitempty"`
+	// Attributes are merged into the person's; a nil value removes one.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+func String(s string) *string       { return &s }
+func Int32(i int32) *int32          { return &i }
+func Strings(s ...string) *[]string { return &s }
+
+// Metadata describes a page. CurrentPage and FirstPage count from 1,
+// LastPage is the number of pages.
+type Metadata struct {
+	CurrentPage  int32 `json:"currentPage"`
+	PageSize     int32 `json:"pageSize"`
+	FirstPage    int32 `json:"firstPage"`
+	LastPage     int32 `json:"lastPage"`
+	TotalRecords int32 `json:"totalRecords"`
+}
+
+type Page struct {
+	Meta    Metadata `json:"meta"`
+	Persons []Person `json:"persons"`
+}
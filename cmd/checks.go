@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	configpkg "github.com/lafetz/assessment/internal/config"
+	"github.com/lafetz/assessment/internal/photo"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web/cors"
+)
+
+// configChecks let the components reject the settings they understand, so
+// that configpkg.Load lists their problems along with the others.
+var configChecks = []configpkg.Check{
+	func(c *configpkg.Config) []error {
+		_, err := cors.New(corsConfig(c.CORS))
+		return problems(err)
+	},
+	func(c *configpkg.Config) []error {
+		if err := repository.CheckUniqueFields(c.Storage.UniqueFields...); err != nil {
+			return problems(fmt.Errorf("storage.uniqueFields: %w", err))
+		}
+		return nil
+	},
+	func(c *configpkg.Config) []error {
+		_, err := photo.New(photoConfig(c.Photos), nil, nil, nil)
+		return problems(err)
+	},
+	func(c *configpkg.Config) []error {
+		if _, err := hobbyCatalog(c.Hobbies); err != nil {
+			return problems(fmt.Errorf("hobbies: %w", err))
+		}
+		return nil
+	},
+}
+
+func problems(err error) []error {
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
+
+func corsConfig(cfg configpkg.CORS) cors.Config {
+	return cors.Config{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		WebSocketOrigins: cfg.WebSocketOrigins,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+}
+
+func photoConfig(cfg configpkg.Photos) photo.Config {
+	return photo.Config{
+		MaxBytes:     int64(cfg.MaxBytes),
+		MaxDimension: cfg.MaxDimension,
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	configpkg "github.com/lafetz/assessment/internal/config"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+type catalogEntry struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Category string   `json:"category"`
+}
+
+// hobbyCatalog loads the configured catalog file, or the built-in catalog
+// when none is set.
+func hobbyCatalog(cfg configpkg.Hobbies) (*domain.HobbyCatalog, error) {
+	if cfg.CatalogFile == "" {
+		return domain.NewHobbyCatalog(domain.DefaultHobbies)
+	}
+	data, err := os.ReadFile(cfg.CatalogFile)
+	if err != nil {
+		return nil, err
+	}
+	var entries []catalogEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.CatalogFile, err)
+	}
+	hobbies := make([]domain.Hobby, len(entries))
+	for i, e := range entries {
+		hobbies[i] = domain.Hobby{Name: e.Name, Aliases: e.Aliases, Category: e.Category}
+	}
+	return domain.NewHobbyCatalog(hobbies)
+}
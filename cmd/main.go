@@ -1,28 +1,210 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+
 	"github.com/go-playground/validator/v10"
-	"github.com/lafetz/assessment/internal/config"
+	configpkg "github.com/lafetz/assessment/internal/config"
 	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/events"
+	"github.com/lafetz/assessment/internal/health"
 	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/metrics"
+	"github.com/lafetz/assessment/internal/outbox"
+	"github.com/lafetz/assessment/internal/photo"
 	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/tracing"
 
 	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/certs"
+	"github.com/lafetz/assessment/internal/web/cors"
+	"github.com/lafetz/assessment/internal/web/idempotency"
 	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/lafetz/assessment/internal/webhook"
 )
 
 func main() {
-	config := config.NewConfig()
-	logger := customlogger.NewLogger(config.LogLevel, config.Env)
-	repo := repository.NewRepository()
-	repo.SeedData()
-	personSvc := person.NewPersonSvc(repo)
+	config, err := configpkg.Load(os.Args[1:], configChecks...)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(config.Log.SlogLevel())
+	logger := customlogger.NewLogger(logLevel, config.Env)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    config.Tracing.Exporter,
+		Endpoint:    config.Tracing.Endpoint,
+		Insecure:    config.Tracing.Insecure,
+		ServiceName: config.Tracing.ServiceName,
+		SampleRatio: config.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
+	repo := repository.NewRepository(repository.WithUniqueIndexes(config.Storage.UniqueFields...))
+	checks := health.New(health.DefaultCheckTimeout)
+	checks.Register("repository", health.CheckerFunc(repo.Ping))
+	appMetrics := metrics.New()
+	appMetrics.RegisterPersonsTotal(repo.Count)
+	eventBus := events.NewBus(events.DefaultBufferSize)
+	webhookNetworks := make([]netip.Prefix, len(config.Webhooks.AllowedNetworks))
+	for i, network := range config.Webhooks.AllowedNetworks {
+		if webhookNetworks[i], err = netip.ParsePrefix(network); err != nil {
+			logger.Error("invalid webhook configuration", "error", err)
+			os.Exit(1)
+		}
+	}
+	webhooks := webhook.New(webhook.Config{
+		MaxAttempts:     config.Webhooks.MaxAttempts,
+		MinBackoff:      config.Webhooks.MinBackoff,
+		MaxBackoff:      config.Webhooks.MaxBackoff,
+		Timeout:         config.Webhooks.Timeout,
+		AllowedNetworks: webhookNetworks,
+	}, logger)
+	photoStore, err := photo.NewFileStore(config.Photos.Dir)
+	if err != nil {
+		logger.Error("invalid photo storage", "error", err)
+		os.Exit(1)
+	}
+	photos, err := photo.New(photoConfig(config.Photos), photoStore, repo, logger)
+	if err != nil {
+		logger.Error("invalid photo configuration", "error", err)
+		os.Exit(1)
+	}
+	svcOpts := []person.Option{
+		person.WithPublisher(webhooks),
+		person.WithPublisher(eventBus),
+		person.WithPublisher(photos),
+	}
+	hobbies, err := hobbyCatalog(config.Hobbies)
+	if err != nil {
+		logger.Error("invalid hobby catalog", "error", err)
+		os.Exit(1)
+	}
+	svcOpts = append(svcOpts, person.WithHobbyCatalog(hobbies))
+	seeded := health.NewFlag("seeding in progress")
+	go func() {
+		if config.Storage.Seed {
+			repo.SeedData(hobbies)
+		}
+		seeded.Set()
+	}()
+	checks.Register("seed", seeded)
+	sinks, err := outboxSinks(config.Outbox, logger)
+	if err != nil {
+		logger.Error("invalid outbox configuration", "error", err)
+		os.Exit(1)
+	}
+	var relay *outbox.Relay
+	if len(sinks) > 0 {
+		outboxStore := tracing.NewOutbox(metrics.NewOutbox(repo, appMetrics))
+		relay = outbox.NewRelay(outboxStore, sinks, outbox.Config{Source: config.Outbox.Source}, logger)
+		svcOpts = append(svcOpts, person.WithOutbox(outboxStore), person.WithPublisher(relay))
+	}
+	personSvc := tracing.NewPersonSvc(person.NewPersonSvc(
+		tracing.NewRepository(metrics.NewRepository(repo, appMetrics)),
+		svcOpts...,
+	))
+	groupSvc := tracing.NewGroupSvc(person.NewGroupSvc(
+		tracing.NewGroupRepository(metrics.NewGroupRepository(repo, appMetrics)),
+	))
 	val := validator.New()
-	custonmVal := customvalidator.NewCustomValidator(val)
-	web := web.NewApp(config.Port, logger, personSvc, custonmVal)
+	custonmVal := customvalidator.NewCustomValidator(val, customvalidator.WithFailureObserver(appMetrics.ValidationFailed))
+	corsPolicy, err := cors.New(corsConfig(config.CORS))
+	if err != nil {
+		logger.Error("invalid cors configuration", "error", err)
+		os.Exit(1)
+	}
+	if config.Admin.Token == "" {
+		logger.Warn("admin endpoints are not authenticated; set ADMIN_TOKEN to protect them")
+	}
+	idempotencyStore := idempotency.New(config.Idempotency.TTL, idempotency.WithScope(web.RequestTenant))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go webhooks.Run(ctx)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		if relay != nil {
+			relay.Run(ctx)
+		}
+	}()
+	opts := []web.Option{
+		web.WithCORS(corsPolicy),
+		web.WithMetrics(appMetrics),
+		web.WithHealth(checks),
+		web.WithDrainDelay(config.Server.DrainDelay),
+		web.WithAccessLogSampling(config.Log.AccessLogSampling),
+		web.WithTimeouts(web.Timeouts{
+			Read:     config.Server.ReadTimeout,
+			Write:    config.Server.WriteTimeout,
+			Idle:     config.Server.IdleTimeout,
+			Shutdown: config.Server.ShutdownTimeout,
+		}),
+		web.WithHTTP2(config.Server.HTTP2),
+		web.WithH2C(config.Server.H2C),
+		web.WithWebhooks(webhooks),
+		web.WithGroups(groupSvc),
+		web.WithPhotos(photos),
+		web.WithEvents(eventBus),
+		web.WithIdempotency(idempotencyStore),
+		web.WithAdminToken(config.Admin.Token),
+	}
+	if config.Server.TLS.Enabled() {
+		reloader, err := certs.NewReloader(config.Server.TLS.CertFile, config.Server.TLS.KeyFile, logger)
+		if err != nil {
+			logger.Error("invalid tls configuration", "error", err)
+			os.Exit(1)
+		}
+		tlsConfig, err := certs.ServerConfig(certs.Config{
+			ClientCAFile: config.Server.TLS.ClientCAFile,
+			ClientAuth:   config.Server.TLS.ClientAuth,
+		}, reloader)
+		if err != nil {
+			logger.Error("invalid tls configuration", "error", err)
+			os.Exit(1)
+		}
+		go reloader.Run(ctx, certs.DefaultReloadInterval)
+		opts = append(opts, web.WithTLS(tlsConfig))
+	}
+	web := web.NewApp(config.Server.Port, logger, personSvc, custonmVal, opts...)
+	watcher := configpkg.NewWatcher(config, os.Args[1:], configpkg.DefaultWatchInterval, logger, func(next *configpkg.Config) error {
+		// Only the origins can be rejected; they are swapped together
+		// before anything else changes.
+		if err := corsPolicy.SetOrigins(next.CORS.AllowedOrigins, next.CORS.WebSocketOrigins); err != nil {
+			return err
+		}
+		logLevel.Set(next.Log.SlogLevel())
+		idempotencyStore.SetTTL(next.Idempotency.TTL)
+		web.SetAccessLogSampling(next.Log.AccessLogSampling)
+		return nil
+	}, configChecks...)
+	go watcher.Run(ctx)
+
 	logger.Info("running web server")
-	err := web.Run()
+	err = web.Run()
 	if err != nil {
 		logger.Error("web server error", "error", err)
 	}
+	// Stop the relay before closing its sinks, which flushes what they
+	// buffer.
+	cancel()
+	<-relayDone
+	closeSinks(sinks, logger)
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	configpkg "github.com/lafetz/assessment/internal/config"
+	"github.com/lafetz/assessment/internal/outbox"
+)
+
+// outboxSinks opens the sinks named in the configuration.
+func outboxSinks(cfg configpkg.Outbox, logger *slog.Logger) ([]outbox.Sink, error) {
+	sinks := make([]outbox.Sink, 0, len(cfg.Sinks))
+	for _, name := range cfg.Sinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(logger))
+		case "file":
+			sink, err := outbox.NewFileSink(cfg.File)
+			if err != nil {
+				return nil, fmt.Errorf("file sink: %w", err)
+			}
+			sinks = append(sinks, sink)
+		case "nats":
+			sink, err := outbox.NewNATSSink(cfg.NATSURL, cfg.NATSSubject)
+			if err != nil {
+				return nil, fmt.Errorf("nats sink: %w", err)
+			}
+			sinks = append(sinks, sink)
+		case "kafka":
+			sinks = append(sinks, outbox.NewKafkaSink(cfg.KafkaBrokers, cfg.KafkaTopic))
+		}
+	}
+	return sinks, nil
+}
+
+// closeSinks flushes and closes the sinks that hold connections or files.
+// The relay must have stopped sending to them.
+func closeSinks(sinks []outbox.Sink, logger *slog.Logger) {
+	for _, sink := range sinks {
+		c, ok := sink.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			logger.Error("failed to close outbox sink", "sink", sink.Name(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/client"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const listAllPageSize = 100
+
+func listAll(ctx context.Context, c *client.Client) ([]client.Person, error) {
+	var persons []client.Person
+	it := c.All(ctx, listAllPageSize)
+	for it.Next() {
+		persons = append(persons, it.Person())
+	}
+	return persons, it.Err()
+}
+
+// completePersonIDs offers the IDs of stored persons, described by name.
+func completePersonIDs(opts *options) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		c, err := opts.client()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		persons, err := listAll(cmd.Context(), c)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		ids := make([]string, 0, len(persons))
+		for _, p := range persons {
+			ids = append(ids, p.ID.String()+"\t"+p.Name)
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func parseIDs(args []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(args))
+	for i, arg := range args {
+		id, err := uuid.Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid person ID %q", arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func newListCmd(opts *options) *cobra.Command {
+	var page, size int
+	var all bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List persons",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			if all {
+				persons, err := listAll(cmd.Context(), c)
+				if err != nil {
+					return err
+				}
+				return printPersons(cmd.OutOrStdout(), opts.output, persons)
+			}
+			resp, err := c.List(cmd.Context(), page, size)
+			if err != nil {
+				return err
+			}
+			return printPersons(cmd.OutOrStdout(), opts.output, resp.Persons)
+		},
+	}
+	cmd.Flags().IntVar(&page, "page", 0, "page number, starting at 0")
+	cmd.Flags().IntVar(&size, "size", 10, "page size")
+	cmd.Flags().BoolVar(&all, "all", false, "list every page")
+	return cmd
+}
+
+func newGetCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "get ID...",
+		Short:             "Show persons by ID",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completePersonIDs(opts),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			persons := make([]client.Person, 0, len(ids))
+			for _, id := range ids {
+				p, err := c.Get(cmd.Context(), id)
+				if err != nil {
+					return fmt.Errorf("getting %s: %w", id, err)
+				}
+				persons = append(persons, p)
+			}
+			return printPersons(cmd.OutOrStdout(), opts.output, persons)
+		},
+	}
+}
+
+func newCreateCmd(opts *options) *cobra.Command {
+	var in client.PersonInput
+	cmd := &cobra.Command{
+		Use:   "create --name NAME (--age AGE | --birth-date YYYY-MM-DD) --hobby HOBBY...",
+		Short: "Create a person",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			p, err := c.Create(cmd.Context(), in)
+			if err != nil {
+				return err
+			}
+			return printPersons(cmd.OutOrStdout(), opts.output, []client.Person{p})
+		},
+	}
+	cmd.Flags().StringVar(&in.Name, "name", "", "name of the person")
+	cmd.Flags().Int32Var(&in.Age, "age", 0, "age of the person")
+	cmd.Flags().StringSliceVar(&in.Hobbies, "hobby", nil, "hobby, repeat or comma separate for several")
+	cmd.Flags().StringVar(&in.Email, "email", "", "email address")
+	cmd.Flags().StringVar(&in.Phone, "phone", "", "phone number in E.164 format, e.g. +14155552671")
+	cmd.Flags().StringVar(&in.BirthDate, "birth-date", "", "birth date as YYYY-MM-DD, the age is derived from it")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("hobby")
+	cmd.MarkFlagsOneRequired("age", "birth-date")
+	return cmd
+}
+
+func newUpdateCmd(opts *options) *cobra.Command {
+	var name string
+	var age int32
+	var hobbies []string
+	var email, phone, birthDate string
+	cmd := &cobra.Command{
+		Use:               "update ID [--name NAME] [--age AGE] [--hobby HOBBY...] [--email EMAIL] [--phone PHONE] [--birth-date DATE]",
+		Short:             "Update a person, keeping fields that are not given",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completePersonIDs(opts),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			var patch client.PersonPatch
+			if cmd.Flags().Changed("name") {
+				patch.Name = &name
+			}
+			if cmd.Flags().Changed("age") {
+				patch.Age = &age
+			}
+			if cmd.Flags().Changed("hobby") {
+				patch.Hobbies = &hobbies
+			}
+			if cmd.Flags().Changed("email") {
+				patch.Email = &email
+			}
+			if cmd.Flags().Changed("phone") {
+				patch.Phone = &phone
+			}
+			if cmd.Flags().Changed("birth-date") {
+				patch.BirthDate = &birthDate
+			}
+			p, err := c.Patch(cmd.Context(), ids[0], patch)
+			if err != nil {
+				return err
+			}
+			return printPersons(cmd.OutOrStdout(), opts.output, []client.Person{p})
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "new name")
+	cmd.Flags().Int32Var(&age, "age", 0, "new age")
+	cmd.Flags().StringSliceVar(&hobbies, "hobby", nil, "new hobbies, replacing the current ones")
+	cmd.Flags().StringVar(&email, "email", "", "new email address")
+	cmd.Flags().StringVar(&phone, "phone", "", "new phone number in E.164 format")
+	cmd.Flags().StringVar(&birthDate, "birth-date", "", "new birth date as YYYY-MM-DD")
+	return cmd
+}
+
+func newDeleteCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete ID...",
+		Aliases:           []string{"rm"},
+		Short:             "Delete persons by ID",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completePersonIDs(opts),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if err := c.Delete(cmd.Context(), id); err != nil {
+					return fmt.Errorf("deleting %s: %w", id, err)
+				}
+				cmd.Printf("deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newImportCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create persons from a JSON or YAML list, - reads stdin",
+		Long:  "Create persons from a JSON or YAML list of persons with the keys of the JSON API, such as the output of export. IDs in the file are ignored and new ones are assigned.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var r io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			var in []client.PersonInput
+			if err := readYAML(r, &in); err != nil {
+				return fmt.Errorf("parsing %s: %w", args[0], err)
+			}
+
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			created := make([]client.Person, 0, len(in))
+			for i, p := range in {
+				person, err := c.Create(cmd.Context(), p)
+				if err != nil {
+					return fmt.Errorf("importing entry %d (%s): %w", i, p.Name, err)
+				}
+				created = append(created, person)
+			}
+			return printPersons(cmd.OutOrStdout(), opts.output, created)
+		},
+	}
+}
+
+func newExportCmd(opts *options) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write every person as JSON or YAML",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.output == outputTable {
+				opts.output = outputJSON
+			}
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			persons, err := listAll(cmd.Context(), c)
+			if err != nil {
+				return err
+			}
+			w := cmd.OutOrStdout()
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return printPersons(w, opts.output, persons)
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "write to file instead of stdout")
+	return cmd
+}
+
+func newReseedCmd(opts *options) *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "reseed",
+		Short: "Replace every person with the sample data set",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return errors.New("reseed deletes every person, pass --yes to confirm")
+			}
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			existing, err := listAll(cmd.Context(), c)
+			if err != nil {
+				return err
+			}
+			for _, p := range existing {
+				if err := c.Delete(cmd.Context(), p.ID); err != nil {
+					return fmt.Errorf("deleting %s: %w", p.ID, err)
+				}
+			}
+			seed := repository.SeedPersons()
+			for _, p := range seed {
+				if _, err := c.Create(cmd.Context(), client.PersonInput{Name: p.Name, Age: p.Age, Hobbies: p.Hobbies}); err != nil {
+					return fmt.Errorf("creating %s: %w", p.Name, err)
+				}
+			}
+			cmd.Printf("deleted %d persons, created %d\n", len(existing), len(seed))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&yes, "yes", false, "confirm deleting every person")
+	return cmd
+}
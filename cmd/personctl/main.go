@@ -0,0 +1,63 @@
+// Command personctl manages persons through a running Persons API.
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/lafetz/assessment/client"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	server  string
+	output  string
+	timeout time.Duration
+}
+
+func (o *options) client() (*client.Client, error) {
+	return client.New(o.server, client.WithHTTPClient(&http.Client{Timeout: o.timeout}))
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &options{}
+	server := os.Getenv("PERSONCTL_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+
+	root := &cobra.Command{
+		Use:           "personctl",
+		Short:         "Manage persons stored by the Persons API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVarP(&opts.server, "server", "s", server, "base URL of the Persons API (env PERSONCTL_SERVER)")
+	root.PersistentFlags().StringVarP(&opts.output, "output", "o", outputTable, "output format: table, json or yaml")
+	root.PersistentFlags().DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout for each API request")
+	_ = root.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return outputFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	root.AddCommand(
+		newListCmd(opts),
+		newGetCmd(opts),
+		newCreateCmd(opts),
+		newUpdateCmd(opts),
+		newDeleteCmd(opts),
+		newImportCmd(opts),
+		newExportCmd(opts),
+		newReseedCmd(opts),
+	)
+	return root
+}
+
+func main() {
+	root := newRootCmd()
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		root.PrintErrln("Error:", err)
+		os.Exit(1)
+	}
+}
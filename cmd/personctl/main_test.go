@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/client"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	personSvc := person.NewPersonSvc(repository.NewRepository())
+	app := web.NewApp(8080, slog.Default(), personSvc, customvalidator.NewCustomValidator(validator.New()))
+	server := httptest.NewServer(app.Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func run(t *testing.T, server *httptest.Server, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	root := newRootCmd()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(append([]string{"--server", server.URL}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestCreateGetDelete(t *testing.T) {
+	server := newServer(t)
+
+	out, err := run(t, server, "create", "--name", "Alice", "--age", "30", "--hobby", "chess,running", "-o", "json")
+	require.NoError(t, err)
+	var created []client.Person
+	require.NoError(t, json.Unmarshal([]byte(out), &created))
+	require.Len(t, created, 1)
+	assert.Equal(t, []string{"chess", "running"}, created[0].Hobbies)
+
+	out, err = run(t, server, "update", created[0].ID.String(), "--age", "31")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Alice")
+	assert.Contains(t, out, "31")
+
+	_, err = run(t, server, "delete", created[0].ID.String())
+	require.NoError(t, err)
+
+	_, err = run(t, server, "get", created[0].ID.String())
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestExportImport(t *testing.T) {
+	source, target := newServer(t), newServer(t)
+
+	_, err := run(t, source, "reseed")
+	assert.Error(t, err, "reseed must require --yes")
+	_, err = run(t, source, "reseed", "--yes")
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "persons.yaml")
+	_, err = run(t, source, "export", "-o", "yaml", "-f", file)
+	require.NoError(t, err)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "hobbies:")
+
+	_, err = run(t, target, "import", file)
+	require.NoError(t, err)
+
+	out, err := run(t, target, "list", "--all", "-o", "json")
+	require.NoError(t, err)
+	var imported []client.Person
+	require.NoError(t, json.Unmarshal([]byte(out), &imported))
+	assert.Len(t, imported, len(repository.SeedPersons()))
+}
+
+func TestExportImport_ProfileFields(t *testing.T) {
+	withAttributes := func() *httptest.Server {
+		personSvc := person.NewPersonSvc(repository.NewRepository())
+		_, err := personSvc.SetAttributeSchema(context.Background(), domain.AttributeSchema{AdditionalProperties: true})
+		require.NoError(t, err)
+		app := web.NewApp(8080, slog.Default(), personSvc, customvalidator.NewCustomValidator(validator.New()))
+		server := httptest.NewServer(app.Handler())
+		t.Cleanup(server.Close)
+		return server
+	}
+	source := withAttributes()
+
+	c, err := client.New(source.URL)
+	require.NoError(t, err)
+	want, err := c.Create(context.Background(), client.PersonInput{
+		Name:      "Alice",
+		Hobbies:   []string{"chess"},
+		Email:     "alice@example.com",
+		Phone:     "+14155550100",
+		BirthDate: "1990-04-01",
+		Address: &client.Address{
+			Street:     "1 Main St",
+			City:       "Springfield",
+			PostalCode: "12345",
+			Region:     "IL",
+			Country:    "US",
+		},
+		Attributes: map[string]any{"team": "core", "badge": float64(42), "remote": true},
+	})
+	require.NoError(t, err)
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			target := withAttributes()
+			file := filepath.Join(t.TempDir(), "persons."+format)
+			_, err := run(t, source, "export", "-o", format, "-f", file)
+			require.NoError(t, err)
+			out, err := run(t, target, "import", file, "-o", "json")
+			require.NoError(t, err)
+
+			var imported []client.Person
+			require.NoError(t, json.Unmarshal([]byte(out), &imported))
+			require.Len(t, imported, 1)
+			got := imported[0]
+			got.ID = want.ID
+			assert.Equal(t, want, got)
+		})
+	}
+}
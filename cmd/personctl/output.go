@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lafetz/assessment/client"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+// printPersons writes persons in the requested format. JSON and YAML use the
+// API's field names so exports can be imported again.
+func printPersons(w io.Writer, format string, persons []client.Person) error {
+	switch format {
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tAGE\tHOBBIES")
+		for _, p := range persons {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", p.ID, p.Name, p.Age, strings.Join(p.Hobbies, ", "))
+		}
+		return tw.Flush()
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(persons)
+	case outputYAML:
+		return writeYAML(w, persons)
+	default:
+		return fmt.Errorf("unknown output format %q, use one of %s", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+// readYAML decodes YAML, or JSON, into v through JSON so that the keys
+// match the JSON tags, as writeYAML writes them. An empty input leaves v
+// untouched.
+func readYAML(r io.Reader, v any) error {
+	var generic any
+	if err := yaml.NewDecoder(r).Decode(&generic); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeYAML round-trips v through JSON so the YAML keys match the JSON tags.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -15,9 +15,53 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/persons": {
+        "/api/v1/admin/attribute-schema": {
             "get": {
-                "description": "Retrieve a list of persons with pagination support",
+                "description": "Get the attribute schema that the tenant's writes of custom attributes are validated against. Persons are shared by all tenants; only the schema is per tenant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the attribute schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, default when absent",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token, required when one is configured",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttributeSchema"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or wrong admin token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The tenant has no schema",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the attribute schema that the tenant's writes of custom attributes are validated against. Stored persons are checked when they are next written. Persons are shared by all tenants; only the schema is per tenant.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,9 +69,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Persons"
+                    "Admin"
                 ],
-                "summary": "Get all persons",
+                "summary": "Set the attribute schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, default when absent",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token, required when one is configured",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Attribute schema: an object of string, integer, number or boolean properties",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttributeSchema"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttributeSchema"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON or unsupported keyword",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or wrong admin token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid schema",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups": {
+            "get": {
+                "description": "List groups ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "List groups",
                 "parameters": [
                     {
                         "type": "integer",
@@ -48,19 +153,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetPersonsResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "intrnal server error",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.GetGroupsResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Add a new person to the database",
                 "consumes": [
                     "application/json"
                 ],
@@ -68,17 +166,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Persons"
+                    "Groups"
                 ],
-                "summary": "Add a new person",
+                "summary": "Create a group",
                 "parameters": [
                     {
-                        "description": "Person data",
-                        "name": "person",
+                        "description": "Group",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePerson"
+                            "$ref": "#/definitions/dto.CreateGroup"
                         }
                     }
                 ],
@@ -86,7 +184,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Person"
+                            "$ref": "#/definitions/dto.JSONGroup"
                         }
                     },
                     "400": {
@@ -104,24 +202,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/persons/{personId}": {
+        "/api/v1/groups/{groupId}": {
             "get": {
-                "description": "Retrieve a person by their ID",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Persons"
+                    "Groups"
                 ],
-                "summary": "Get person by ID",
+                "summary": "Get a group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the person",
-                        "name": "personId",
+                        "description": "ID of the group",
+                        "name": "groupId",
                         "in": "path",
                         "required": true
                     }
@@ -130,11 +224,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Person"
+                            "$ref": "#/definitions/dto.JSONGroup"
                         }
                     },
                     "404": {
-                        "description": "Person not found",
+                        "description": "Group not found",
                         "schema": {
                             "type": "string"
                         }
@@ -142,7 +236,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a person by their ID",
+                "description": "Replace the name and description of a group",
                 "consumes": [
                     "application/json"
                 ],
@@ -150,24 +244,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Persons"
+                    "Groups"
                 ],
-                "summary": "Update an existing person",
+                "summary": "Update a group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the person",
-                        "name": "personId",
+                        "description": "ID of the group",
+                        "name": "groupId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated person data",
-                        "name": "person",
+                        "description": "Group",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePerson"
+                            "$ref": "#/definitions/dto.CreateGroup"
                         }
                     }
                 ],
@@ -175,7 +269,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Person"
+                            "$ref": "#/definitions/dto.JSONGroup"
                         }
                     },
                     "400": {
@@ -185,7 +279,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Person not found",
+                        "description": "Group not found",
                         "schema": {
                             "type": "string"
                         }
@@ -199,22 +293,16 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a person by their ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete a group and its memberships; the members are kept",
                 "tags": [
-                    "Persons"
+                    "Groups"
                 ],
-                "summary": "Delete a person",
+                "summary": "Delete a group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the person",
-                        "name": "personId",
+                        "description": "ID of the group",
+                        "name": "groupId",
                         "in": "path",
                         "required": true
                     }
@@ -224,80 +312,2149 @@ const docTemplate = `{
                         "description": "No Content"
                     },
                     "404": {
-                        "description": "Person not found",
+                        "description": "Group not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "customvalidator.ValidationErrorResponse": {
-            "type": "object",
-            "properties": {
-                "errors": {},
-                "statusCode": {
-                    "type": "integer"
-                }
-            }
         },
-        "domain.Person": {
-            "type": "object",
-            "properties": {
-                "age": {
-                    "type": "integer"
-                },
-                "hobbies": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/api/v1/groups/{groupId}/members": {
+            "get": {
+                "description": "List the members of a group ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "List the members of a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the group",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetMembersResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
                 }
-            }
-        },
-        "dto.CreatePerson": {
-            "type": "object",
-            "required": [
-                "age",
-                "hobbies",
-                "name"
-            ],
-            "properties": {
-                "age": {
-                    "type": "integer",
-                    "maximum": 120,
-                    "minimum": 0
-                },
-                "hobbies": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Add a person to a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the group",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Person and role, member by default",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group or person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Person is already a member",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
                     }
-                },
-                "name": {
-                    "type": "string"
                 }
             }
         },
-        "dto.GetPersonsResponse": {
-            "type": "object",
-            "properties": {
+        "/api/v1/groups/{groupId}/members/{personId}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Change the role of a member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the group",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the member",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not a member",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Remove a person from a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the group",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the member",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not a member",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/hobbies": {
+            "get": {
+                "description": "List catalogued and stored hobbies whose name, an alias, or a word of them starts with prefix, the ones most persons have first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hobbies"
+                ],
+                "summary": "Autocomplete hobbies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the hobby, ignoring case and accents",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of hobbies",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetHobbiesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons": {
+            "get": {
+                "description": "Retrieve a list of persons with pagination support. Query parameters named attributes.{name} only keep persons whose attribute has that value, e.g. attributes.department=sales or attributes.badge=42.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Get all persons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPersonsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "intrnal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new person to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Add a new person",
+                "parameters": [
+                    {
+                        "description": "Person data",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePerson"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPerson"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is in progress",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed, or Idempotency-Key reused with a different body",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/events": {
+            "get": {
+                "description": "Server-Sent Events stream of person.created, person.updated and person.deleted events. Event IDs increase monotonically; reconnecting with Last-Event-ID replays missed events from a bounded buffer. A \"resync\" event means some events could not be replayed and clients should reload the collection. Comment lines are sent as heartbeats.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Stream person changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated event types to receive",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this person",
+                        "name": "personId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event ID, like the Last-Event-ID header",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event ID",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPersonEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/stats": {
+            "get": {
+                "description": "Count persons, summarize their ages and list the most common hobbies and pairs of hobbies held together. Query parameters named attributes.{name} only count persons whose attribute has that value, as in the person list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Aggregate statistics of persons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "18,25,35,45,55,65",
+                        "description": "Comma separated ascending lower bounds of the age buckets after the first",
+                        "name": "buckets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "25,50,75,90,99",
+                        "description": "Comma separated age percentiles, each in (0, 100]",
+                        "name": "percentiles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of hobbies",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of hobby pairs",
+                        "name": "pairs",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}": {
+            "get": {
+                "description": "Retrieve a person by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Get person by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPerson"
+                        }
+                    },
+                    "308": {
+                        "description": "Person was merged; Location names the survivor"
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a person by their ID. email, phone, birthDate, address and attributes keep their value when they are not sent; send null to remove one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Update an existing person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated person data",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePerson"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPerson"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a person by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Delete a person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Apply a JSON merge patch to a person; fields that are not sent keep their value. null removes email, phone, birthDate, address or attributes; name, age and hobbies can not be null",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Partially update a person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchPerson"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPerson"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/duplicates": {
+            "get": {
+                "description": "Score every other person on normalized name, age and hobbies and return the best matches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Find possible duplicates of a person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.75,
+                        "description": "Lowest score returned, between 0 and 1",
+                        "name": "minScore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of candidates",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetDuplicatesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid minScore or limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/friends-of-friends": {
+            "get": {
+                "description": "List the friends of this person's friends who are not its friends yet, most mutual friends first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relationships"
+                ],
+                "summary": "Suggest friends of friends",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FriendsOfFriendsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/groups": {
+            "get": {
+                "description": "List the groups a person belongs to, with its role, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "List the groups of a person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPersonGroupsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/merge": {
+            "post": {
+                "description": "Fold the source person into this one field by field, delete the source and redirect reads of it here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Merge a person into another one",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person that survives",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Source person and per-field strategies",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergePerson"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPerson"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A kept value is used by another person",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid merge",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/merges": {
+            "get": {
+                "description": "Show the records merged into this person, oldest first, as they were before the merge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "List the merges into a person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetMergesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/path/{otherId}": {
+            "get": {
+                "description": "Find a path with the fewest relationships between two persons",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relationships"
+                ],
+                "summary": "Find how two persons are connected",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the first person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last person",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated relationship types the path may follow, all by default",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Only follow relationships from each person to the next",
+                        "name": "directed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 6,
+                        "description": "Maximum number of relationships on the path",
+                        "name": "maxDepth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PathResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid types, directed or maxDepth",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found or no path",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/photo": {
+            "get": {
+                "description": "Get the photo of a person, or one of its thumbnails. Responses carry an ETag and Last-Modified for conditional requests.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "Photos"
+                ],
+                "summary": "Get a profile photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "small",
+                            "medium",
+                            "large",
+                            "original"
+                        ],
+                        "type": "string",
+                        "default": "original",
+                        "description": "Rendition",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Unknown size",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The person has no photo",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the photo of a person with a JPEG, PNG or WebP image sent in the photo field of a multipart form. The type is sniffed from the content. The image is turned upright, stripped of EXIF metadata and stored with small, medium and large thumbnails.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Photos"
+                ],
+                "summary": "Upload a profile photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "JPEG, PNG or WebP image",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "No photo field",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Photo too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Not a multipart form, or not a JPEG, PNG or WebP image",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Corrupt image or dimensions out of bounds",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/relationships": {
+            "get": {
+                "description": "List the relationships from and to this person, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relationships"
+                ],
+                "summary": "List the relationships of a person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "friend",
+                            "spouse",
+                            "parent",
+                            "manager"
+                        ],
+                        "type": "string",
+                        "description": "Only relationships of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "out",
+                            "in",
+                            "both"
+                        ],
+                        "type": "string",
+                        "default": "both",
+                        "description": "out: from the person, in: to the person",
+                        "name": "direction",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetRelationshipsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid type or direction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Make this person the friend, spouse, parent or manager of another person",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relationships"
+                ],
+                "summary": "Relate a person to another one",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Related person and type",
+                        "name": "relationship",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRelationship"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONRelationship"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Relationship already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Person related to itself",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/relationships/{type}/{otherId}": {
+            "delete": {
+                "description": "Delete the relationship of the given type from this person to another one",
+                "tags": [
+                    "Relationships"
+                ],
+                "summary": "Delete a relationship",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "friend",
+                            "spouse",
+                            "parent",
+                            "manager"
+                        ],
+                        "type": "string",
+                        "description": "Relationship type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the related person",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Relationship not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/persons/{personId}/similar": {
+            "get": {
+                "description": "Rank the persons sharing hobbies with this one by TF-IDF cosine similarity of their hobbies, so rare shared hobbies count more. Ties go to the person closest in age.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Recommend persons with similar hobbies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the person",
+                        "name": "personId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of persons",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetSimilarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.JSONWebhook"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a URL receiving person events. Deliveries are signed with the secret in the X-Webhook-Signature header as t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256 of \"\u003ct\u003e.\u003cbody\u003e\"\u003e. The secret is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Subscribe a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONWebhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed, or the URL names a loopback, link-local or private address",
+                        "schema": {
+                            "$ref": "#/definitions/customvalidator.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/dead-letters": {
+            "get": {
+                "description": "Deliveries that failed every attempt",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List dead-lettered deliveries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.JSONDelivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/dead-letters/{deliveryId}/redeliver": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Retry a dead-lettered delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the delivery",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is not dead-lettered",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{webhookId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the webhook",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JSONWebhook"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the webhook",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{webhookId}/deliveries": {
+            "get": {
+                "description": "Newest first, with every attempt, for debugging receivers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List recent deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the webhook",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.JSONDelivery"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ws": {
+            "get": {
+                "description": "Upgrade to a WebSocket exchanging JSON messages. Send {\"type\":\"subscribe\",\"id\":\"s1\",\"personIds\":[\"...\"],\"filter\":\"type == person.updated \u0026\u0026 age \u003e= 18\"} and {\"type\":\"unsubscribe\",\"id\":\"s1\"}; every matching change arrives once as {\"type\":\"event\",\"subscriptions\":[\"s1\"],\"event\":{...}}. Filters compare type, personId, name, age and hobby with == != \u003c \u003c= \u003e \u003e= joined by \u0026\u0026 and ||. Clients that fall behind are disconnected with close code 1013 and should reconnect.",
+                "tags": [
+                    "Persons"
+                ],
+                "summary": "Subscribe to person changes over a WebSocket",
+                "parameters": [
+                    {
+                        "description": "Messages sent after the upgrade",
+                        "name": "message",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WSClientMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WSServerMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "customvalidator.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {},
+                "statusCode": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AddMember": {
+            "type": "object",
+            "required": [
+                "personId"
+            ],
+            "properties": {
+                "personId": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role defaults to member.",
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "lead",
+                        "member"
+                    ],
+                    "example": "member"
+                }
+            }
+        },
+        "dto.AttributeSchema": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "additionalProperties": {
+                    "description": "AdditionalProperties defaults to true.",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.JSONAttributeProperty"
+                    }
+                },
+                "required": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "object"
+                }
+            }
+        },
+        "dto.CreateGroup": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.CreatePerson": {
+            "type": "object",
+            "required": [
+                "hobbies",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/dto.JSONAddress"
+                },
+                "age": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 0
+                },
+                "attributes": {
+                    "description": "Attributes are checked against the tenant's attribute schema.",
+                    "type": "object"
+                },
+                "birthDate": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "hobbies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateRelationship": {
+            "type": "object",
+            "required": [
+                "toId",
+                "type"
+            ],
+            "properties": {
+                "toId": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "friend",
+                        "spouse",
+                        "parent",
+                        "manager"
+                    ],
+                    "example": "friend"
+                }
+            }
+        },
+        "dto.CreateWebhook": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Events filters the delivered events; empty subscribes to all of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Secret signs deliveries; one is generated when empty.",
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FriendsOfFriendsResponse": {
+            "type": "object",
+            "properties": {
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONSuggestion"
+                    }
+                }
+            }
+        },
+        "dto.GetDuplicatesResponse": {
+            "type": "object",
+            "properties": {
+                "duplicates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONDuplicate"
+                    }
+                }
+            }
+        },
+        "dto.GetGroupsResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONGroup"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.JSONMetadata"
+                }
+            }
+        },
+        "dto.GetHobbiesResponse": {
+            "type": "object",
+            "properties": {
+                "hobbies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONHobby"
+                    }
+                }
+            }
+        },
+        "dto.GetMembersResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONMember"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.JSONMetadata"
+                }
+            }
+        },
+        "dto.GetMergesResponse": {
+            "type": "object",
+            "properties": {
+                "merges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONMerge"
+                    }
+                }
+            }
+        },
+        "dto.GetPersonGroupsResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONPersonGroup"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.JSONMetadata"
+                }
+            }
+        },
+        "dto.GetPersonsResponse": {
+            "type": "object",
+            "properties": {
                 "meta": {
                     "$ref": "#/definitions/dto.JSONMetadata"
                 },
                 "persons": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.JSONPerson"
+                        "$ref": "#/definitions/dto.JSONPerson"
+                    }
+                }
+            }
+        },
+        "dto.GetRelationshipsResponse": {
+            "type": "object",
+            "properties": {
+                "relationships": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONRelationship"
+                    }
+                }
+            }
+        },
+        "dto.GetSimilarResponse": {
+            "type": "object",
+            "properties": {
+                "similar": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONSimilarPerson"
+                    }
+                }
+            }
+        },
+        "dto.JSONAddress": {
+            "type": "object",
+            "required": [
+                "city",
+                "country",
+                "postalCode",
+                "street"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "DE"
+                },
+                "postalCode": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONAgeBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "max": {
+                    "description": "Max is exclusive and absent for the last bucket.",
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JSONAgeStats": {
+            "type": "object",
+            "properties": {
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONAgeBucket"
+                    }
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "percentiles": {
+                    "description": "Percentiles maps \"p90\" and the like to ages.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "dto.JSONAttributeProperty": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maxLength": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "integer",
+                        "number",
+                        "boolean"
+                    ],
+                    "example": "string"
+                }
+            }
+        },
+        "dto.JSONDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONDeliveryAttempt"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhookId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONDeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "durationMs": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JSONDuplicate": {
+            "type": "object",
+            "properties": {
+                "person": {
+                    "$ref": "#/definitions/dto.JSONPerson"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "scores": {
+                    "$ref": "#/definitions/dto.JSONDuplicateScores"
+                }
+            }
+        },
+        "dto.JSONDuplicateScores": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "number"
+                },
+                "hobbies": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.JSONGroup": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONHobby": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "persons": {
+                    "description": "Persons is the number of stored persons with the hobby.",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JSONHobbyCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "hobby": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONHobbyPair": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "hobbies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.JSONMember": {
+            "type": "object",
+            "properties": {
+                "joinedAt": {
+                    "type": "string"
+                },
+                "person": {
+                    "$ref": "#/definitions/dto.JSONPerson"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONMerge": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "mergedAt": {
+                    "type": "string"
+                },
+                "source": {
+                    "$ref": "#/definitions/dto.JSONPerson"
+                },
+                "strategies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
+                },
+                "targetId": {
+                    "type": "string"
                 }
             }
         },
@@ -324,9 +2481,22 @@ const docTemplate = `{
         "dto.JSONPerson": {
             "type": "object",
             "properties": {
+                "address": {
+                    "$ref": "#/definitions/dto.JSONAddress"
+                },
                 "age": {
                     "type": "integer"
                 },
+                "attributes": {
+                    "type": "object"
+                },
+                "birthDate": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
+                "email": {
+                    "type": "string"
+                },
                 "hobbies": {
                     "type": "array",
                     "items": {
@@ -338,6 +2508,306 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONPersonEvent": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "occurredAt": {
+                    "type": "string"
+                },
+                "person": {
+                    "$ref": "#/definitions/dto.JSONPerson"
+                },
+                "personId": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONPersonGroup": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "$ref": "#/definitions/dto.JSONGroup"
+                },
+                "joinedAt": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONPhoto": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "personId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "urls": {
+                    "description": "URLs maps the sizes of the photo to the paths serving them.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JSONRelationship": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "fromId": {
+                    "type": "string"
+                },
+                "toId": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JSONSimilarPerson": {
+            "type": "object",
+            "properties": {
+                "person": {
+                    "$ref": "#/definitions/dto.JSONPerson"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "sharedHobbies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.JSONSuggestion": {
+            "type": "object",
+            "properties": {
+                "mutualFriends": {
+                    "type": "integer"
+                },
+                "person": {
+                    "$ref": "#/definitions/dto.JSONPerson"
+                }
+            }
+        },
+        "dto.JSONWebhook": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret is only returned when the webhook is created.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MergePerson": {
+            "type": "object",
+            "required": [
+                "sourceId"
+            ],
+            "properties": {
+                "sourceId": {
+                    "type": "string"
+                },
+                "strategies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "hobbies": "union",
+                        "name": "keepTarget"
+                    }
+                }
+            }
+        },
+        "dto.PatchPerson": {
+            "type": "object",
+            "required": [
+                "hobbies"
+            ],
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/dto.JSONAddress"
+                },
+                "age": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 0
+                },
+                "attributes": {
+                    "description": "Attributes are checked against the tenant's attribute schema.",
+                    "type": "object"
+                },
+                "birthDate": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "hobbies": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PathResponse": {
+            "type": "object",
+            "properties": {
+                "length": {
+                    "type": "integer"
+                },
+                "persons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONPerson"
+                    }
+                },
+                "relationships": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONRelationship"
+                    }
+                }
+            }
+        },
+        "dto.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "ages": {
+                    "$ref": "#/definitions/dto.JSONAgeStats"
+                },
+                "hobbyPairs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONHobbyPair"
+                    }
+                },
+                "topHobbies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JSONHobbyCount"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UpdateMember": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "lead",
+                        "member"
+                    ],
+                    "example": "lead"
+                }
+            }
+        },
+        "dto.WSClientMessage": {
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "personIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WSServerMessage": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/dto.JSONPersonEvent"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         }
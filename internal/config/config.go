@@ -1,15 +1,25 @@
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
-	"strconv"
+	"path/filepath"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-const defaultPort = 8080
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+
+	StorageMemory = "memory"
+)
 
 var logLevels = map[string]slog.Level{
 	"debug": slog.LevelDebug,
@@ -18,49 +28,282 @@ var logLevels = map[string]slog.Level{
 	"error": slog.LevelError,
 }
 
+var ErrUnsupportedFile = errors.New("unsupported config file extension")
+
+type Server struct {
+	Port            int           `yaml:"port" toml:"port"`
+	ReadTimeout     time.Duration `yaml:"readTimeout" toml:"readTimeout"`
+	WriteTimeout    time.Duration `yaml:"writeTimeout" toml:"writeTimeout"`
+	IdleTimeout     time.Duration `yaml:"idleTimeout" toml:"idleTimeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout"`
+	// DrainDelay is how long the server keeps serving after failing
+	// readiness on shutdown.
+	DrainDelay time.Duration `yaml:"drainDelay" toml:"drainDelay"`
+	TLS        TLS           `yaml:"tls" toml:"tls"`
+	// HTTP2 enables HTTP/2 over TLS.
+	HTTP2 bool `yaml:"http2" toml:"http2"`
+	// H2C accepts HTTP/2 over cleartext when TLS is terminated by a proxy.
+	H2C bool `yaml:"h2c" toml:"h2c"`
+}
+
+// TLS enables HTTPS when CertFile and KeyFile are set. Rotated files are
+// picked up without a restart.
+type TLS struct {
+	CertFile string `yaml:"certFile" toml:"certFile"`
+	KeyFile  string `yaml:"keyFile" toml:"keyFile"`
+	// ClientAuth is none, optional or require; client certificates are
+	// verified against ClientCAFile.
+	ClientAuth   string `yaml:"clientAuth" toml:"clientAuth"`
+	ClientCAFile string `yaml:"clientCAFile" toml:"clientCAFile"`
+}
+
+// Enabled reports whether the server should serve HTTPS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+type Log struct {
+	Level string `yaml:"level" toml:"level"`
+	// AccessLogSampling maps "METHOD /route" to the fraction of successful
+	// requests written to the access log.
+	AccessLogSampling map[string]float64 `yaml:"accessLogSampling" toml:"accessLogSampling"`
+}
+
+// SlogLevel returns the slog level matching Level.
+func (l Log) SlogLevel() slog.Level {
+	return logLevels[l.Level]
+}
+
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowedOrigins" toml:"allowedOrigins"`
+	// WebSocketOrigins may open WebSockets. When empty, the explicit
+	// AllowedOrigins may; "*" never does.
+	WebSocketOrigins []string      `yaml:"webSocketOrigins" toml:"webSocketOrigins"`
+	AllowCredentials bool          `yaml:"allowCredentials" toml:"allowCredentials"`
+	MaxAge           time.Duration `yaml:"maxAge" toml:"maxAge"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`
+	Insecure    bool    `yaml:"insecure" toml:"insecure"`
+	ServiceName string  `yaml:"serviceName" toml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio" toml:"sampleRatio"`
+}
+
+// Webhooks controls delivery of person events to subscribed URLs.
+type Webhooks struct {
+	MaxAttempts int           `yaml:"maxAttempts" toml:"maxAttempts"`
+	MinBackoff  time.Duration `yaml:"minBackoff" toml:"minBackoff"`
+	MaxBackoff  time.Duration `yaml:"maxBackoff" toml:"maxBackoff"`
+	Timeout     time.Duration `yaml:"timeout" toml:"timeout"`
+	// AllowedNetworks are CIDRs webhooks may be delivered to although they
+	// are loopback, link-local or private, e.g. 127.0.0.0/8 in development.
+	AllowedNetworks []string `yaml:"allowedNetworks" toml:"allowedNetworks"`
+}
+
+// Idempotency controls replaying responses to POST requests retried with
+// the same Idempotency-Key.
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl" toml:"ttl"`
+}
+
+// Outbox relays stored person events as CloudEvents to the listed sinks:
+// log, file, nats and kafka. No sinks disables the outbox.
+type Outbox struct {
+	Sinks  []string `yaml:"sinks" toml:"sinks"`
+	Source string   `yaml:"source" toml:"source"`
+	// File receives one JSON event per line for the file sink.
+	File         string   `yaml:"file" toml:"file"`
+	NATSURL      string   `yaml:"natsURL" toml:"natsURL"`
+	NATSSubject  string   `yaml:"natsSubject" toml:"natsSubject"`
+	KafkaBrokers []string `yaml:"kafkaBrokers" toml:"kafkaBrokers"`
+	KafkaTopic   string   `yaml:"kafkaTopic" toml:"kafkaTopic"`
+}
+
+// Hobbies configures the catalog incoming hobbies are normalized against.
+type Hobbies struct {
+	// CatalogFile is a JSON array of {"name", "aliases", "category"}
+	// objects replacing the built-in catalog.
+	CatalogFile string `yaml:"catalogFile" toml:"catalogFile"`
+}
+
+// Photos configures profile photo uploads, stored as files under Dir.
+type Photos struct {
+	Dir string `yaml:"dir" toml:"dir"`
+	// MaxBytes bounds the size of an upload.
+	MaxBytes int `yaml:"maxBytes" toml:"maxBytes"`
+	// MaxDimension bounds the width and height of an upload in pixels.
+	MaxDimension int `yaml:"maxDimension" toml:"maxDimension"`
+}
+
+// Admin guards the admin endpoints, such as the attribute schemas.
+type Admin struct {
+	// Token must be sent as "Authorization: Bearer <token>" to the admin
+	// endpoints. Empty leaves them open.
+	Token string `yaml:"token" toml:"token" secret:"true"`
+}
+
+type Storage struct {
+	Backend string `yaml:"backend" toml:"backend"`
+	Seed    bool   `yaml:"seed" toml:"seed"`
+	// UniqueFields are person fields no two persons may share.
+	UniqueFields []string `yaml:"uniqueFields" toml:"uniqueFields"`
+}
+
+// Config is merged from, in increasing order of precedence, built-in
+// defaults, a YAML or TOML config file, environment variables and
+// command-line flags.
 type Config struct {
-	Port     int
-	LogLevel slog.Level
-	Env      string
+	Env      string   `yaml:"env" toml:"env"`
+	Server   Server   `yaml:"server" toml:"server"`
+	Log      Log      `yaml:"log" toml:"log"`
+	CORS     CORS     `yaml:"cors" toml:"cors"`
+	Tracing  Tracing  `yaml:"tracing" toml:"tracing"`
+	Storage  Storage  `yaml:"storage" toml:"storage"`
+	Webhooks Webhooks `yaml:"webhooks" toml:"webhooks"`
+	Outbox   Outbox   `yaml:"outbox" toml:"outbox"`
+	Hobbies  Hobbies  `yaml:"hobbies" toml:"hobbies"`
+	Photos   Photos   `yaml:"photos" toml:"photos"`
+	Admin    Admin    `yaml:"admin" toml:"admin"`
+	// Idempotency keys are kept for TTL after the first response.
+	Idempotency Idempotency `yaml:"idempotency" toml:"idempotency"`
+	// File is the config file the configuration was read from, if any.
+	File string `yaml:"-" toml:"-"`
 }
 
-func NewConfig() *Config {
-	err := godotenv.Load()
-	if err != nil {
-		fmt.Println("Error loading .env file")
+func Default() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Server: Server{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     time.Minute,
+			ShutdownTimeout: 5 * time.Second,
+			DrainDelay:      5 * time.Second,
+			TLS: TLS{
+				ClientAuth: "none",
+			},
+			HTTP2: true,
+		},
+		Log: Log{
+			Level:             "info",
+			AccessLogSampling: map[string]float64{},
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"*"},
+			MaxAge:         time.Hour,
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			ServiceName: "persons-api",
+			SampleRatio: 1,
+		},
+		Storage: Storage{
+			Backend:      StorageMemory,
+			Seed:         true,
+			UniqueFields: []string{"email"},
+		},
+		Webhooks: Webhooks{
+			MaxAttempts: 6,
+			MinBackoff:  time.Second,
+			MaxBackoff:  10 * time.Minute,
+			Timeout:     10 * time.Second,
+		},
+		Idempotency: Idempotency{
+			TTL: 24 * time.Hour,
+		},
+		Photos: Photos{
+			Dir:          "photos",
+			MaxBytes:     10 << 20,
+			MaxDimension: 4096,
+		},
+		Outbox: Outbox{
+			Sinks:        []string{},
+			Source:       "/persons-api",
+			File:         "outbox.jsonl",
+			NATSURL:      "nats://127.0.0.1:4222",
+			NATSSubject:  "persons",
+			KafkaBrokers: []string{"127.0.0.1:9092"},
+			KafkaTopic:   "persons.events",
+		},
 	}
-	portStr := os.Getenv("PORT")
-	port := defaultPort
-
-	if portStr != "" {
-		if p, err := strconv.Atoi(portStr); err == nil {
-			port = p
-		} else {
-			fmt.Printf("Invalid PORT value '%s', defaulting to %d\n", portStr, defaultPort)
-		}
-	} else {
-		fmt.Printf("PORT not set, defaulting to %d\n", defaultPort)
+}
+
+// Check returns one error per setting a component rejects. Checks let the
+// components judge the values they understand while Load still reports
+// every invalid setting at once.
+type Check func(*Config) []error
+
+// Load builds the configuration from args (usually os.Args[1:]), the
+// environment, an optional .env file and the config file named by -config
+// or CONFIG_FILE. Every invalid setting, as found by Validate and checks,
+// is reported in the returned error.
+func Load(args []string, checks ...Check) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading .env: %w", err)
 	}
 
-	logLevelStr := os.Getenv("LOG_LEVEL")
-	level, exists := logLevels[logLevelStr]
-	if !exists {
-		fmt.Printf("Invalid LOG_LEVEL '%s', defaulting to 'info'\n", logLevelStr)
-		level = logLevels["info"]
+	fs := flag.NewFlagSet("persons-api", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	for _, s := range settings {
+		fs.String(s.flag, "", s.usage+" (env "+s.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
 	}
 
-	env := os.Getenv("ENV")
-	if env != "development" && env != "production" {
-		fmt.Printf("Invalid ENV '%s', defaulting to 'development'\n", env)
-		env = "development"
-	} else if env == "" {
-		fmt.Printf("ENV not set, defaulting to 'development'\n")
-		env = "development"
+	cfg := Default()
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Config{
-		Port:     port,
-		LogLevel: level,
-		Env:      env,
+	var problems []error
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok && value != "" {
+			if err := s.set(cfg, value); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name {
+				if err := s.set(cfg, f.Value.String()); err != nil {
+					problems = append(problems, fmt.Errorf("-%s: %w", s.flag, err))
+				}
+			}
+		}
+	})
+	problems = append(problems, cfg.Validate()...)
+	for _, check := range checks {
+		problems = append(problems, check(cfg)...)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(problems...))
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFile, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
 	}
+	c.File = path
+	return nil
 }
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+env: production
+server:
+  port: 9000
+  readTimeout: 3s
+log:
+  level: warn
+cors:
+  allowedOrigins: ["https://file.example.com"]
+`)
+	t.Setenv("PORT", "9100")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := Load([]string{"-config", path, "-port", "9200"})
+	assert.NoError(t, err)
+	assert.Equal(t, path, cfg.File)
+	assert.Equal(t, EnvProduction, cfg.Env, "file overrides default")
+	assert.Equal(t, 3*time.Second, cfg.Server.ReadTimeout, "file overrides default")
+	assert.Equal(t, 30*time.Second, cfg.Server.WriteTimeout, "default kept when file omits it")
+	assert.Equal(t, []string{"https://file.example.com"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, "debug", cfg.Log.Level, "env overrides file")
+	assert.Equal(t, 9200, cfg.Server.Port, "flag overrides env and file")
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "config.toml", `
+[server]
+port = 7000
+idleTimeout = "2m"
+
+[log.accessLogSampling]
+"GET /api/v1/persons" = 0.5
+`)
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 7000, cfg.Server.Port)
+	assert.Equal(t, 2*time.Minute, cfg.Server.IdleTimeout)
+	assert.Equal(t, map[string]float64{"GET /api/v1/persons": 0.5}, cfg.Log.AccessLogSampling)
+}
+
+func TestLoad_ListsCheckProblemsWithTheOthers(t *testing.T) {
+	check := func(c *Config) []error {
+		if c.Photos.MaxDimension < 100 {
+			return []error{errors.New("photos: too small")}
+		}
+		return nil
+	}
+
+	_, err := Load([]string{"-port", "70000", "-photos-max-dimension", "50"}, check)
+	assert.ErrorContains(t, err, "server.port: 70000 is not between 1 and 65535")
+	assert.ErrorContains(t, err, "photos: too small")
+
+	_, err = Load(nil, check)
+	assert.NoError(t, err)
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "maybe")
+	t.Setenv("STORAGE_BACKEND", "postgres")
+
+	_, err := Load([]string{"-port", "70000", "-write-timeout", "0s", "-tracing-exporter", "zipkin", "-access-log-sampling", "GET /=2"})
+	assert.Error(t, err)
+	for _, msg := range []string{
+		`CORS_ALLOW_CREDENTIALS: "maybe" is not a boolean`,
+		"server.port: 70000 is not between 1 and 65535",
+		"server.writeTimeout: must be positive",
+		`tracing.exporter: "zipkin" must be none, stdout or otlp`,
+		"log.accessLogSampling[GET /]: 2 is not between 0 and 1",
+		`storage.backend: "postgres" is not supported`,
+	} {
+		assert.Contains(t, err.Error(), msg)
+	}
+}
+
+func TestLoad_UnsupportedFile(t *testing.T) {
+	path := writeFile(t, "config.json", `{}`)
+	_, err := Load([]string{"-config", path})
+	assert.ErrorIs(t, err, ErrUnsupportedFile)
+}
+
+func TestLoad_TLS(t *testing.T) {
+	certFile := writeFile(t, "tls.crt", "cert")
+
+	_, err := Load([]string{"-tls-cert-file", certFile, "-tls-client-auth", "require", "-h2c", "true"})
+	assert.Error(t, err)
+	for _, msg := range []string{
+		"server.h2c: can not be combined with TLS",
+		"server.tls.keyFile: is required when TLS is enabled",
+		"server.tls.clientCAFile: is required when client auth is require",
+	} {
+		assert.Contains(t, err.Error(), msg)
+	}
+
+	_, err = Load([]string{"-tls-client-auth", "optional"})
+	assert.ErrorContains(t, err, "server.tls.clientAuth: requires server.tls.certFile and server.tls.keyFile")
+}
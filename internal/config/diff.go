@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Change is a setting whose value differs between two configurations.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+}
+
+// Diff lists the settings that differ between prev and next, keyed by their
+// config file path (e.g. "log.level").
+func Diff(prev, next *Config) []Change {
+	var changes []Change
+	diffValue("", reflect.ValueOf(*prev), reflect.ValueOf(*next), &changes)
+	return changes
+}
+
+func diffValue(prefix string, prev, next reflect.Value, changes *[]Change) {
+	if prev.Kind() == reflect.Struct {
+		for i := 0; i < prev.NumField(); i++ {
+			field := prev.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" || name == "" {
+				continue
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			if field.Tag.Get("secret") == "true" {
+				diffSecret(name, prev.Field(i), next.Field(i), changes)
+				continue
+			}
+			diffValue(name, prev.Field(i), next.Field(i), changes)
+		}
+		return
+	}
+	old, updated := fmt.Sprint(prev.Interface()), fmt.Sprint(next.Interface())
+	if old != updated {
+		*changes = append(*changes, Change{Key: prefix, Old: old, New: updated})
+	}
+}
+
+// diffSecret reports a changed secret without its values, which end up in
+// the logs.
+func diffSecret(key string, prev, next reflect.Value, changes *[]Change) {
+	if !prev.Equal(next) {
+		*changes = append(*changes, Change{Key: key, Old: "<redacted>", New: "<redacted>"})
+	}
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting is a single value that can be overridden from the environment or
+// the command line.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{"ENV", "env", "environment: development or production", func(c *Config, v string) error {
+		c.Env = v
+		return nil
+	}},
+	{"PORT", "port", "port to listen on", intSetting(func(c *Config) *int { return &c.Server.Port })},
+	{"READ_TIMEOUT", "read-timeout", "maximum duration for reading a request", durationSetting(func(c *Config) *time.Duration { return &c.Server.ReadTimeout })},
+	{"WRITE_TIMEOUT", "write-timeout", "maximum duration for writing a response", durationSetting(func(c *Config) *time.Duration { return &c.Server.WriteTimeout })},
+	{"IDLE_TIMEOUT", "idle-timeout", "maximum keep-alive idle duration", durationSetting(func(c *Config) *time.Duration { return &c.Server.IdleTimeout })},
+	{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "maximum duration for graceful shutdown", durationSetting(func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout })},
+	{"SHUTDOWN_DRAIN_DELAY", "drain-delay", "how long to keep serving after failing readiness on shutdown", durationSetting(func(c *Config) *time.Duration { return &c.Server.DrainDelay })},
+	{"TLS_CERT_FILE", "tls-cert-file", "PEM certificate file, enables HTTPS", func(c *Config, v string) error {
+		c.Server.TLS.CertFile = v
+		return nil
+	}},
+	{"TLS_KEY_FILE", "tls-key-file", "PEM private key file", func(c *Config, v string) error {
+		c.Server.TLS.KeyFile = v
+		return nil
+	}},
+	{"TLS_CLIENT_AUTH", "tls-client-auth", "client certificate authentication: none, optional or require", func(c *Config, v string) error {
+		c.Server.TLS.ClientAuth = v
+		return nil
+	}},
+	{"TLS_CLIENT_CA_FILE", "tls-client-ca-file", "PEM CA bundle used to verify client certificates", func(c *Config, v string) error {
+		c.Server.TLS.ClientCAFile = v
+		return nil
+	}},
+	{"HTTP2", "http2", "enable HTTP/2 over TLS", boolSetting(func(c *Config) *bool { return &c.Server.HTTP2 })},
+	{"H2C", "h2c", "accept HTTP/2 over cleartext behind a TLS terminating proxy", boolSetting(func(c *Config) *bool { return &c.Server.H2C })},
+	{"LOG_LEVEL", "log-level", "log level: debug, info, warn or error", func(c *Config, v string) error {
+		c.Log.Level = v
+		return nil
+	}},
+	{"ACCESS_LOG_SAMPLING", "access-log-sampling", `comma separated "METHOD /route=fraction" access log sampling rates`, func(c *Config, v string) error {
+		sampling := make(map[string]float64)
+		for _, entry := range strings.Split(v, ",") {
+			route, rateStr, found := strings.Cut(entry, "=")
+			if !found {
+				return fmt.Errorf("entry %q is not of the form route=fraction", entry)
+			}
+			rate, err := strconv.ParseFloat(rateStr, 64)
+			if err != nil {
+				return fmt.Errorf("entry %q: %w", entry, err)
+			}
+			sampling[strings.TrimSpace(route)] = rate
+		}
+		c.Log.AccessLogSampling = sampling
+		return nil
+	}},
+	{"CORS_ALLOWED_ORIGINS", "cors-allowed-origins", "comma separated allowed origins", func(c *Config, v string) error {
+		c.CORS.AllowedOrigins = strings.Split(v, ",")
+		return nil
+	}},
+	{"CORS_WEBSOCKET_ORIGINS", "cors-websocket-origins", "comma separated origins allowed to open WebSockets", func(c *Config, v string) error {
+		c.CORS.WebSocketOrigins = strings.Split(v, ",")
+		return nil
+	}},
+	{"CORS_ALLOW_CREDENTIALS", "cors-allow-credentials", "allow credentialed cross-origin requests", boolSetting(func(c *Config) *bool { return &c.CORS.AllowCredentials })},
+	{"CORS_MAX_AGE", "cors-max-age", "how long browsers may cache preflight responses", durationSetting(func(c *Config) *time.Duration { return &c.CORS.MaxAge })},
+	{"TRACING_EXPORTER", "tracing-exporter", "trace exporter: none, stdout or otlp", func(c *Config, v string) error {
+		c.Tracing.Exporter = v
+		return nil
+	}},
+	{"TRACING_ENDPOINT", "tracing-endpoint", "OTLP collector host:port", func(c *Config, v string) error {
+		c.Tracing.Endpoint = v
+		return nil
+	}},
+	{"TRACING_INSECURE", "tracing-insecure", "export traces over plain HTTP", boolSetting(func(c *Config) *bool { return &c.Tracing.Insecure })},
+	{"TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "fraction of traces to sample", floatSetting(func(c *Config) *float64 { return &c.Tracing.SampleRatio })},
+	{"STORAGE_BACKEND", "storage-backend", "storage backend: memory", func(c *Config, v string) error {
+		c.Storage.Backend = v
+		return nil
+	}},
+	{"STORAGE_UNIQUE_FIELDS", "storage-unique-fields", "comma separated person fields that must be unique: email, phone", func(c *Config, v string) error {
+		c.Storage.UniqueFields = nil
+		for _, field := range strings.Split(v, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				c.Storage.UniqueFields = append(c.Storage.UniqueFields, field)
+			}
+		}
+		return nil
+	}},
+	{"STORAGE_SEED", "storage-seed", "seed the store with sample persons on startup", boolSetting(func(c *Config) *bool { return &c.Storage.Seed })},
+	{"WEBHOOK_MAX_ATTEMPTS", "webhook-max-attempts", "delivery attempts before a webhook event is dead-lettered", intSetting(func(c *Config) *int { return &c.Webhooks.MaxAttempts })},
+	{"WEBHOOK_MIN_BACKOFF", "webhook-min-backoff", "delay before the first webhook retry", durationSetting(func(c *Config) *time.Duration { return &c.Webhooks.MinBackoff })},
+	{"WEBHOOK_MAX_BACKOFF", "webhook-max-backoff", "maximum delay between webhook retries", durationSetting(func(c *Config) *time.Duration { return &c.Webhooks.MaxBackoff })},
+	{"WEBHOOK_TIMEOUT", "webhook-timeout", "timeout of a single webhook delivery attempt", durationSetting(func(c *Config) *time.Duration { return &c.Webhooks.Timeout })},
+	{"WEBHOOK_ALLOWED_NETWORKS", "webhook-allowed-networks", "comma separated non-public CIDRs webhooks may be delivered to", func(c *Config, v string) error {
+		c.Webhooks.AllowedNetworks = nil
+		for _, network := range strings.Split(v, ",") {
+			if network = strings.TrimSpace(network); network != "" {
+				c.Webhooks.AllowedNetworks = append(c.Webhooks.AllowedNetworks, network)
+			}
+		}
+		return nil
+	}},
+	{"IDEMPOTENCY_TTL", "idempotency-ttl", "how long responses to requests with an Idempotency-Key are replayed", durationSetting(func(c *Config) *time.Duration { return &c.Idempotency.TTL })},
+	{"HOBBIES_CATALOG_FILE", "hobbies-catalog-file", "JSON hobby catalog replacing the built-in one", func(c *Config, v string) error {
+		c.Hobbies.CatalogFile = v
+		return nil
+	}},
+	{"PHOTOS_DIR", "photos-dir", "directory profile photos are stored in", func(c *Config, v string) error {
+		c.Photos.Dir = v
+		return nil
+	}},
+	{"PHOTOS_MAX_BYTES", "photos-max-bytes", "largest profile photo upload in bytes", intSetting(func(c *Config) *int { return &c.Photos.MaxBytes })},
+	{"PHOTOS_MAX_DIMENSION", "photos-max-dimension", "largest width or height of a profile photo in pixels", intSetting(func(c *Config) *int { return &c.Photos.MaxDimension })},
+	{"ADMIN_TOKEN", "admin-token", "bearer token required by the admin endpoints; empty leaves them open", func(c *Config, v string) error {
+		c.Admin.Token = v
+		return nil
+	}},
+	{"OUTBOX_SINKS", "outbox-sinks", "comma separated outbox sinks: log, file, nats or kafka", func(c *Config, v string) error {
+		c.Outbox.Sinks = strings.Split(v, ",")
+		return nil
+	}},
+	{"OUTBOX_SOURCE", "outbox-source", "CloudEvents source of relayed events", func(c *Config, v string) error {
+		c.Outbox.Source = v
+		return nil
+	}},
+	{"OUTBOX_FILE", "outbox-file", "file the file sink appends events to", func(c *Config, v string) error {
+		c.Outbox.File = v
+		return nil
+	}},
+	{"OUTBOX_NATS_URL", "outbox-nats-url", "NATS server URL of the nats sink", func(c *Config, v string) error {
+		c.Outbox.NATSURL = v
+		return nil
+	}},
+	{"OUTBOX_NATS_SUBJECT", "outbox-nats-subject", "subject prefix of the nats sink", func(c *Config, v string) error {
+		c.Outbox.NATSSubject = v
+		return nil
+	}},
+	{"OUTBOX_KAFKA_BROKERS", "outbox-kafka-brokers", "comma separated Kafka brokers of the kafka sink", func(c *Config, v string) error {
+		c.Outbox.KafkaBrokers = strings.Split(v, ",")
+		return nil
+	}},
+	{"OUTBOX_KAFKA_TOPIC", "outbox-kafka-topic", "topic of the kafka sink", func(c *Config, v string) error {
+		c.Outbox.KafkaTopic = v
+		return nil
+	}},
+}
+
+func intSetting(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", v)
+		}
+		*field(c) = i
+		return nil
+	}
+}
+
+func boolSetting(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func floatSetting(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func durationSetting(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.New(strings.TrimPrefix(err.Error(), "time: "))
+		}
+		*field(c) = d
+		return nil
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"time"
+)
+
+// Validate returns one error per invalid setting. It only checks what can
+// be told from the settings themselves; each component checks the values
+// it understands, such as CORS origins, when it is built from them.
+func (c *Config) Validate() []error {
+	var problems []error
+	invalid := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		invalid("env: %q must be %q or %q", c.Env, EnvDevelopment, EnvProduction)
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server.port: %d is not between 1 and 65535", c.Server.Port)
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.readTimeout", c.Server.ReadTimeout},
+		{"server.writeTimeout", c.Server.WriteTimeout},
+		{"server.idleTimeout", c.Server.IdleTimeout},
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			invalid("%s: must be positive", timeout.name)
+		}
+	}
+	if c.Server.DrainDelay < 0 {
+		invalid("server.drainDelay: must not be negative")
+	}
+	c.validateTLS(invalid)
+	if _, ok := logLevels[c.Log.Level]; !ok {
+		invalid("log.level: %q must be one of debug, info, warn or error", c.Log.Level)
+	}
+	routes := make([]string, 0, len(c.Log.AccessLogSampling))
+	for route := range c.Log.AccessLogSampling {
+		routes = append(routes, route)
+	}
+	slices.Sort(routes)
+	for _, route := range routes {
+		if rate := c.Log.AccessLogSampling[route]; rate < 0 || rate > 1 {
+			invalid("log.accessLogSampling[%s]: %v is not between 0 and 1", route, rate)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		invalid("cors.maxAge: must not be negative")
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		invalid("tracing.exporter: %q must be none, stdout or otlp", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio <= 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sampleRatio: %v is not in (0, 1]", c.Tracing.SampleRatio)
+	}
+	if c.Storage.Backend != StorageMemory {
+		invalid("storage.backend: %q is not supported, use %q", c.Storage.Backend, StorageMemory)
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		invalid("webhooks.maxAttempts: must be at least 1")
+	}
+	if c.Webhooks.MinBackoff <= 0 || c.Webhooks.Timeout <= 0 {
+		invalid("webhooks: minBackoff and timeout must be positive")
+	}
+	if c.Webhooks.MaxBackoff < c.Webhooks.MinBackoff {
+		invalid("webhooks.maxBackoff: %v is less than minBackoff %v", c.Webhooks.MaxBackoff, c.Webhooks.MinBackoff)
+	}
+	for _, network := range c.Webhooks.AllowedNetworks {
+		if _, err := netip.ParsePrefix(network); err != nil {
+			invalid("webhooks.allowedNetworks: %v", err)
+		}
+	}
+	if c.Idempotency.TTL <= 0 {
+		invalid("idempotency.ttl: must be positive")
+	}
+	if c.Photos.Dir == "" {
+		invalid("photos.dir: is required")
+	}
+	if c.Photos.MaxBytes < 1 {
+		invalid("photos.maxBytes: must be positive")
+	}
+	if c.Photos.MaxDimension < 1 {
+		invalid("photos.maxDimension: must be positive")
+	}
+	c.validateOutbox(invalid)
+	return problems
+}
+
+func (c *Config) validateOutbox(invalid func(format string, args ...any)) {
+	o := c.Outbox
+	for _, sink := range o.Sinks {
+		switch sink {
+		case "log":
+		case "file":
+			if o.File == "" {
+				invalid("outbox.file: is required by the file sink")
+			}
+		case "nats":
+			if o.NATSURL == "" || o.NATSSubject == "" {
+				invalid("outbox: natsURL and natsSubject are required by the nats sink")
+			}
+		case "kafka":
+			if len(o.KafkaBrokers) == 0 || o.KafkaTopic == "" {
+				invalid("outbox: kafkaBrokers and kafkaTopic are required by the kafka sink")
+			}
+		default:
+			invalid("outbox.sinks: %q must be log, file, nats or kafka", sink)
+		}
+	}
+	if len(o.Sinks) > 0 && o.Source == "" {
+		invalid("outbox.source: must not be empty")
+	}
+}
+
+func (c *Config) validateTLS(invalid func(format string, args ...any)) {
+	t := c.Server.TLS
+	switch t.ClientAuth {
+	case "none", "optional", "require":
+	default:
+		invalid("server.tls.clientAuth: %q must be none, optional or require", t.ClientAuth)
+	}
+	if !t.Enabled() {
+		if t.ClientAuth != "none" {
+			invalid("server.tls.clientAuth: requires server.tls.certFile and server.tls.keyFile")
+		}
+		return
+	}
+	if c.Server.H2C {
+		invalid("server.h2c: can not be combined with TLS")
+	}
+	for _, file := range []struct {
+		name string
+		path string
+	}{
+		{"server.tls.certFile", t.CertFile},
+		{"server.tls.keyFile", t.KeyFile},
+	} {
+		if file.path == "" {
+			invalid("%s: is required when TLS is enabled", file.name)
+		} else if _, err := os.Stat(file.path); err != nil {
+			invalid("%s: %v", file.name, err)
+		}
+	}
+	if t.ClientAuth != "none" {
+		if t.ClientCAFile == "" {
+			invalid("server.tls.clientCAFile: is required when client auth is %s", t.ClientAuth)
+		} else if _, err := os.Stat(t.ClientCAFile); err != nil {
+			invalid("server.tls.clientCAFile: %v", err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const DefaultWatchInterval = 2 * time.Second
+
+// reloadable lists the settings that take effect without a restart.
+var reloadable = []string{
+	"log.level",
+	"log.accessLogSampling",
+	"cors.allowedOrigins",
+	"cors.webSocketOrigins",
+	"idempotency.ttl",
+}
+
+// Watcher reloads the configuration when the process receives SIGHUP or the
+// config file changes. Every valid new configuration is passed to apply;
+// invalid ones are logged and the running configuration is kept.
+type Watcher struct {
+	args     []string
+	interval time.Duration
+	logger   *slog.Logger
+	apply    func(*Config) error
+	checks   []Check
+
+	mu      sync.Mutex
+	current *Config
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher watches the configuration that was loaded from args and
+// checks. apply must only touch the reloadable settings.
+func NewWatcher(current *Config, args []string, interval time.Duration, logger *slog.Logger, apply func(*Config) error, checks ...Check) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	w := &Watcher{
+		args:     args,
+		interval: interval,
+		logger:   logger,
+		apply:    apply,
+		checks:   checks,
+		current:  current,
+	}
+	w.modTime, w.size = w.stat()
+	return w
+}
+
+// Current returns the configuration currently in effect.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+func (w *Watcher) stat() (time.Time, int64) {
+	if w.current.File == "" {
+		return time.Time{}, 0
+	}
+	info, err := os.Stat(w.current.File)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// Run watches for SIGHUP and file changes until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.logger.Info("received SIGHUP, reloading configuration")
+			_ = w.Reload()
+		case <-ticker.C:
+			w.mu.Lock()
+			modTime, size := w.stat()
+			changed := !modTime.Equal(w.modTime) || size != w.size
+			w.mu.Unlock()
+			if changed {
+				w.logger.Info("config file changed, reloading configuration")
+				_ = w.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads and validates the configuration and applies it when valid.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.modTime, w.size = w.stat()
+
+	next, err := Load(w.args, w.checks...)
+	if err != nil {
+		w.logger.Error("rejected configuration reload", "error", err)
+		return err
+	}
+
+	var applied, restart []string
+	for _, change := range Diff(w.current, next) {
+		if slices.Contains(reloadable, change.Key) {
+			applied = append(applied, change.String())
+		} else {
+			restart = append(restart, change.String())
+		}
+	}
+	if len(applied) == 0 && len(restart) == 0 {
+		w.logger.Info("configuration unchanged")
+		return nil
+	}
+	if len(applied) > 0 {
+		if err := w.apply(next); err != nil {
+			w.logger.Error("rejected configuration reload", "error", err)
+			return err
+		}
+		w.logger.Info("configuration reloaded", "changes", applied)
+	}
+	if len(restart) > 0 {
+		w.logger.Warn("configuration changes require a restart", "changes", restart)
+	}
+
+	effective := *w.current
+	effective.Log.Level = next.Log.Level
+	effective.Log.AccessLogSampling = next.Log.AccessLogSampling
+	effective.CORS.AllowedOrigins = next.CORS.AllowedOrigins
+	effective.CORS.WebSocketOrigins = next.CORS.WebSocketOrigins
+	effective.Idempotency.TTL = next.Idempotency.TTL
+	w.current = &effective
+	return nil
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	prev := Default()
+	next := Default()
+	next.Log.Level = "debug"
+	next.Server.Port = 9000
+	next.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	next.Admin.Token = "s3cret"
+
+	assert.Equal(t, []Change{
+		{Key: "server.port", Old: "8080", New: "9000"},
+		{Key: "log.level", Old: "info", New: "debug"},
+		{Key: "cors.allowedOrigins", Old: "[*]", New: "[https://app.example.com]"},
+		{Key: "admin.token", Old: "<redacted>", New: "<redacted>"},
+	}, Diff(prev, next))
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := writeFile(t, "config.yaml", "log:\n  level: info\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	assert.NoError(t, err)
+
+	var applied []*Config
+	w := NewWatcher(cfg, args, time.Hour, slog.Default(), func(next *Config) error {
+		applied = append(applied, next)
+		return nil
+	})
+
+	t.Run("applies reloadable settings", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("log:\n  level: debug\ncors:\n  allowedOrigins: [\"https://*.example.com\"]\n  webSocketOrigins: [\"https://app.example.com\"]\n"), 0o600))
+		assert.NoError(t, w.Reload())
+		assert.Len(t, applied, 1)
+		assert.Equal(t, "debug", w.Current().Log.Level)
+		assert.Equal(t, []string{"https://*.example.com"}, w.Current().CORS.AllowedOrigins)
+		assert.Equal(t, []string{"https://app.example.com"}, w.Current().CORS.WebSocketOrigins)
+
+		// The same file again is no change.
+		assert.NoError(t, w.Reload())
+		assert.Len(t, applied, 1)
+	})
+
+	t.Run("applies the idempotency ttl alone", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("log:\n  level: debug\ncors:\n  allowedOrigins: [\"https://*.example.com\"]\n  webSocketOrigins: [\"https://app.example.com\"]\nidempotency:\n  ttl: 1h\n"), 0o600))
+		assert.NoError(t, w.Reload())
+		assert.Len(t, applied, 2)
+		assert.Equal(t, time.Hour, w.Current().Idempotency.TTL)
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("log:\n  level: loud\n"), 0o600))
+		assert.Error(t, w.Reload())
+		assert.Len(t, applied, 2)
+		assert.Equal(t, "debug", w.Current().Log.Level)
+	})
+
+	t.Run("keeps settings that require a restart", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(path, []byte("log:\n  level: debug\ncors:\n  allowedOrigins: [\"https://*.example.com\"]\n  webSocketOrigins: [\"https://app.example.com\"]\nidempotency:\n  ttl: 1h\nserver:\n  port: 9000\n"), 0o600))
+		assert.NoError(t, w.Reload())
+		assert.Len(t, applied, 2)
+		assert.Equal(t, 8080, w.Current().Server.Port)
+	})
+}
+
+func TestWatcher_RunDetectsFileChange(t *testing.T) {
+	path := writeFile(t, "config.yaml", "log:\n  level: info\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	assert.NoError(t, err)
+
+	var level atomic.Value
+	w := NewWatcher(cfg, args, 10*time.Millisecond, slog.Default(), func(next *Config) error {
+		level.Store(next.Log.Level)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	assert.NoError(t, os.WriteFile(path, []byte("log:\n  level: error\n"), 0o600))
+	assert.Eventually(t, func() bool {
+		return level.Load() == "error"
+	}, time.Second, 10*time.Millisecond)
+}
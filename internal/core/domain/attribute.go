@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Attributes are the custom fields of a person, e.g. a department or a
+// badge number. Values are strings, float64 numbers or booleans, as
+// decoded from JSON.
+type Attributes map[string]any
+
+// AttributeType is the type of an attribute.
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeInteger AttributeType = "integer"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+)
+
+// AttributeProperty constrains one attribute with the keywords that apply
+// to its type. Unset bounds are nil.
+type AttributeProperty struct {
+	Type        AttributeType
+	Title       string
+	Description string
+	Enum        []any
+	Minimum     *float64
+	Maximum     *float64
+	MinLength   *int
+	MaxLength   *int
+	Pattern     string
+
+	pattern *regexp.Regexp
+}
+
+// AttributeSchema describes the attributes a tenant may write: an object
+// of scalar properties. Its keywords follow JSON Schema, but it supports
+// only the ones below.
+type AttributeSchema struct {
+	Title       string
+	Description string
+	Properties  map[string]AttributeProperty
+	Required    []string
+	// AdditionalProperties allows attributes missing from Properties, as
+	// long as they are scalars.
+	AdditionalProperties bool
+}
+
+// Compile checks that the keywords of every property apply to its type
+// and returns s ready to Validate.
+func (s AttributeSchema) Compile() (AttributeSchema, error) {
+	props := make(map[string]AttributeProperty, len(s.Properties))
+	for name, p := range s.Properties {
+		if err := p.compile(); err != nil {
+			return AttributeSchema{}, fmt.Errorf("properties.%s: %w", name, err)
+		}
+		props[name] = p
+	}
+	s.Properties = props
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			return AttributeSchema{}, fmt.Errorf("required: %q is not a property", name)
+		}
+	}
+	return s, nil
+}
+
+func (p *AttributeProperty) compile() error {
+	switch p.Type {
+	case AttributeString, AttributeInteger, AttributeNumber, AttributeBoolean:
+	default:
+		return fmt.Errorf("type must be one of string integer number boolean")
+	}
+	numeric := p.Type == AttributeInteger || p.Type == AttributeNumber
+	if (p.Minimum != nil || p.Maximum != nil) && !numeric {
+		return fmt.Errorf("minimum and maximum only apply to numbers")
+	}
+	if (p.MinLength != nil || p.MaxLength != nil || p.Pattern != "") && p.Type != AttributeString {
+		return fmt.Errorf("minLength, maxLength and pattern only apply to strings")
+	}
+	for _, v := range p.Enum {
+		if msg := p.checkType(v); msg != "" {
+			return fmt.Errorf("enum: %v %s", v, msg)
+		}
+	}
+	if p.Pattern != "" {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern: %w", err)
+		}
+		p.pattern = re
+	}
+	return nil
+}
+
+// Validate returns a message for every attribute of attrs that the schema
+// rejects, keyed by attribute name.
+func (s AttributeSchema) Validate(attrs Attributes) map[string]string {
+	errs := make(map[string]string)
+	for _, name := range s.Required {
+		if _, ok := attrs[name]; !ok {
+			errs[name] = "This field is required"
+		}
+	}
+	for name, v := range attrs {
+		p, ok := s.Properties[name]
+		switch {
+		case ok:
+			if msg := p.check(v); msg != "" {
+				errs[name] = msg
+			}
+		case !s.AdditionalProperties:
+			errs[name] = "is not a defined attribute"
+		case !isScalar(v):
+			errs[name] = "must be a string, number or boolean"
+		}
+	}
+	return errs
+}
+
+// ValidateChanges is Validate limited to the attributes that attrs adds,
+// changes or removes compared to previous. Attributes left as they were,
+// such as those written under another tenant's schema, are not checked.
+func (s AttributeSchema) ValidateChanges(previous, attrs Attributes) map[string]string {
+	errs := s.Validate(attrs)
+	for name := range errs {
+		old, had := previous[name]
+		v, has := attrs[name]
+		if had == has && (!has || reflect.DeepEqual(old, v)) {
+			delete(errs, name)
+		}
+	}
+	return errs
+}
+
+func (p AttributeProperty) check(v any) string {
+	if msg := p.checkType(v); msg != "" {
+		return msg
+	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, v) {
+		values := make([]string, len(p.Enum))
+		for i, e := range p.Enum {
+			values[i] = FormatAttribute(e)
+		}
+		return "must be one of " + strings.Join(values, " ")
+	}
+	switch v := v.(type) {
+	case float64:
+		if p.Minimum != nil && v < *p.Minimum {
+			return "can not be less than " + FormatAttribute(*p.Minimum)
+		}
+		if p.Maximum != nil && v > *p.Maximum {
+			return "can not be greater than " + FormatAttribute(*p.Maximum)
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if p.MinLength != nil && n < *p.MinLength {
+			return "must have at least " + strconv.Itoa(*p.MinLength) + " character(s)"
+		}
+		if p.MaxLength != nil && n > *p.MaxLength {
+			return "must have at most " + strconv.Itoa(*p.MaxLength) + " character(s)"
+		}
+		if p.pattern != nil && !p.pattern.MatchString(v) {
+			return "must match " + p.Pattern
+		}
+	}
+	return ""
+}
+
+func (p AttributeProperty) checkType(v any) string {
+	switch p.Type {
+	case AttributeString:
+		if _, ok := v.(string); !ok {
+			return "must be a string"
+		}
+	case AttributeInteger:
+		if f, ok := v.(float64); !ok || f != math.Trunc(f) {
+			return "must be an integer"
+		}
+	case AttributeNumber:
+		if _, ok := v.(float64); !ok {
+			return "must be a number"
+		}
+	case AttributeBoolean:
+		if _, ok := v.(bool); !ok {
+			return "must be a boolean"
+		}
+	}
+	return ""
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case string, float64, bool:
+		return true
+	}
+	return false
+}
+
+// FormatAttribute formats an attribute value the way list filters spell
+// it: strings as is, numbers without trailing zeros and booleans as true
+// or false.
+func FormatAttribute(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// PersonFilter selects the persons a list returns. The zero value selects
+// every person.
+type PersonFilter struct {
+	// Attributes maps attribute names to the formatted value a person must
+	// have, as returned by FormatAttribute.
+	Attributes map[string]string
+}
+
+func (f PersonFilter) Matches(p Person) bool {
+	for name, want := range f.Attributes {
+		v, ok := p.Attributes[name]
+		if !ok || FormatAttribute(v) != want {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestAttributeSchemaCompile(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema AttributeSchema
+		err    string
+	}{
+		{"unknown type", AttributeSchema{Properties: map[string]AttributeProperty{"a": {Type: "array"}}}, "properties.a: type must be"},
+		{"bounds on string", AttributeSchema{Properties: map[string]AttributeProperty{"a": {Type: AttributeString, Minimum: ptr(1.0)}}}, "minimum and maximum only apply to numbers"},
+		{"pattern on number", AttributeSchema{Properties: map[string]AttributeProperty{"a": {Type: AttributeNumber, Pattern: "x"}}}, "only apply to strings"},
+		{"bad pattern", AttributeSchema{Properties: map[string]AttributeProperty{"a": {Type: AttributeString, Pattern: "("}}}, "pattern:"},
+		{"enum of other type", AttributeSchema{Properties: map[string]AttributeProperty{"a": {Type: AttributeString, Enum: []any{1.0}}}}, "enum: 1 must be a string"},
+		{"required without property", AttributeSchema{Required: []string{"a"}}, `required: "a" is not a property`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.schema.Compile()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestAttributeSchemaValidate(t *testing.T) {
+	schema, err := AttributeSchema{
+		Properties: map[string]AttributeProperty{
+			"department": {Type: AttributeString, Enum: []any{"sales", "engineering"}},
+			"badge":      {Type: AttributeInteger, Minimum: ptr(1.0), Maximum: ptr(9999.0)},
+			"shirtSize":  {Type: AttributeString, Pattern: "^(XS|S|M|L|XL)$"},
+			"nickname":   {Type: AttributeString, MinLength: ptr(2), MaxLength: ptr(4)},
+			"remote":     {Type: AttributeBoolean},
+		},
+		Required: []string{"department"},
+	}.Compile()
+	require.NoError(t, err)
+
+	assert.Empty(t, schema.Validate(Attributes{"department": "sales", "badge": 42.0, "shirtSize": "M", "nickname": "Åsa", "remote": true}))
+	assert.Equal(t, map[string]string{
+		"department": "This field is required",
+		"badge":      "must be an integer",
+		"shirtSize":  "must match ^(XS|S|M|L|XL)$",
+		"nickname":   "must have at least 2 character(s)",
+		"remote":     "must be a boolean",
+		"team":       "is not a defined attribute",
+	}, schema.Validate(Attributes{"badge": 4.5, "shirtSize": "XXL", "nickname": "A", "remote": "yes", "team": "a"}))
+	assert.Equal(t, map[string]string{
+		"department": "must be one of sales engineering",
+		"badge":      "can not be greater than 9999",
+		"nickname":   "must have at most 4 character(s)",
+	}, schema.Validate(Attributes{"department": "hr", "badge": 10000.0, "nickname": "Alexa"}))
+
+	schema.AdditionalProperties = true
+	assert.Equal(t, map[string]string{"tags": "must be a string, number or boolean"},
+		schema.Validate(Attributes{"department": "sales", "team": "a", "tags": []any{"x"}}))
+}
+
+func TestAttributeSchemaValidateChanges(t *testing.T) {
+	schema, err := AttributeSchema{
+		Properties: map[string]AttributeProperty{
+			"department": {Type: AttributeString},
+			"badge":      {Type: AttributeInteger},
+		},
+		Required: []string{"department"},
+	}.Compile()
+	require.NoError(t, err)
+
+	// Attributes written under another schema are left alone.
+	previous := Attributes{"team": "a", "badge": 4.5}
+	assert.Empty(t, schema.ValidateChanges(previous, Attributes{"team": "a", "badge": 4.5}))
+	assert.Equal(t, map[string]string{
+		"badge": "must be an integer",
+		"shirt": "is not a defined attribute",
+	}, schema.ValidateChanges(previous, Attributes{"team": "a", "badge": 5.5, "shirt": "M"}))
+
+	previous = Attributes{"department": "sales"}
+	assert.Equal(t, map[string]string{"department": "This field is required"}, schema.ValidateChanges(previous, Attributes{}))
+}
+
+func TestPersonFilter(t *testing.T) {
+	p := Person{Attributes: Attributes{"department": "sales", "badge": 42.0, "remote": true}}
+	assert.True(t, PersonFilter{}.Matches(p))
+	assert.True(t, PersonFilter{Attributes: map[string]string{"department": "sales", "badge": "42", "remote": "true"}}.Matches(p))
+	assert.False(t, PersonFilter{Attributes: map[string]string{"badge": "42.0"}}.Matches(p))
+	assert.False(t, PersonFilter{Attributes: map[string]string{"team": ""}}.Matches(p))
+}
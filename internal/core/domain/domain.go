@@ -1,14 +1,39 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// DateLayout is the format of birth dates on the wire.
+const DateLayout = "2006-01-02"
+
+type Address struct {
+	Street     string
+	City       string
+	PostalCode string
+	Region     string
+	// Country is an ISO 3166-1 alpha-2 code.
+	Country string
+}
+
 type Person struct {
-	ID      uuid.UUID
-	Name    string
+	ID   uuid.UUID
+	Name string
+	// Age is derived from BirthDate when it is set; it is only stored for
+	// persons without a birth date.
 	Age     int32
 	Hobbies []string
+	Email   string
+	// Phone is an E.164 number, e.g. +14155552671.
+	Phone string
+	// BirthDate is a calendar date at UTC midnight, zero when unknown.
+	BirthDate time.Time
+	Address   *Address
+	// Attributes are validated against the schema of the tenant writing
+	// the person.
+	Attributes Attributes
 }
 
 func NewPerson(
@@ -23,3 +48,21 @@ func NewPerson(
 		Hobbies: hobbies,
 	}
 }
+
+// WithDerivedAge returns p with Age computed from BirthDate as of now.
+func (p Person) WithDerivedAge(now time.Time) Person {
+	if !p.BirthDate.IsZero() {
+		p.Age = AgeOn(p.BirthDate, now)
+	}
+	return p
+}
+
+// AgeOn returns the age in whole years on day of someone born on birth.
+func AgeOn(birth, day time.Time) int32 {
+	day = day.UTC()
+	age := day.Year() - birth.Year()
+	if day.Month() < birth.Month() || (day.Month() == birth.Month() && day.Day() < birth.Day()) {
+		age--
+	}
+	return int32(max(age, 0))
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Weights of the parts of a duplicate score; they add up to 1.
+const (
+	nameWeight  = 0.6
+	ageWeight   = 0.2
+	hobbyWeight = 0.2
+)
+
+// DuplicateCandidate is a person that may describe the same human as
+// another one. Scores range from 0 (nothing alike) to 1 (identical).
+type DuplicateCandidate struct {
+	Person     Person
+	Score      float64
+	NameScore  float64
+	AgeScore   float64
+	HobbyScore float64
+}
+
+// ScoreDuplicate rates how likely candidate is a duplicate of p from their
+// normalized names, ages and hobbies.
+func ScoreDuplicate(p, candidate Person) DuplicateCandidate {
+	c := DuplicateCandidate{
+		Person:     candidate,
+		NameScore:  jaroWinkler(NormalizeName(p.Name), NormalizeName(candidate.Name)),
+		AgeScore:   ageScore(p, candidate),
+		HobbyScore: hobbyScore(p.Hobbies, candidate.Hobbies),
+	}
+	c.Score = nameWeight*c.NameScore + ageWeight*c.AgeScore + hobbyWeight*c.HobbyScore
+	return c
+}
+
+// NormalizeName folds case and accents, drops punctuation and sorts the
+// words, so "Müller, Anna" and "anna muller" compare equal.
+func NormalizeName(name string) string {
+	words := strings.Fields(fold(name))
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// fold lowercases s, strips accents and replaces punctuation with spaces.
+func fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+func ageScore(a, b Person) float64 {
+	if !a.BirthDate.IsZero() && !b.BirthDate.IsZero() {
+		switch {
+		case a.BirthDate.Equal(b.BirthDate):
+			return 1
+		case a.BirthDate.Year() == b.BirthDate.Year():
+			return 0.5
+		}
+		return 0
+	}
+	switch diff := a.Age - b.Age; {
+	case diff == 0:
+		return 1
+	case diff == 1 || diff == -1:
+		// Ages are entered at different times, so a year apart is likely.
+		return 0.5
+	}
+	return 0
+}
+
+// hobbyScore is the Jaccard similarity of the case-folded hobby sets.
+func hobbyScore(a, b []string) float64 {
+	set := make(map[string]int, len(a)+len(b))
+	for _, h := range a {
+		set[strings.ToLower(strings.TrimSpace(h))] |= 1
+	}
+	for _, h := range b {
+		set[strings.ToLower(strings.TrimSpace(h))] |= 2
+	}
+	if len(set) == 0 {
+		return 0
+	}
+	both := 0
+	for _, in := range set {
+		if in == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(set))
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of a and b, which
+// favours strings sharing a prefix.
+func jaroWinkler(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 || len(t) == 0 {
+		if len(s) == len(t) {
+			return 1
+		}
+		return 0
+	}
+	window := max(len(s), len(t))/2 - 1
+	window = max(window, 0)
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
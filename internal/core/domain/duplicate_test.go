@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "anna muller", NormalizeName("Müller, Anna"))
+	assert.Equal(t, "anna muller", NormalizeName("  anna   MULLER "))
+	assert.Equal(t, "jean luc picard", NormalizeName("Jean-Luc Picard"))
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.InDelta(t, 0.961, jaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, jaroWinkler("dwayne", "duane"), 0.001)
+	assert.Equal(t, 1.0, jaroWinkler("same", "same"))
+	assert.Equal(t, 0.0, jaroWinkler("abc", "xyz"))
+	assert.Equal(t, 0.0, jaroWinkler("", "abc"))
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	p := Person{Name: "Anna Müller", Age: 34, Hobbies: []string{"Chess", "Running"}}
+
+	same := ScoreDuplicate(p, Person{Name: "muller anna", Age: 34, Hobbies: []string{"running", "chess"}})
+	assert.InDelta(t, 1, same.Score, 1e-9)
+
+	close := ScoreDuplicate(p, Person{Name: "Ana Muller", Age: 35, Hobbies: []string{"Chess"}})
+	assert.Greater(t, close.Score, 0.75)
+	assert.Equal(t, 0.5, close.AgeScore)
+	assert.Equal(t, 0.5, close.HobbyScore)
+
+	other := ScoreDuplicate(p, Person{Name: "Bob Stone", Age: 60, Hobbies: []string{"Golf"}})
+	assert.Less(t, other.Score, 0.5)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventPersonCreated EventType = "person.created"
+	EventPersonUpdated EventType = "person.updated"
+	EventPersonDeleted EventType = "person.deleted"
+)
+
+// EventTypes lists every event raised for persons.
+var EventTypes = []EventType{EventPersonCreated, EventPersonUpdated, EventPersonDeleted}
+
+// Event records a change to a person. For deletions only Person.ID is set.
+type Event struct {
+	ID         uuid.UUID
+	Type       EventType
+	OccurredAt time.Time
+	Person     Person
+}
+
+func NewEvent(eventType EventType, person Person) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Person:     person,
+	}
+}
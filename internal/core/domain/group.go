@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupRole is the role of a member within a group.
+type GroupRole string
+
+const (
+	RoleOwner  GroupRole = "owner"
+	RoleLead   GroupRole = "lead"
+	RoleMember GroupRole = "member"
+)
+
+// GroupRoles lists every supported role.
+var GroupRoles = []GroupRole{RoleOwner, RoleLead, RoleMember}
+
+// Group is a team or other set of persons.
+type Group struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+func NewGroup(name, description string) Group {
+	return Group{ID: uuid.New(), Name: name, Description: description, CreatedAt: time.Now().UTC()}
+}
+
+// Membership places a person in a group with a role.
+type Membership struct {
+	Group    Group
+	Person   Person
+	Role     GroupRole
+	JoinedAt time.Time
+}
@@ -0,0 +1,39 @@
+package domain
+
+// DefaultHobbies is the built-in hobby catalog.
+var DefaultHobbies = []Hobby{
+	{Name: "Running", Aliases: []string{"Jogging"}, Category: "Sports"},
+	{Name: "Cycling", Aliases: []string{"Biking", "Bicycling"}, Category: "Sports"},
+	{Name: "Swimming", Category: "Sports"},
+	{Name: "Soccer", Aliases: []string{"Football"}, Category: "Sports"},
+	{Name: "Basketball", Category: "Sports"},
+	{Name: "Tennis", Category: "Sports"},
+	{Name: "Golf", Category: "Sports"},
+	{Name: "Yoga", Category: "Sports"},
+	{Name: "Climbing", Aliases: []string{"Rock Climbing", "Bouldering"}, Category: "Sports"},
+	{Name: "Hiking", Aliases: []string{"Trekking"}, Category: "Outdoors"},
+	{Name: "Camping", Category: "Outdoors"},
+	{Name: "Fishing", Category: "Outdoors"},
+	{Name: "Gardening", Category: "Outdoors"},
+	{Name: "Traveling", Aliases: []string{"Travel", "Travelling"}, Category: "Outdoors"},
+	{Name: "Photography", Aliases: []string{"Photos"}, Category: "Arts"},
+	{Name: "Painting", Category: "Arts"},
+	{Name: "Drawing", Aliases: []string{"Sketching"}, Category: "Arts"},
+	{Name: "Writing", Aliases: []string{"Creative Writing"}, Category: "Arts"},
+	{Name: "Dancing", Aliases: []string{"Dance"}, Category: "Arts"},
+	{Name: "Knitting", Category: "Crafts"},
+	{Name: "Woodworking", Category: "Crafts"},
+	{Name: "Playing Guitar", Aliases: []string{"Guitar"}, Category: "Music"},
+	{Name: "Playing Piano", Aliases: []string{"Piano"}, Category: "Music"},
+	{Name: "Singing", Category: "Music"},
+	{Name: "Listening to Music", Aliases: []string{"Music"}, Category: "Music"},
+	{Name: "Video Games", Aliases: []string{"Gaming", "Videogames", "Video Gaming"}, Category: "Games"},
+	{Name: "Board Games", Aliases: []string{"Tabletop Games"}, Category: "Games"},
+	{Name: "Chess", Category: "Games"},
+	{Name: "Cooking", Category: "Food"},
+	{Name: "Baking", Category: "Food"},
+	{Name: "Reading", Aliases: []string{"Books"}, Category: "Learning"},
+	{Name: "Learning Languages", Aliases: []string{"Languages"}, Category: "Learning"},
+	{Name: "Programming", Aliases: []string{"Coding"}, Category: "Technology"},
+	{Name: "Volunteering", Category: "Social"},
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Hobby is a catalog entry. Aliases are other spellings that normalize to
+// Name.
+type Hobby struct {
+	Name     string
+	Aliases  []string
+	Category string
+}
+
+// HobbySuggestion is a hobby completing a prefix, with the number of
+// stored persons who have it.
+type HobbySuggestion struct {
+	Name     string
+	Category string
+	Persons  int
+}
+
+// HobbyCatalog maps hobbies and their aliases to canonical names. Lookups
+// ignore case, accents, punctuation and spacing. The zero value is an
+// empty catalog.
+type HobbyCatalog struct {
+	hobbies []Hobby
+	// byKey maps the folded names and aliases to indexes in hobbies.
+	byKey map[string]int
+}
+
+// NewHobbyCatalog fails when two hobbies share a name or alias.
+func NewHobbyCatalog(hobbies []Hobby) (*HobbyCatalog, error) {
+	c := &HobbyCatalog{byKey: make(map[string]int)}
+	for i, h := range hobbies {
+		if strings.TrimSpace(h.Name) == "" {
+			return nil, fmt.Errorf("hobby %d has no name", i)
+		}
+		for _, name := range append([]string{h.Name}, h.Aliases...) {
+			key := hobbyKey(name)
+			if j, taken := c.byKey[key]; taken && j != i {
+				return nil, fmt.Errorf("%q is both %q and %q", name, hobbies[j].Name, h.Name)
+			}
+			c.byKey[key] = i
+		}
+	}
+	c.hobbies = hobbies
+	return c, nil
+}
+
+// Lookup returns the catalog entry hobby names or is an alias of.
+func (c *HobbyCatalog) Lookup(hobby string) (Hobby, bool) {
+	i, ok := c.byKey[hobbyKey(hobby)]
+	if !ok {
+		return Hobby{}, false
+	}
+	return c.hobbies[i], true
+}
+
+// Normalize replaces catalogued hobbies and aliases with their canonical
+// name, trims the others and drops blanks and duplicates, keeping the
+// first occurrence.
+func (c *HobbyCatalog) Normalize(hobbies []string) []string {
+	if hobbies == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(hobbies))
+	seen := make(map[string]bool, len(hobbies))
+	for _, h := range hobbies {
+		h = c.canonical(h)
+		if key := hobbyKey(h); key != "" && !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, h)
+		}
+	}
+	return normalized
+}
+
+func (c *HobbyCatalog) canonical(hobby string) string {
+	if h, ok := c.Lookup(hobby); ok {
+		return h.Name
+	}
+	return strings.Join(strings.Fields(hobby), " ")
+}
+
+// Suggest returns up to limit hobbies whose name or an alias, or a word of
+// them, starts with prefix, most popular first. counts maps stored hobbies
+// to the number of persons who have them; uncatalogued ones are suggested
+// too.
+func (c *HobbyCatalog) Suggest(prefix string, counts map[string]int, limit int) []HobbySuggestion {
+	byKey := make(map[string]*HobbySuggestion)
+	aliases := make(map[string][]string)
+	for _, h := range c.hobbies {
+		key := hobbyKey(h.Name)
+		byKey[key] = &HobbySuggestion{Name: h.Name, Category: h.Category}
+		for _, alias := range h.Aliases {
+			aliases[key] = append(aliases[key], hobbyKey(alias))
+		}
+	}
+	// Uncatalogued spellings that only differ in case are one hobby, shown
+	// as its most common spelling.
+	spellings := make(map[string]int)
+	for hobby, n := range counts {
+		name := c.canonical(hobby)
+		key := hobbyKey(name)
+		if key == "" {
+			continue
+		}
+		s, ok := byKey[key]
+		if !ok {
+			s = &HobbySuggestion{Name: name}
+			byKey[key] = s
+		}
+		s.Persons += n
+		if _, catalogued := c.byKey[key]; !catalogued {
+			spellings[name] += n
+			if spellings[name] > spellings[s.Name] || (spellings[name] == spellings[s.Name] && name < s.Name) {
+				s.Name = name
+			}
+		}
+	}
+
+	prefix = hobbyKey(prefix)
+	matches := func(key string) bool {
+		return strings.HasPrefix(key, prefix) || strings.Contains(key, " "+prefix)
+	}
+	suggestions := make([]HobbySuggestion, 0, len(byKey))
+	for key, s := range byKey {
+		if matches(key) || slices.ContainsFunc(aliases[key], matches) {
+			suggestions = append(suggestions, *s)
+		}
+	}
+	slices.SortFunc(suggestions, func(a, b HobbySuggestion) int {
+		if c := cmp.Compare(b.Persons, a.Persons); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return suggestions[:min(limit, len(suggestions))]
+}
+
+// hobbyKey folds hobby for comparison, so "Video-games" and "video games"
+// are the same hobby.
+func hobbyKey(hobby string) string {
+	return strings.Join(strings.Fields(fold(hobby)), " ")
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHobbyCatalog_Normalize(t *testing.T) {
+	c, err := NewHobbyCatalog(DefaultHobbies)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"Video Games", "Hiking", "Knife making"},
+		c.Normalize([]string{"gaming", " video-games ", "Trekking", "VIDEO GAMES", "Knife   making", "  "}),
+	)
+	assert.Equal(t, []string{"Soccer", "Playing Piano"}, c.Normalize([]string{"Football", "piano"}))
+	assert.Nil(t, c.Normalize(nil))
+
+	var empty HobbyCatalog
+	assert.Equal(t, []string{"Gaming"}, empty.Normalize([]string{"Gaming", "gaming "}))
+}
+
+func TestNewHobbyCatalog_RejectsSharedAliases(t *testing.T) {
+	_, err := NewHobbyCatalog([]Hobby{
+		{Name: "Soccer", Aliases: []string{"Football"}},
+		{Name: "American Football", Aliases: []string{"football"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestHobbyCatalog_Suggest(t *testing.T) {
+	c, err := NewHobbyCatalog(DefaultHobbies)
+	require.NoError(t, err)
+	counts := map[string]int{"Video Games": 2, "Gaming": 1, "Board Games": 1, "game design": 1, "Game Design": 1}
+
+	got := c.Suggest("gam", counts, 10)
+	assert.Equal(t, []HobbySuggestion{
+		{Name: "Video Games", Category: "Games", Persons: 3},
+		{Name: "Game Design", Persons: 2},
+		{Name: "Board Games", Category: "Games", Persons: 1},
+	}, got)
+
+	got = c.Suggest("foot", counts, 10)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Soccer", got[0].Name)
+
+	assert.Len(t, c.Suggest("", counts, 5), 5)
+}
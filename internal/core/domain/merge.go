@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MergeStrategy decides which record a field of a merged person comes from.
+type MergeStrategy string
+
+const (
+	KeepTarget MergeStrategy = "keepTarget"
+	KeepSource MergeStrategy = "keepSource"
+	// UnionHobbies keeps the hobbies of both records; only valid for hobbies.
+	UnionHobbies MergeStrategy = "union"
+)
+
+// MergeFields lists the fields a MergeStrategies can name.
+var MergeFields = []string{"name", "age", "hobbies", "email", "phone", "birthDate", "address", "attributes"}
+
+// MergeStrategies maps fields to their strategy. Fields that are not
+// listed keep the target's value.
+type MergeStrategies map[string]MergeStrategy
+
+// Validate reports the first field or strategy that is not supported.
+func (s MergeStrategies) Validate() error {
+	for field, strategy := range s {
+		if !slices.Contains(MergeFields, field) {
+			return fmt.Errorf("unknown field %q, must be one of %s", field, strings.Join(MergeFields, ", "))
+		}
+		switch strategy {
+		case KeepTarget, KeepSource:
+		case UnionHobbies:
+			if field != "hobbies" {
+				return fmt.Errorf("strategy %q only applies to hobbies", strategy)
+			}
+		default:
+			return fmt.Errorf("unknown strategy %q for %s", strategy, field)
+		}
+	}
+	return nil
+}
+
+// Merge records that Source was folded into the person TargetID.
+type Merge struct {
+	ID         uuid.UUID
+	TargetID   uuid.UUID
+	Source     Person
+	Strategies MergeStrategies
+	MergedAt   time.Time
+}
+
+func NewMerge(targetID uuid.UUID, source Person, strategies MergeStrategies) Merge {
+	return Merge{
+		ID:         uuid.New(),
+		TargetID:   targetID,
+		Source:     source,
+		Strategies: strategies,
+		MergedAt:   time.Now().UTC(),
+	}
+}
+
+// MergePersons returns target with the fields s selects taken from source.
+// strategies must be valid.
+func MergePersons(target, source Person, s MergeStrategies) Person {
+	merged := target
+	if s["name"] == KeepSource {
+		merged.Name = source.Name
+	}
+	if s["age"] == KeepSource {
+		merged.Age = source.Age
+	}
+	switch s["hobbies"] {
+	case KeepSource:
+		merged.Hobbies = source.Hobbies
+	case UnionHobbies:
+		merged.Hobbies = slices.Clone(target.Hobbies)
+		for _, h := range source.Hobbies {
+			if !slices.ContainsFunc(merged.Hobbies, func(t string) bool { return strings.EqualFold(t, h) }) {
+				merged.Hobbies = append(merged.Hobbies, h)
+			}
+		}
+	}
+	if s["email"] == KeepSource {
+		merged.Email = source.Email
+	}
+	if s["phone"] == KeepSource {
+		merged.Phone = source.Phone
+	}
+	if s["birthDate"] == KeepSource {
+		merged.BirthDate = source.BirthDate
+	}
+	if s["address"] == KeepSource {
+		merged.Address = source.Address
+	}
+	if s["attributes"] == KeepSource {
+		merged.Attributes = source.Attributes
+	}
+	return merged
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeStrategiesValidate(t *testing.T) {
+	assert.NoError(t, MergeStrategies{"name": KeepSource, "hobbies": UnionHobbies}.Validate())
+	assert.ErrorContains(t, MergeStrategies{"nickname": KeepSource}.Validate(), "unknown field")
+	assert.ErrorContains(t, MergeStrategies{"email": UnionHobbies}.Validate(), "only applies to hobbies")
+	assert.ErrorContains(t, MergeStrategies{"age": "newest"}.Validate(), "unknown strategy")
+}
+
+func TestMergePersons(t *testing.T) {
+	target := Person{Name: "T", Age: 30, Hobbies: []string{"Chess"}, Email: "t@example.com"}
+	source := Person{Name: "S", Age: 31, Hobbies: []string{"chess", "Golf"}, Phone: "+123456789", Address: &Address{City: "Oslo"}}
+
+	kept := MergePersons(target, source, nil)
+	assert.Equal(t, target, kept)
+
+	merged := MergePersons(target, source, MergeStrategies{
+		"age":     KeepSource,
+		"hobbies": UnionHobbies,
+		"phone":   KeepSource,
+		"address": KeepSource,
+	})
+	assert.Equal(t, "T", merged.Name)
+	assert.Equal(t, int32(31), merged.Age)
+	assert.Equal(t, []string{"Chess", "Golf"}, merged.Hobbies)
+	assert.Equal(t, "t@example.com", merged.Email)
+	assert.Equal(t, "+123456789", merged.Phone)
+	assert.Equal(t, "Oslo", merged.Address.City)
+	assert.Equal(t, []string{"Chess"}, target.Hobbies)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RelationType string
+
+const (
+	RelationFriend  RelationType = "friend"
+	RelationSpouse  RelationType = "spouse"
+	RelationParent  RelationType = "parent"
+	RelationManager RelationType = "manager"
+)
+
+// RelationTypes lists every supported relationship type.
+var RelationTypes = []RelationType{RelationFriend, RelationSpouse, RelationParent, RelationManager}
+
+// Relationship is a directed, typed edge: FromID is the Type of ToID, e.g.
+// the parent or manager of ToID. A pair of persons has at most one
+// relationship of each type in each direction.
+type Relationship struct {
+	FromID    uuid.UUID
+	ToID      uuid.UUID
+	Type      RelationType
+	CreatedAt time.Time
+}
+
+func NewRelationship(from, to uuid.UUID, t RelationType) Relationship {
+	return Relationship{FromID: from, ToID: to, Type: t, CreatedAt: time.Now().UTC()}
+}
+
+// Other returns the person at the other end of r from id.
+func (r Relationship) Other(id uuid.UUID) uuid.UUID {
+	if r.FromID == id {
+		return r.ToID
+	}
+	return r.FromID
+}
+
+// PathQuery restricts the relationships a path may follow.
+type PathQuery struct {
+	// Types allowed on the path; empty allows every type.
+	Types []RelationType
+	// Directed only follows relationships from FromID to ToID.
+	Directed bool
+	// MaxDepth bounds the number of relationships on the path.
+	MaxDepth int
+}
+
+// Allows reports whether a path under q may use relationships of type t.
+func (q PathQuery) Allows(t RelationType) bool {
+	return len(q.Types) == 0 || slices.Contains(q.Types, t)
+}
+
+// Suggestion is a person reached through Mutual friends.
+type Suggestion struct {
+	Person Person
+	Mutual int
+}
+
+// Path connects Persons[0] to the last person; Relationships[i] links
+// Persons[i] and Persons[i+1] in either direction unless the query was
+// directed.
+type Path struct {
+	Persons       []Person
+	Relationships []Relationship
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// SimilarPerson is a person ranked by how much its hobbies resemble
+// another person's.
+type SimilarPerson struct {
+	Person Person
+	// Score is the TF-IDF cosine similarity of the hobbies, from 0 to 1.
+	Score         float64
+	SharedHobbies []string
+}
+
+// RankSimilar scores candidates against p and returns up to limit of them
+// sharing at least one hobby, best first. Hobbies held by fewer of the
+// total persons weigh more, as counted by holders. Equal scores rank the
+// person closest in age first.
+func RankSimilar(p Person, candidates []Person, holders map[string]int, total int, limit int) []SimilarPerson {
+	weight := func(hobby string) float64 {
+		return math.Log(1 + float64(total)/float64(max(holders[hobby], 1)))
+	}
+	norm := func(hobbies []string) float64 {
+		sum := 0.0
+		for _, h := range hobbies {
+			sum += weight(h) * weight(h)
+		}
+		return math.Sqrt(sum)
+	}
+	own := make(map[string]bool, len(p.Hobbies))
+	for _, h := range p.Hobbies {
+		own[h] = true
+	}
+	pNorm := norm(p.Hobbies)
+
+	var ranked []SimilarPerson
+	for _, c := range candidates {
+		if c.ID == p.ID {
+			continue
+		}
+		s := SimilarPerson{Person: c}
+		dot := 0.0
+		for _, h := range c.Hobbies {
+			if own[h] {
+				dot += weight(h) * weight(h)
+				s.SharedHobbies = append(s.SharedHobbies, h)
+			}
+		}
+		if dot == 0 {
+			continue
+		}
+		s.Score = dot / (pNorm * norm(c.Hobbies))
+		ranked = append(ranked, s)
+	}
+	ageGap := func(s SimilarPerson) int32 {
+		if d := s.Person.Age - p.Age; d > 0 {
+			return d
+		}
+		return p.Age - s.Person.Age
+	}
+	slices.SortFunc(ranked, func(a, b SimilarPerson) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(ageGap(a), ageGap(b)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Person.Name, b.Person.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Person.ID.String(), b.Person.ID.String())
+	})
+	return ranked[:min(limit, len(ranked))]
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankSimilar(t *testing.T) {
+	p := NewPerson("Me", 30, []string{"Chess", "Reading", "Falconry"})
+	rare := NewPerson("Rare", 60, []string{"Falconry"})
+	common := NewPerson("Common", 31, []string{"Reading"})
+	older := NewPerson("Older", 50, []string{"Chess"})
+	younger := NewPerson("Younger", 28, []string{"Chess"})
+	none := NewPerson("None", 30, []string{"Golf"})
+	holders := map[string]int{"Chess": 3, "Reading": 10, "Falconry": 2, "Golf": 1}
+
+	ranked := RankSimilar(p, []Person{p, common, older, rare, younger, none}, holders, 20, 10)
+	require.Len(t, ranked, 4)
+	names := make([]string, len(ranked))
+	for i, s := range ranked {
+		names[i] = s.Person.Name
+	}
+	assert.Equal(t, []string{"Rare", "Younger", "Older", "Common"}, names)
+	assert.Equal(t, []string{"Falconry"}, ranked[0].SharedHobbies)
+	assert.Equal(t, ranked[1].Score, ranked[2].Score)
+	assert.Less(t, ranked[0].Score, 1.0)
+
+	assert.Len(t, RankSimilar(p, []Person{common, older, rare}, holders, 20, 2), 2)
+	same := RankSimilar(p, []Person{{Name: "Twin", Hobbies: p.Hobbies}}, holders, 20, 1)
+	assert.InDelta(t, 1, same[0].Score, 1e-9)
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// HobbyPair is two hobbies in alphabetical order.
+type HobbyPair [2]string
+
+// NewHobbyPair orders a and b.
+func NewHobbyPair(a, b string) HobbyPair {
+	if b < a {
+		a, b = b, a
+	}
+	return HobbyPair{a, b}
+}
+
+// Aggregates are counters a repository keeps up to date as persons change.
+type Aggregates struct {
+	Total int
+	// Ages counts persons by age.
+	Ages map[int32]int
+	// Hobbies counts persons by hobby.
+	Hobbies map[string]int
+	// HobbyPairs counts persons having both hobbies of a pair.
+	HobbyPairs map[HobbyPair]int
+}
+
+// StatsQuery shapes the Stats computed from Aggregates.
+type StatsQuery struct {
+	// Filter selects the persons summarized, as in a list.
+	Filter PersonFilter
+	// Buckets are the ascending lower bounds of the age histogram buckets
+	// after the first, which starts at 0.
+	Buckets []int32
+	// Percentiles of the ages to report, in (0, 100].
+	Percentiles []float64
+	// TopHobbies and TopPairs bound the hobbies and hobby pairs reported.
+	TopHobbies int
+	TopPairs   int
+}
+
+type AgeBucket struct {
+	Min int32
+	// Max is exclusive; 0 for the last, unbounded bucket.
+	Max   int32
+	Count int
+}
+
+type AgeStats struct {
+	Min    int32
+	Max    int32
+	Mean   float64
+	Median float64
+	// Percentiles maps the requested percentiles to ages.
+	Percentiles map[float64]float64
+	Histogram   []AgeBucket
+}
+
+type HobbyCount struct {
+	Hobby string
+	Count int
+}
+
+type HobbyPairCount struct {
+	Pair  HobbyPair
+	Count int
+}
+
+type Stats struct {
+	Total      int
+	Ages       AgeStats
+	TopHobbies []HobbyCount
+	HobbyPairs []HobbyPairCount
+}
+
+// ComputeStats derives Stats from a in time proportional to the number of
+// distinct ages, hobbies and hobby pairs rather than persons.
+func ComputeStats(a Aggregates, q StatsQuery) Stats {
+	stats := Stats{
+		Total: a.Total,
+		Ages:  ageStats(a.Ages, a.Total, q),
+	}
+	for hobby, n := range a.Hobbies {
+		stats.TopHobbies = append(stats.TopHobbies, HobbyCount{Hobby: hobby, Count: n})
+	}
+	slices.SortFunc(stats.TopHobbies, func(x, y HobbyCount) int {
+		if c := cmp.Compare(y.Count, x.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Hobby, y.Hobby)
+	})
+	stats.TopHobbies = stats.TopHobbies[:min(q.TopHobbies, len(stats.TopHobbies))]
+
+	for pair, n := range a.HobbyPairs {
+		stats.HobbyPairs = append(stats.HobbyPairs, HobbyPairCount{Pair: pair, Count: n})
+	}
+	slices.SortFunc(stats.HobbyPairs, func(x, y HobbyPairCount) int {
+		if c := cmp.Compare(y.Count, x.Count); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(x.Pair[0], y.Pair[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Pair[1], y.Pair[1])
+	})
+	stats.HobbyPairs = stats.HobbyPairs[:min(q.TopPairs, len(stats.HobbyPairs))]
+	return stats
+}
+
+func ageStats(counts map[int32]int, total int, q StatsQuery) AgeStats {
+	stats := AgeStats{Percentiles: make(map[float64]float64, len(q.Percentiles))}
+	bounds := append([]int32{0}, q.Buckets...)
+	for i, lo := range bounds {
+		bucket := AgeBucket{Min: lo}
+		if i+1 < len(bounds) {
+			bucket.Max = bounds[i+1]
+		}
+		stats.Histogram = append(stats.Histogram, bucket)
+	}
+	if total == 0 {
+		return stats
+	}
+
+	ages := make([]int32, 0, len(counts))
+	sum := 0.0
+	for age, n := range counts {
+		ages = append(ages, age)
+		sum += float64(age) * float64(n)
+		i, _ := slices.BinarySearch(bounds, age+1)
+		stats.Histogram[i-1].Count += n
+	}
+	slices.Sort(ages)
+	stats.Min, stats.Max = ages[0], ages[len(ages)-1]
+	stats.Mean = sum / float64(total)
+
+	// rank returns the age of the person at the 1-based rank in age order.
+	rank := func(r int) float64 {
+		for _, age := range ages {
+			if r -= counts[age]; r <= 0 {
+				return float64(age)
+			}
+		}
+		return float64(stats.Max)
+	}
+	if total%2 == 1 {
+		stats.Median = rank(total/2 + 1)
+	} else {
+		stats.Median = (rank(total/2) + rank(total/2+1)) / 2
+	}
+	// Nearest-rank percentiles.
+	for _, p := range q.Percentiles {
+		stats.Percentiles[p] = rank(max(1, int(math.Ceil(p/100*float64(total)))))
+	}
+	return stats
+}
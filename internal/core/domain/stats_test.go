@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeStats(t *testing.T) {
+	a := Aggregates{
+		Total:   6,
+		Ages:    map[int32]int{10: 1, 20: 2, 30: 1, 70: 2},
+		Hobbies: map[string]int{"Chess": 3, "Golf": 1, "Yoga": 3},
+		HobbyPairs: map[HobbyPair]int{
+			NewHobbyPair("Yoga", "Chess"): 2,
+			NewHobbyPair("Chess", "Golf"): 1,
+		},
+	}
+	stats := ComputeStats(a, StatsQuery{
+		Buckets:     []int32{18, 65},
+		Percentiles: []float64{50, 90, 100},
+		TopHobbies:  2,
+		TopPairs:    5,
+	})
+
+	assert.Equal(t, 6, stats.Total)
+	assert.Equal(t, int32(10), stats.Ages.Min)
+	assert.Equal(t, int32(70), stats.Ages.Max)
+	assert.InDelta(t, 36.667, stats.Ages.Mean, 0.001)
+	assert.Equal(t, 25.0, stats.Ages.Median)
+	assert.Equal(t, map[float64]float64{50: 20, 90: 70, 100: 70}, stats.Ages.Percentiles)
+	assert.Equal(t, []AgeBucket{{Min: 0, Max: 18, Count: 1}, {Min: 18, Max: 65, Count: 3}, {Min: 65, Count: 2}}, stats.Ages.Histogram)
+	assert.Equal(t, []HobbyCount{{"Chess", 3}, {"Yoga", 3}}, stats.TopHobbies)
+	assert.Equal(t, []HobbyPairCount{{HobbyPair{"Chess", "Yoga"}, 2}, {HobbyPair{"Chess", "Golf"}, 1}}, stats.HobbyPairs)
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	stats := ComputeStats(Aggregates{}, StatsQuery{Buckets: []int32{18}, Percentiles: []float64{50}, TopHobbies: 10})
+	assert.Zero(t, stats.Total)
+	assert.Zero(t, stats.Ages.Median)
+	assert.Len(t, stats.Ages.Histogram, 2)
+	assert.Empty(t, stats.TopHobbies)
+}
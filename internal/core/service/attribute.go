@@ -0,0 +1,69 @@
+package person
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// noAttributes is the schema of tenants that did not define one: persons
+// have no attributes.
+var noAttributes = domain.AttributeSchema{}
+
+// GetAttributeSchema returns the attribute schema of the context's tenant.
+func (s *PersonSvc) GetAttributeSchema(ctx context.Context) (domain.AttributeSchema, error) {
+	return s.repo.GetAttributeSchema(ctx, TenantFromContext(ctx))
+}
+
+// SetAttributeSchema makes schema the attribute schema of the context's
+// tenant. Stored persons are not rechecked; writes check the attributes
+// they change against it.
+func (s *PersonSvc) SetAttributeSchema(ctx context.Context, schema domain.AttributeSchema) (domain.AttributeSchema, error) {
+	schema, err := schema.Compile()
+	if err != nil {
+		return domain.AttributeSchema{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if err := s.repo.PutAttributeSchema(ctx, TenantFromContext(ctx), schema); err != nil {
+		return domain.AttributeSchema{}, err
+	}
+	return schema, nil
+}
+
+// validateAttributes checks the attributes of a new person against the
+// schema of the context's tenant and returns an AttributeError listing the
+// rejected ones.
+func (s *PersonSvc) validateAttributes(ctx context.Context, attrs domain.Attributes) error {
+	schema, err := s.tenantSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if fields := schema.Validate(attrs); len(fields) > 0 {
+		return &AttributeError{Fields: fields}
+	}
+	return nil
+}
+
+// validateAttributeChanges is validateAttributes for a stored person whose
+// attributes change from previous to attrs. Persons are shared by every
+// tenant, so only the attributes the write changes are checked against the
+// schema of the context's tenant.
+func (s *PersonSvc) validateAttributeChanges(ctx context.Context, previous, attrs domain.Attributes) error {
+	schema, err := s.tenantSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if fields := schema.ValidateChanges(previous, attrs); len(fields) > 0 {
+		return &AttributeError{Fields: fields}
+	}
+	return nil
+}
+
+func (s *PersonSvc) tenantSchema(ctx context.Context) (domain.AttributeSchema, error) {
+	schema, err := s.repo.GetAttributeSchema(ctx, TenantFromContext(ctx))
+	if errors.Is(err, ErrNotFound) {
+		return noAttributes, nil
+	}
+	return schema, err
+}
@@ -1,7 +1,88 @@
 package person
 
-import "errors"
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a write would break a unique index.
+	ErrConflict = errors.New("conflicts with an existing person")
+	// ErrInvalidMerge is returned for merges of a person into itself or
+	// with unsupported strategies.
+	ErrInvalidMerge = errors.New("invalid merge")
+	// ErrRelationshipExists is returned when adding a relationship twice.
+	ErrRelationshipExists = errors.New("relationship already exists")
+	// ErrSelfRelationship is returned for relationships of a person with
+	// itself.
+	ErrSelfRelationship = errors.New("a person can not be related to itself")
+	// ErrInvalidRelationship is returned for unknown relationship types.
+	ErrInvalidRelationship = errors.New("invalid relationship")
+	// ErrMemberExists is returned when adding a person to a group twice.
+	ErrMemberExists = errors.New("person is already a member of the group")
+	// ErrInvalidRole is returned for unknown group roles.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrNoPath is returned when no path within the query's depth connects
+	// two persons.
+	ErrNoPath = errors.New("no path between the persons")
+	// ErrInvalidSchema is returned for attribute schemas that are not
+	// valid JSON or use unsupported keywords.
+	ErrInvalidSchema = errors.New("invalid attribute schema")
+	// ErrInvalidAttributes is matched by AttributeError.
+	ErrInvalidAttributes = errors.New("invalid attributes")
 )
+
+// UniqueViolation reports the unique field whose value another person
+// already holds. It matches ErrConflict.
+type UniqueViolation struct {
+	Field string
+	// PersonID holds the value.
+	PersonID uuid.UUID
+}
+
+func (e *UniqueViolation) Error() string {
+	return e.Field + " is already in use"
+}
+
+func (e *UniqueViolation) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// MergedError is returned when reading a person that was merged into
+// another one. It matches ErrNotFound for callers that do not follow it.
+type MergedError struct {
+	// Into is the person that survived the merge.
+	Into uuid.UUID
+}
+
+func (e *MergedError) Error() string {
+	return "person was merged into " + e.Into.String()
+}
+
+func (e *MergedError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AttributeError reports the attributes of a person that the tenant's
+// schema rejects. It matches ErrInvalidAttributes.
+type AttributeError struct {
+	// Fields maps attribute names to what is wrong with them.
+	Fields map[string]string
+}
+
+func (e *AttributeError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return "invalid attributes: " + strings.Join(names, ", ")
+}
+
+func (e *AttributeError) Is(target error) bool {
+	return target == ErrInvalidAttributes
+}
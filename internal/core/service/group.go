@@ -0,0 +1,76 @@
+package person
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// GroupSvc manages groups of persons and their members.
+type GroupSvc struct {
+	repo GroupRepository
+}
+
+func NewGroupSvc(repo GroupRepository) *GroupSvc {
+	return &GroupSvc{repo: repo}
+}
+
+func (s *GroupSvc) AddGroup(ctx context.Context, group domain.Group) (domain.Group, error) {
+	return s.repo.AddGroup(ctx, group)
+}
+
+func (s *GroupSvc) GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	return s.repo.GetGroup(ctx, id)
+}
+
+func (s *GroupSvc) GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error) {
+	return s.repo.GetGroups(ctx, page, size)
+}
+
+func (s *GroupSvc) UpdateGroup(ctx context.Context, group domain.Group) (domain.Group, error) {
+	return s.repo.UpdateGroup(ctx, group)
+}
+
+// DeleteGroup deletes a group along with its memberships; the members
+// themselves are kept.
+func (s *GroupSvc) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteGroup(ctx, id)
+}
+
+func (s *GroupSvc) AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	if err := validateRole(role); err != nil {
+		return domain.Membership{}, err
+	}
+	return s.repo.AddMember(ctx, groupID, personID, role, time.Now().UTC())
+}
+
+func (s *GroupSvc) UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	if err := validateRole(role); err != nil {
+		return domain.Membership{}, err
+	}
+	return s.repo.UpdateMember(ctx, groupID, personID, role)
+}
+
+func (s *GroupSvc) RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error {
+	return s.repo.RemoveMember(ctx, groupID, personID)
+}
+
+func (s *GroupSvc) GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	return s.repo.GetMembers(ctx, groupID, page, size)
+}
+
+// GetPersonGroups returns the groups the person personID belongs to.
+func (s *GroupSvc) GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	return s.repo.GetPersonGroups(ctx, personID, page, size)
+}
+
+func validateRole(role domain.GroupRole) error {
+	if !slices.Contains(domain.GroupRoles, role) {
+		return fmt.Errorf("%w %q", ErrInvalidRole, role)
+	}
+	return nil
+}
@@ -2,22 +2,110 @@ package person
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lafetz/assessment/internal/core/domain"
 )
 
-type repository interface {
+// Repository is the storage port used by PersonSvc.
+type Repository interface {
 	AddPerson(ctx context.Context, person domain.Person) (domain.Person, error)
 	GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error)
-	GetPersons(ctx context.Context, page, size int32) ([]domain.Person, domain.Metadata, error)
+	GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error)
 	DeletePerson(ctx context.Context, id uuid.UUID) error
 	UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error)
+	// InTx runs fn in a transaction carried by the context passed to it.
+	// Writes using that context are kept only if fn returns nil.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// RecordMerge stores m in the target's history and leaves a tombstone
+	// redirecting reads of the source to the target.
+	RecordMerge(ctx context.Context, m domain.Merge) error
+	GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error)
+	// AddRelationship fails with ErrNotFound unless both persons exist.
+	// Deleting a person deletes its relationships.
+	AddRelationship(ctx context.Context, r domain.Relationship) error
+	DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error
+	// GetRelationships returns the relationships from and to id.
+	GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error)
+	ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error)
+	FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error)
+	// MoveMemberships moves the group memberships of the person from to the
+	// person to, keeping the role of to in groups it already belongs to.
+	MoveMemberships(ctx context.Context, from, to uuid.UUID) error
+	// HobbyCounts returns the number of persons having each stored hobby.
+	HobbyCounts(ctx context.Context) (map[string]int, error)
+	// Aggregates returns the counters of the persons matching filter. The
+	// counters of every person are kept up to date as persons change.
+	Aggregates(ctx context.Context, filter domain.PersonFilter) (domain.Aggregates, error)
+	// PersonsSharingHobbies returns the persons having any of hobbies.
+	PersonsSharingHobbies(ctx context.Context, hobbies []string) ([]domain.Person, error)
+	// GetAttributeSchema fails with ErrNotFound when the tenant has no
+	// schema.
+	GetAttributeSchema(ctx context.Context, tenant string) (domain.AttributeSchema, error)
+	PutAttributeSchema(ctx context.Context, tenant string, schema domain.AttributeSchema) error
 }
+
+// GroupRepository is the storage port used by GroupSvc. Deleting a group
+// or a person deletes its memberships. Lists are paginated like
+// Repository.GetPersons.
+type GroupRepository interface {
+	AddGroup(ctx context.Context, group domain.Group) (domain.Group, error)
+	GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error)
+	GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error)
+	UpdateGroup(ctx context.Context, group domain.Group) (domain.Group, error)
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+	AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole, joinedAt time.Time) (domain.Membership, error)
+	UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error)
+	RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error)
+	GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error)
+}
+
+// EventPublisher receives the events raised by PersonSvc once a change is
+// stored. Publish must not block on slow consumers.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event)
+}
+
+// Outbox stores events in the same transaction as the change that raised
+// them, so that they are relayed if and only if the change is stored. It
+// must take part in the transactions of the Repository.
+type Outbox interface {
+	AppendEvents(ctx context.Context, events ...domain.Event) error
+}
+
 type PersonSvcApi interface {
 	AddPerson(ctx context.Context, person domain.Person) (domain.Person, error)
 	GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error)
-	GetPersons(ctx context.Context, page, size int32) ([]domain.Person, domain.Metadata, error)
+	GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error)
 	DeletePerson(ctx context.Context, id uuid.UUID) error
 	UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error)
+	PatchPerson(ctx context.Context, id uuid.UUID, patch func(domain.Person) domain.Person) (domain.Person, error)
+	FindDuplicates(ctx context.Context, id uuid.UUID, minScore float64, limit int) ([]domain.DuplicateCandidate, error)
+	MergePersons(ctx context.Context, targetID, sourceID uuid.UUID, strategies domain.MergeStrategies) (domain.Person, error)
+	GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error)
+	AddRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) (domain.Relationship, error)
+	DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error
+	GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error)
+	ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error)
+	FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error)
+	SuggestHobbies(ctx context.Context, prefix string, limit int) ([]domain.HobbySuggestion, error)
+	Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error)
+	SimilarPersons(ctx context.Context, id uuid.UUID, limit int) ([]domain.SimilarPerson, error)
+	GetAttributeSchema(ctx context.Context) (domain.AttributeSchema, error)
+	SetAttributeSchema(ctx context.Context, schema domain.AttributeSchema) (domain.AttributeSchema, error)
+}
+
+type GroupSvcApi interface {
+	AddGroup(ctx context.Context, group domain.Group) (domain.Group, error)
+	GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error)
+	GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error)
+	UpdateGroup(ctx context.Context, group domain.Group) (domain.Group, error)
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+	AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error)
+	UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error)
+	RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error)
+	GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error)
 }
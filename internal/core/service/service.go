@@ -1,38 +1,399 @@
 package person
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/lafetz/assessment/internal/core/domain"
 )
 
 type PersonSvc struct {
-	repo repository
+	repo       Repository
+	outbox     Outbox
+	publishers []EventPublisher
+	hobbies    *domain.HobbyCatalog
+	order      publishOrder
 }
 
-func NewPersonSvc(repo repository) *PersonSvc {
-	return &PersonSvc{
-		repo: repo,
+// Option configures optional behaviour of the PersonSvc.
+type Option func(*PersonSvc)
+
+// WithPublisher adds a publisher notified of every stored change.
+func WithPublisher(p EventPublisher) Option {
+	return func(s *PersonSvc) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+// WithOutbox stores every event in o together with the change raising it.
+func WithOutbox(o Outbox) Option {
+	return func(s *PersonSvc) {
+		s.outbox = o
+	}
+}
+
+// WithHobbyCatalog normalizes the hobbies of stored persons against c.
+// Without it hobbies are only trimmed and deduplicated.
+func WithHobbyCatalog(c *domain.HobbyCatalog) Option {
+	return func(s *PersonSvc) {
+		s.hobbies = c
+	}
+}
+
+func NewPersonSvc(repo Repository, opts ...Option) *PersonSvc {
+	s := &PersonSvc{
+		repo:    repo,
+		hobbies: &domain.HobbyCatalog{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// write runs change, which returns the events it raises, in a transaction
+// together with appending the events to the outbox, then notifies the
+// publishers. Publishers see the events of concurrent writes in the order
+// the writes were committed.
+func (s *PersonSvc) write(ctx context.Context, change func(ctx context.Context) ([]domain.Event, error)) error {
+	var events []domain.Event
+	var turn uint64
+	taken := false
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if events, err = change(ctx); err != nil {
+			return err
+		}
+		if s.outbox != nil {
+			if err := s.outbox.AppendEvents(ctx, events...); err != nil {
+				return err
+			}
+		}
+		// Transactions are serialized, so turns are taken in commit order.
+		turn, taken = s.order.take(), true
+		return nil
+	})
+	if !taken {
+		return err
+	}
+	s.order.wait(turn)
+	defer s.order.done()
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		for _, p := range s.publishers {
+			p.Publish(ctx, e)
+		}
+	}
+	return nil
+}
+
+// publishOrder hands out turns to publish in the order they are taken.
+type publishOrder struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	next uint64
+	turn uint64
+}
+
+func (o *publishOrder) take() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.next++
+	return o.next
+}
+
+// wait blocks until turn is up. done must be called after.
+func (o *publishOrder) wait(turn uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.cond == nil {
+		o.cond = sync.NewCond(&o.mu)
+	}
+	for o.turn+1 != turn {
+		o.cond.Wait()
+	}
+}
+
+func (o *publishOrder) done() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.turn++
+	if o.cond != nil {
+		o.cond.Broadcast()
 	}
 }
 
 func (s *PersonSvc) AddPerson(ctx context.Context, person domain.Person) (domain.Person, error) {
-	return s.repo.AddPerson(ctx, person)
+	var added domain.Person
+	person.Hobbies = s.hobbies.Normalize(person.Hobbies)
+	if err := s.validateAttributes(ctx, person.Attributes); err != nil {
+		return domain.Person{}, err
+	}
+	err := s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		var err error
+		added, err = s.repo.AddPerson(ctx, person)
+		return []domain.Event{domain.NewEvent(domain.EventPersonCreated, added)}, err
+	})
+	if err != nil {
+		return domain.Person{}, err
+	}
+	return added, nil
 }
 
 func (s *PersonSvc) GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error) {
 	return s.repo.GetPerson(ctx, id)
 }
 
-func (s *PersonSvc) GetPersons(ctx context.Context, page, size int32) ([]domain.Person, domain.Metadata, error) {
-	return s.repo.GetPersons(ctx, page, size)
+func (s *PersonSvc) GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error) {
+	return s.repo.GetPersons(ctx, filter, page, size)
 }
 
 func (s *PersonSvc) DeletePerson(ctx context.Context, id uuid.UUID) error {
-	return s.repo.DeletePerson(ctx, id)
+	return s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		err := s.repo.DeletePerson(ctx, id)
+		return []domain.Event{domain.NewEvent(domain.EventPersonDeleted, domain.Person{ID: id})}, err
+	})
 }
 
 func (s *PersonSvc) UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error) {
-	return s.repo.UpdatePerson(ctx, person)
+	var updated domain.Person
+	person.Hobbies = s.hobbies.Normalize(person.Hobbies)
+	err := s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		current, err := s.repo.GetPerson(ctx, person.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateAttributeChanges(ctx, current.Attributes, person.Attributes); err != nil {
+			return nil, err
+		}
+		updated, err = s.repo.UpdatePerson(ctx, person)
+		return []domain.Event{domain.NewEvent(domain.EventPersonUpdated, updated)}, err
+	})
+	if err != nil {
+		return domain.Person{}, err
+	}
+	return updated, nil
+}
+
+// PatchPerson stores the person with id as changed by patch. Reading,
+// patching and storing happen in one transaction, so a concurrent update
+// is not lost.
+func (s *PersonSvc) PatchPerson(ctx context.Context, id uuid.UUID, patch func(domain.Person) domain.Person) (domain.Person, error) {
+	var updated domain.Person
+	err := s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		current, err := s.repo.GetPerson(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		person := patch(current)
+		person.ID = id
+		person.Hobbies = s.hobbies.Normalize(person.Hobbies)
+		if err := s.validateAttributeChanges(ctx, current.Attributes, person.Attributes); err != nil {
+			return nil, err
+		}
+		updated, err = s.repo.UpdatePerson(ctx, person)
+		return []domain.Event{domain.NewEvent(domain.EventPersonUpdated, updated)}, err
+	})
+	if err != nil {
+		return domain.Person{}, err
+	}
+	return updated, nil
+}
+
+// scanPageSize is the page size used when reading every person.
+const scanPageSize = 500
+
+// FindDuplicates returns up to limit persons scoring at least minScore as
+// duplicates of the person with id, best match first.
+func (s *PersonSvc) FindDuplicates(ctx context.Context, id uuid.UUID, minScore float64, limit int) ([]domain.DuplicateCandidate, error) {
+	p, err := s.repo.GetPerson(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []domain.DuplicateCandidate{}
+	for page := int32(0); ; page++ {
+		persons, _, err := s.repo.GetPersons(ctx, domain.PersonFilter{}, page, scanPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range persons {
+			if other.ID == id {
+				continue
+			}
+			if c := domain.ScoreDuplicate(p, other); c.Score >= minScore {
+				candidates = append(candidates, c)
+			}
+		}
+		if len(persons) < scanPageSize {
+			break
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b domain.DuplicateCandidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// MergePersons folds the person sourceID into targetID, taking each field
+// from the record strategies select. The source is deleted, reads of it
+// are redirected to the target, its relationships and group memberships
+// move to the target and the merge is kept in the target's
+// history, all in one transaction.
+func (s *PersonSvc) MergePersons(ctx context.Context, targetID, sourceID uuid.UUID, strategies domain.MergeStrategies) (domain.Person, error) {
+	if targetID == sourceID {
+		return domain.Person{}, fmt.Errorf("%w: a person can not be merged into itself", ErrInvalidMerge)
+	}
+	if err := strategies.Validate(); err != nil {
+		return domain.Person{}, fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+	}
+	var merged domain.Person
+	err := s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		target, err := s.repo.GetPerson(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		source, err := s.repo.GetPerson(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.moveRelationships(ctx, sourceID, targetID); err != nil {
+			return nil, err
+		}
+		if err := s.repo.MoveMemberships(ctx, sourceID, targetID); err != nil {
+			return nil, err
+		}
+		// Delete first so that unique values kept from the source are free.
+		if err := s.repo.DeletePerson(ctx, sourceID); err != nil {
+			return nil, err
+		}
+		merged = domain.MergePersons(target, source, strategies)
+		merged.Hobbies = s.hobbies.Normalize(merged.Hobbies)
+		if err := s.validateAttributeChanges(ctx, target.Attributes, merged.Attributes); err != nil {
+			return nil, err
+		}
+		if merged, err = s.repo.UpdatePerson(ctx, merged); err != nil {
+			return nil, err
+		}
+		if err := s.repo.RecordMerge(ctx, domain.NewMerge(targetID, source, strategies)); err != nil {
+			return nil, err
+		}
+		return []domain.Event{
+			domain.NewEvent(domain.EventPersonDeleted, domain.Person{ID: sourceID}),
+			domain.NewEvent(domain.EventPersonUpdated, merged),
+		}, nil
+	})
+	if err != nil {
+		return domain.Person{}, err
+	}
+	return merged, nil
+}
+
+// GetMerges returns the merges into the person targetID, oldest first.
+func (s *PersonSvc) GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error) {
+	if _, err := s.repo.GetPerson(ctx, targetID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMerges(ctx, targetID)
+}
+
+// moveRelationships copies the relationships of the person from to the
+// person to, skipping those it already has or that would relate it to
+// itself. Deleting from removes the originals.
+func (s *PersonSvc) moveRelationships(ctx context.Context, from, to uuid.UUID) error {
+	rels, err := s.repo.GetRelationships(ctx, from)
+	if err != nil {
+		return err
+	}
+	for _, r := range rels {
+		if r.FromID == from {
+			r.FromID = to
+		} else {
+			r.ToID = to
+		}
+		err := s.repo.AddRelationship(ctx, r)
+		if err != nil && !errors.Is(err, ErrRelationshipExists) && !errors.Is(err, ErrSelfRelationship) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PersonSvc) AddRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) (domain.Relationship, error) {
+	if !slices.Contains(domain.RelationTypes, t) {
+		return domain.Relationship{}, fmt.Errorf("%w: unknown relationship type %q", ErrInvalidRelationship, t)
+	}
+	r := domain.NewRelationship(from, to, t)
+	if err := s.repo.AddRelationship(ctx, r); err != nil {
+		return domain.Relationship{}, err
+	}
+	return r, nil
+}
+
+func (s *PersonSvc) DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error {
+	return s.repo.DeleteRelationship(ctx, from, to, t)
+}
+
+// GetRelationships returns the relationships from and to the person id,
+// oldest first.
+func (s *PersonSvc) GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error) {
+	return s.repo.GetRelationships(ctx, id)
+}
+
+// ShortestPath returns a path with the fewest relationships allowed by q
+// between two persons, or ErrNoPath.
+func (s *PersonSvc) ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error) {
+	return s.repo.ShortestPath(ctx, from, to, q)
+}
+
+// FriendsOfFriends suggests persons sharing friends with the person id.
+func (s *PersonSvc) FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error) {
+	return s.repo.FriendsOfFriends(ctx, id)
+}
+
+// SuggestHobbies completes prefix with up to limit catalogued or stored
+// hobbies, the ones most persons have first.
+func (s *PersonSvc) SuggestHobbies(ctx context.Context, prefix string, limit int) ([]domain.HobbySuggestion, error) {
+	counts, err := s.repo.HobbyCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.hobbies.Suggest(prefix, counts, limit), nil
+}
+
+// Stats summarizes the persons matching q.Filter from the repository's
+// aggregates.
+func (s *PersonSvc) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	aggregates, err := s.repo.Aggregates(ctx, q.Filter)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	return domain.ComputeStats(aggregates, q), nil
+}
+
+// SimilarPersons ranks the persons sharing hobbies with the person id by
+// hobby similarity and returns the first limit.
+func (s *PersonSvc) SimilarPersons(ctx context.Context, id uuid.UUID, limit int) ([]domain.SimilarPerson, error) {
+	p, err := s.repo.GetPerson(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repo.PersonsSharingHobbies(ctx, p.Hobbies)
+	if err != nil {
+		return nil, err
+	}
+	aggregates, err := s.repo.Aggregates(ctx, domain.PersonFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return domain.RankSimilar(p, candidates, aggregates.Hobbies, aggregates.Total, limit), nil
 }
@@ -0,0 +1,21 @@
+package person
+
+import "context"
+
+// DefaultTenant is the tenant of requests that do not name one.
+const DefaultTenant = "default"
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx acting on behalf of tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, or DefaultTenant.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+const (
+	DefaultBufferSize = 1024
+	// subscriberBuffer is how many events a subscriber may fall behind
+	// before it is dropped.
+	subscriberBuffer = 64
+)
+
+var (
+	ErrSlowConsumer = errors.New("events: subscriber fell too far behind")
+	ErrClosed       = errors.New("events: bus closed")
+)
+
+// Event is a domain event numbered in publishing order. Sequence numbers
+// start at 1 and restart with the process.
+type Event struct {
+	Seq uint64
+	domain.Event
+}
+
+// Bus fans person events out to live subscribers and keeps the most recent
+// ones so that subscribers can resume after reconnecting. It implements
+// person.EventPublisher.
+type Bus struct {
+	mu     sync.Mutex
+	buf    []Event
+	size   int
+	seq    uint64
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewBus(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Bus{
+		size: bufferSize,
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events on C until it is closed with Unsubscribe or
+// dropped for falling behind, after which C is closed.
+type Subscription struct {
+	C   <-chan Event
+	c   chan Event
+	err error
+}
+
+// Err reports why C was closed: ErrSlowConsumer, ErrClosed or nil after
+// Unsubscribe. It must only be called once C is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Publish numbers event, buffers it and passes it to every subscriber.
+// Subscribers whose channel is full are dropped instead of blocking.
+func (b *Bus) Publish(_ context.Context, event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.seq++
+	e := Event{Seq: b.seq, Event: event}
+	if len(b.buf) == b.size {
+		copy(b.buf, b.buf[1:])
+		b.buf[len(b.buf)-1] = e
+	} else {
+		b.buf = append(b.buf, e)
+	}
+	for sub := range b.subs {
+		select {
+		case sub.c <- e:
+		default:
+			b.drop(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Subscribe starts a subscription. With resume set, the buffered events
+// after lastSeq are returned to be sent first; complete is false when some
+// of them are no longer buffered or lastSeq is unknown to this process.
+func (b *Bus) Subscribe(lastSeq uint64, resume bool) (sub *Subscription, backlog []Event, complete bool) {
+	c := make(chan Event, subscriberBuffer)
+	sub = &Subscription{C: c, c: c}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		sub.err = ErrClosed
+		close(c)
+		return sub, nil, true
+	}
+	b.subs[sub] = struct{}{}
+	if !resume {
+		return sub, nil, true
+	}
+	complete = lastSeq <= b.seq
+	if len(b.buf) > 0 && b.buf[0].Seq > lastSeq+1 {
+		complete = false
+	}
+	for _, e := range b.buf {
+		if e.Seq > lastSeq {
+			backlog = append(backlog, e)
+		}
+	}
+	return sub, backlog, complete
+}
+
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(sub, nil)
+}
+
+// Close ends every subscription, e.g. to let streaming responses finish on
+// shutdown. Later events are discarded.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub, ErrClosed)
+	}
+}
+
+// drop removes and closes sub. b.mu must be held.
+func (b *Bus) drop(sub *Subscription, reason error) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		sub.err = reason
+		close(sub.c)
+	}
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func publish(b *Bus, n int) {
+	for i := 0; i < n; i++ {
+		b.Publish(context.Background(), domain.NewEvent(domain.EventPersonCreated, domain.NewPerson("Alice", 30, nil)))
+	}
+}
+
+func seqs(events []Event) []uint64 {
+	s := make([]uint64, len(events))
+	for i, e := range events {
+		s[i] = e.Seq
+	}
+	return s
+}
+
+func TestSubscribe(t *testing.T) {
+	b := NewBus(3)
+	sub, _, _ := b.Subscribe(0, false)
+	publish(b, 2)
+
+	assert.Equal(t, uint64(1), (<-sub.C).Seq)
+	assert.Equal(t, uint64(2), (<-sub.C).Seq)
+
+	b.Unsubscribe(sub)
+	_, open := <-sub.C
+	assert.False(t, open)
+}
+
+func TestSubscribe_Resume(t *testing.T) {
+	b := NewBus(3)
+	publish(b, 5)
+
+	tests := []struct {
+		name             string
+		lastSeq          uint64
+		expectedBacklog  []uint64
+		expectedComplete bool
+	}{
+		{"up to date", 5, []uint64{}, true},
+		{"within buffer", 2, []uint64{3, 4, 5}, true},
+		{"before buffer", 1, []uint64{3, 4, 5}, false},
+		{"from a previous process", 9, []uint64{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, complete := b.Subscribe(tt.lastSeq, true)
+			defer b.Unsubscribe(sub)
+			assert.Equal(t, tt.expectedBacklog, seqs(backlog))
+			assert.Equal(t, tt.expectedComplete, complete)
+		})
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	b := NewBus(DefaultBufferSize)
+	slow, _, _ := b.Subscribe(0, false)
+	publish(b, subscriberBuffer+1)
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+}
+
+func TestClose(t *testing.T) {
+	b := NewBus(1)
+	sub, _, _ := b.Subscribe(0, false)
+	b.Close()
+	_, open := <-sub.C
+	assert.False(t, open)
+	assert.ErrorIs(t, sub.Err(), ErrClosed)
+
+	late, _, _ := b.Subscribe(0, false)
+	_, open = <-late.C
+	assert.False(t, open)
+}
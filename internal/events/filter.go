@@ -0,0 +1,203 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Filter matches events against an expression of comparisons joined by
+// "&&" and "||", where "&&" binds tighter, e.g.
+//
+//	type == person.created && age >= 18 || hobby == chess
+//
+// Fields are type, personId, name, age and hobby; hobby == x matches
+// persons having hobby x. Values containing spaces are double quoted. Only
+// type and personId are known for deletions, so other comparisons never
+// match them. The zero Filter matches every event.
+type Filter struct {
+	// any holds the "||" alternatives, each a list of comparisons that must
+	// all hold.
+	any [][]comparison
+}
+
+type comparison struct {
+	field string
+	op    string
+	value string
+	num   int64
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func ParseFilter(expr string) (Filter, error) {
+	var f Filter
+	if strings.TrimSpace(expr) == "" {
+		return f, nil
+	}
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return Filter{}, err
+	}
+	var all []comparison
+	for len(tokens) > 0 {
+		if len(tokens) < 3 {
+			return Filter{}, fmt.Errorf("%w: incomplete comparison %q", ErrInvalidFilter, strings.Join(tokens, " "))
+		}
+		c, err := newComparison(tokens[0], tokens[1], tokens[2])
+		if err != nil {
+			return Filter{}, err
+		}
+		all = append(all, c)
+		tokens = tokens[3:]
+		if len(tokens) == 0 {
+			break
+		}
+		switch tokens[0] {
+		case "&&":
+		case "||":
+			f.any = append(f.any, all)
+			all = nil
+		default:
+			return Filter{}, fmt.Errorf("%w: expected && or || before %q", ErrInvalidFilter, tokens[0])
+		}
+		tokens = tokens[1:]
+		if len(tokens) == 0 {
+			return Filter{}, fmt.Errorf("%w: expression ends with an operator", ErrInvalidFilter)
+		}
+	}
+	f.any = append(f.any, all)
+	return f, nil
+}
+
+func newComparison(field, op, value string) (comparison, error) {
+	c := comparison{field: field, op: op, value: value}
+	if !slices.Contains(filterOps, op) {
+		return c, fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, op)
+	}
+	ordered := op != "==" && op != "!="
+	switch field {
+	case "age":
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return c, fmt.Errorf("%w: age %q is not a number", ErrInvalidFilter, value)
+		}
+		c.num = n
+		return c, nil
+	case "type":
+		if !slices.Contains(domain.EventTypes, domain.EventType(value)) {
+			return c, fmt.Errorf("%w: unknown event type %q", ErrInvalidFilter, value)
+		}
+	case "personId", "name", "hobby":
+	default:
+		return c, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, field)
+	}
+	if ordered {
+		return c, fmt.Errorf("%w: %s only supports == and !=", ErrInvalidFilter, field)
+	}
+	return c, nil
+}
+
+// tokenize splits expr into words, operators and quoted strings.
+func tokenize(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		switch ch := rune(expr[i]); {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '"':
+			end := strings.IndexByte(expr[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidFilter)
+			}
+			tokens = append(tokens, expr[i+1:i+1+end])
+			i += end + 2
+		case strings.ContainsRune("=!<>&|", ch):
+			j := i
+			for j < len(expr) && strings.ContainsRune("=!<>&|", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(expr) && !unicode.IsSpace(rune(expr[j])) && !strings.ContainsRune(`=!<>&|"`, rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// Matches reports whether e satisfies the filter.
+func (f Filter) Matches(e domain.Event) bool {
+	if len(f.any) == 0 {
+		return true
+	}
+	for _, all := range f.any {
+		if matchesAll(all, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(all []comparison, e domain.Event) bool {
+	for _, c := range all {
+		if !c.matches(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c comparison) matches(e domain.Event) bool {
+	deleted := e.Type == domain.EventPersonDeleted
+	switch c.field {
+	case "type":
+		return c.equal(string(e.Type) == c.value)
+	case "personId":
+		return c.equal(strings.EqualFold(e.Person.ID.String(), c.value))
+	case "name":
+		return !deleted && c.equal(e.Person.Name == c.value)
+	case "hobby":
+		return !deleted && c.equal(slices.Contains(e.Person.Hobbies, c.value))
+	case "age":
+		if deleted {
+			return false
+		}
+		age := int64(e.Person.Age)
+		switch c.op {
+		case "==":
+			return age == c.num
+		case "!=":
+			return age != c.num
+		case "<":
+			return age < c.num
+		case "<=":
+			return age <= c.num
+		case ">":
+			return age > c.num
+		case ">=":
+			return age >= c.num
+		}
+	}
+	return false
+}
+
+// equal applies == or != to the outcome of an equality test.
+func (c comparison) equal(eq bool) bool {
+	if c.op == "!=" {
+		return !eq
+	}
+	return eq
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	alice := domain.NewPerson("Alice Smith", 30, []string{"chess", "running"})
+	created := domain.NewEvent(domain.EventPersonCreated, alice)
+	deleted := domain.NewEvent(domain.EventPersonDeleted, domain.Person{ID: alice.ID})
+
+	tests := []struct {
+		expr        string
+		wantCreated bool
+		wantDeleted bool
+	}{
+		{"", true, true},
+		{"type == person.created", true, false},
+		{"type != person.created", false, true},
+		{"personId == " + alice.ID.String(), true, true},
+		{`name == "Alice Smith"`, true, false},
+		{"age >= 18 && age < 31", true, false},
+		{"age > 30", false, false},
+		{"hobby == chess", true, false},
+		{"hobby != chess", false, false},
+		{"age > 40 || type == person.deleted", false, true},
+		{"type == person.created && age > 40 || hobby == running", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCreated, f.Matches(created), "created")
+			assert.Equal(t, tt.wantDeleted, f.Matches(deleted), "deleted")
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"age",
+		"age >= old",
+		"colour == red",
+		"name > bob",
+		"type == person.renamed",
+		"age == 1 &&",
+		"age == 1 age == 2",
+		`name == "unterminated`,
+		"age ~ 3",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseFilter(expr)
+			assert.ErrorIs(t, err, ErrInvalidFilter)
+		})
+	}
+}
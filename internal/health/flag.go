@@ -0,0 +1,29 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// Flag is a Checker that fails until Set is called, e.g. to gate readiness
+// on a one-off startup task such as seeding.
+type Flag struct {
+	done atomic.Bool
+	err  error
+}
+
+func NewFlag(pending string) *Flag {
+	return &Flag{err: errors.New(pending)}
+}
+
+func (f *Flag) Set() {
+	f.done.Store(true)
+}
+
+func (f *Flag) Check(ctx context.Context) error {
+	if !f.done.Load() {
+		return f.err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultCheckTimeout = 2 * time.Second
+
+var ErrShuttingDown = errors.New("shutting down")
+
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusReady    = "ready"
+	StatusNotReady = "not ready"
+)
+
+// Checker reports whether a dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Health serves the liveness and readiness endpoints. Readiness is the
+// aggregate of all registered checkers and is forced to not ready once
+// shutdown has started.
+type Health struct {
+	mu           sync.RWMutex
+	checkers     []namedChecker
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func New(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Health{
+		timeout: timeout,
+	}
+}
+
+// Register adds a readiness check under name.
+func (h *Health) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers = append(h.checkers, namedChecker{name: name, checker: checker})
+}
+
+// Shutdown marks the service as not ready so load balancers stop routing
+// new traffic to it.
+func (h *Health) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+type CheckResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Ready runs every checker concurrently and aggregates the results.
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	checkers := append([]namedChecker(nil), h.checkers...)
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c namedChecker) {
+			defer wg.Done()
+			start := time.Now()
+			err := c.checker.Check(ctx)
+			results[i] = CheckResult{Status: StatusOK, Latency: time.Since(start).String()}
+			if err != nil {
+				results[i].Status = StatusFailing
+				results[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Checks: make(map[string]CheckResult, len(checkers)+1)}
+	for i, c := range checkers {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusNotReady
+		}
+	}
+	if h.shuttingDown.Load() {
+		report.Status = StatusNotReady
+		report.Checks["shutdown"] = CheckResult{Status: StatusFailing, Latency: "0s", Error: ErrShuttingDown.Error()}
+	}
+	return report
+}
+
+// LivenessHandler reports that the process is up and serving requests.
+func (h *Health) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+	}
+}
+
+// ReadinessHandler responds 200 when every check passes and 503 otherwise.
+func (h *Health) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := h.Ready(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusReady {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
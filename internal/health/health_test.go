@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveReady(t *testing.T, h *Health) (int, Report) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	return w.Code, report
+}
+
+func TestLivenessHandler(t *testing.T) {
+	h := New(0)
+	h.Register("broken", CheckerFunc(func(ctx context.Context) error { return errors.New("down") }))
+	w := httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	seeded := NewFlag("seeding in progress")
+	h := New(0)
+	h.Register("repository", CheckerFunc(func(ctx context.Context) error { return nil }))
+	h.Register("seed", seeded)
+
+	code, report := serveReady(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusNotReady, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["repository"].Status)
+	assert.NotEmpty(t, report.Checks["repository"].Latency)
+	assert.Equal(t, StatusFailing, report.Checks["seed"].Status)
+	assert.Equal(t, "seeding in progress", report.Checks["seed"].Error)
+
+	seeded.Set()
+	code, report = serveReady(t, h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusReady, report.Status)
+	assert.Len(t, report.Checks, 2)
+}
+
+func TestReadinessHandler_Timeout(t *testing.T) {
+	h := New(10 * time.Millisecond)
+	h.Register("slow", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	code, report := serveReady(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestReadinessHandler_Shutdown(t *testing.T) {
+	h := New(0)
+	h.Register("repository", CheckerFunc(func(ctx context.Context) error { return nil }))
+	h.Shutdown()
+
+	code, report := serveReady(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusNotReady, report.Status)
+	assert.Equal(t, ErrShuttingDown.Error(), report.Checks["shutdown"].Error)
+}
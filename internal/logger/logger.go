@@ -5,14 +5,16 @@ import (
 	"os"
 )
 
-func NewLogger(logLevel slog.Level, env string) *slog.Logger {
+// NewLogger creates the application logger. Pass a *slog.LevelVar as
+// logLevel to change the level at runtime.
+func NewLogger(logLevel slog.Leveler, env string) *slog.Logger {
 	var logHandler slog.Handler
 
 	switch env {
 	case "development":
 		logHandler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 			AddSource: true,
-			Level:     slog.LevelDebug,
+			Level:     logLevel,
 		})
 	default:
 		logHandler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
@@ -21,6 +23,6 @@ func NewLogger(logLevel slog.Level, env string) *slog.Logger {
 		})
 
 	}
-	logger := slog.New(logHandler)
+	logger := slog.New(traceHandler{logHandler})
 	return logger
 }
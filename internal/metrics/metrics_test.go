@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(w.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func TestRequestStarted(t *testing.T) {
+	m := New()
+	done := m.RequestStarted(http.MethodGet, "/api/v1/persons/{personId}")
+	assert.Contains(t, scrape(t, m), `persons_api_http_requests_in_flight{method="GET",route="/api/v1/persons/{personId}"} 1`)
+
+	done(http.StatusNotFound)
+	body := scrape(t, m)
+	assert.Contains(t, body, `persons_api_http_requests_in_flight{method="GET",route="/api/v1/persons/{personId}"} 0`)
+	assert.Contains(t, body, `persons_api_http_requests_total{method="GET",route="/api/v1/persons/{personId}",status="404"} 1`)
+	assert.Contains(t, body, `persons_api_http_request_duration_seconds_count{method="GET",route="/api/v1/persons/{personId}",status="404"} 1`)
+}
+
+func TestRegisterPersonsTotal(t *testing.T) {
+	m := New()
+	repo := repository.NewRepository()
+	m.RegisterPersonsTotal(repo.Count)
+
+	_, err := repo.AddPerson(context.Background(), domain.NewPerson("John", 30, []string{"Reading"}))
+	assert.NoError(t, err)
+	assert.Contains(t, scrape(t, m), "persons_api_persons_total 1")
+}
+
+func TestValidationFailed(t *testing.T) {
+	m := New()
+	m.ValidationFailed("age", "gte")
+	m.ValidationFailed("age", "gte")
+	assert.Contains(t, scrape(t, m), `persons_api_validation_failures_total{field="age",rule="gte"} 2`)
+}
+
+func TestRepository(t *testing.T) {
+	m := New()
+	store := repository.NewRepository()
+	repo := NewRepository(store, m)
+	groups := NewGroupRepository(store, m)
+
+	_, err := repo.AddPerson(context.Background(), domain.NewPerson("John", 30, []string{"Reading"}))
+	assert.NoError(t, err)
+	_, err = repo.GetPerson(context.Background(), uuid.New())
+	assert.Error(t, err)
+	_, err = repo.Aggregates(context.Background(), domain.PersonFilter{})
+	assert.NoError(t, err)
+	_, err = groups.AddGroup(context.Background(), domain.NewGroup("Platform", ""))
+	assert.NoError(t, err)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `persons_api_repository_operation_duration_seconds_count{operation="Aggregates",outcome="success"} 1`)
+	assert.Contains(t, body, `persons_api_repository_operation_duration_seconds_count{operation="AddGroup",outcome="success"} 1`)
+	assert.Contains(t, body, `persons_api_repository_operation_duration_seconds_count{operation="AddPerson",outcome="success"} 1`)
+	assert.Contains(t, body, `persons_api_repository_operation_duration_seconds_count{operation="GetPerson",outcome="error"} 1`)
+}
@@ -0,0 +1,286 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/outbox"
+)
+
+// Repository records the latency of every call to the wrapped repository.
+type Repository struct {
+	next    person.Repository
+	metrics *Metrics
+}
+
+var _ person.Repository = (*Repository)(nil)
+
+func NewRepository(next person.Repository, m *Metrics) *Repository {
+	return &Repository{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (r *Repository) AddPerson(ctx context.Context, p domain.Person) (domain.Person, error) {
+	start := time.Now()
+	p, err := r.next.AddPerson(ctx, p)
+	r.metrics.observeRepository("AddPerson", start, err)
+	return p, err
+}
+
+func (r *Repository) GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error) {
+	start := time.Now()
+	p, err := r.next.GetPerson(ctx, id)
+	r.metrics.observeRepository("GetPerson", start, err)
+	return p, err
+}
+
+func (r *Repository) GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error) {
+	start := time.Now()
+	persons, meta, err := r.next.GetPersons(ctx, filter, page, size)
+	r.metrics.observeRepository("GetPersons", start, err)
+	return persons, meta, err
+}
+
+func (r *Repository) DeletePerson(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.DeletePerson(ctx, id)
+	r.metrics.observeRepository("DeletePerson", start, err)
+	return err
+}
+
+func (r *Repository) UpdatePerson(ctx context.Context, p domain.Person) (domain.Person, error) {
+	start := time.Now()
+	p, err := r.next.UpdatePerson(ctx, p)
+	r.metrics.observeRepository("UpdatePerson", start, err)
+	return p, err
+}
+
+func (r *Repository) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := r.next.InTx(ctx, fn)
+	r.metrics.observeRepository("InTx", start, err)
+	return err
+}
+
+func (r *Repository) RecordMerge(ctx context.Context, m domain.Merge) error {
+	start := time.Now()
+	err := r.next.RecordMerge(ctx, m)
+	r.metrics.observeRepository("RecordMerge", start, err)
+	return err
+}
+
+func (r *Repository) GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error) {
+	start := time.Now()
+	merges, err := r.next.GetMerges(ctx, targetID)
+	r.metrics.observeRepository("GetMerges", start, err)
+	return merges, err
+}
+
+func (r *Repository) AddRelationship(ctx context.Context, rel domain.Relationship) error {
+	start := time.Now()
+	err := r.next.AddRelationship(ctx, rel)
+	r.metrics.observeRepository("AddRelationship", start, err)
+	return err
+}
+
+func (r *Repository) DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error {
+	start := time.Now()
+	err := r.next.DeleteRelationship(ctx, from, to, t)
+	r.metrics.observeRepository("DeleteRelationship", start, err)
+	return err
+}
+
+func (r *Repository) GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error) {
+	start := time.Now()
+	rels, err := r.next.GetRelationships(ctx, id)
+	r.metrics.observeRepository("GetRelationships", start, err)
+	return rels, err
+}
+
+func (r *Repository) ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error) {
+	start := time.Now()
+	path, err := r.next.ShortestPath(ctx, from, to, q)
+	r.metrics.observeRepository("ShortestPath", start, err)
+	return path, err
+}
+
+func (r *Repository) FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error) {
+	start := time.Now()
+	suggestions, err := r.next.FriendsOfFriends(ctx, id)
+	r.metrics.observeRepository("FriendsOfFriends", start, err)
+	return suggestions, err
+}
+
+func (r *Repository) MoveMemberships(ctx context.Context, from, to uuid.UUID) error {
+	start := time.Now()
+	err := r.next.MoveMemberships(ctx, from, to)
+	r.metrics.observeRepository("MoveMemberships", start, err)
+	return err
+}
+
+func (r *Repository) HobbyCounts(ctx context.Context) (map[string]int, error) {
+	start := time.Now()
+	counts, err := r.next.HobbyCounts(ctx)
+	r.metrics.observeRepository("HobbyCounts", start, err)
+	return counts, err
+}
+
+func (r *Repository) Aggregates(ctx context.Context, filter domain.PersonFilter) (domain.Aggregates, error) {
+	start := time.Now()
+	aggregates, err := r.next.Aggregates(ctx, filter)
+	r.metrics.observeRepository("Aggregates", start, err)
+	return aggregates, err
+}
+
+func (r *Repository) PersonsSharingHobbies(ctx context.Context, hobbies []string) ([]domain.Person, error) {
+	start := time.Now()
+	persons, err := r.next.PersonsSharingHobbies(ctx, hobbies)
+	r.metrics.observeRepository("PersonsSharingHobbies", start, err)
+	return persons, err
+}
+
+func (r *Repository) GetAttributeSchema(ctx context.Context, tenant string) (domain.AttributeSchema, error) {
+	start := time.Now()
+	schema, err := r.next.GetAttributeSchema(ctx, tenant)
+	r.metrics.observeRepository("GetAttributeSchema", start, err)
+	return schema, err
+}
+
+func (r *Repository) PutAttributeSchema(ctx context.Context, tenant string, schema domain.AttributeSchema) error {
+	start := time.Now()
+	err := r.next.PutAttributeSchema(ctx, tenant, schema)
+	r.metrics.observeRepository("PutAttributeSchema", start, err)
+	return err
+}
+
+// GroupRepository records the latency of every call to the wrapped group
+// repository.
+type GroupRepository struct {
+	next    person.GroupRepository
+	metrics *Metrics
+}
+
+var _ person.GroupRepository = (*GroupRepository)(nil)
+
+func NewGroupRepository(next person.GroupRepository, m *Metrics) *GroupRepository {
+	return &GroupRepository{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (r *GroupRepository) AddGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	start := time.Now()
+	g, err := r.next.AddGroup(ctx, g)
+	r.metrics.observeRepository("AddGroup", start, err)
+	return g, err
+}
+
+func (r *GroupRepository) GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	start := time.Now()
+	g, err := r.next.GetGroup(ctx, id)
+	r.metrics.observeRepository("GetGroup", start, err)
+	return g, err
+}
+
+func (r *GroupRepository) GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error) {
+	start := time.Now()
+	groups, meta, err := r.next.GetGroups(ctx, page, size)
+	r.metrics.observeRepository("GetGroups", start, err)
+	return groups, meta, err
+}
+
+func (r *GroupRepository) UpdateGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	start := time.Now()
+	g, err := r.next.UpdateGroup(ctx, g)
+	r.metrics.observeRepository("UpdateGroup", start, err)
+	return g, err
+}
+
+func (r *GroupRepository) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.DeleteGroup(ctx, id)
+	r.metrics.observeRepository("DeleteGroup", start, err)
+	return err
+}
+
+func (r *GroupRepository) AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole, joinedAt time.Time) (domain.Membership, error) {
+	start := time.Now()
+	membership, err := r.next.AddMember(ctx, groupID, personID, role, joinedAt)
+	r.metrics.observeRepository("AddMember", start, err)
+	return membership, err
+}
+
+func (r *GroupRepository) UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	start := time.Now()
+	membership, err := r.next.UpdateMember(ctx, groupID, personID, role)
+	r.metrics.observeRepository("UpdateMember", start, err)
+	return membership, err
+}
+
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error {
+	start := time.Now()
+	err := r.next.RemoveMember(ctx, groupID, personID)
+	r.metrics.observeRepository("RemoveMember", start, err)
+	return err
+}
+
+func (r *GroupRepository) GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	start := time.Now()
+	members, meta, err := r.next.GetMembers(ctx, groupID, page, size)
+	r.metrics.observeRepository("GetMembers", start, err)
+	return members, meta, err
+}
+
+func (r *GroupRepository) GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	start := time.Now()
+	groups, meta, err := r.next.GetPersonGroups(ctx, personID, page, size)
+	r.metrics.observeRepository("GetPersonGroups", start, err)
+	return groups, meta, err
+}
+
+// OutboxStore is the outbox as appended to by the service and read by the
+// relay.
+type OutboxStore interface {
+	person.Outbox
+	outbox.Store
+}
+
+// Outbox records the latency of every call to the wrapped outbox.
+type Outbox struct {
+	next    OutboxStore
+	metrics *Metrics
+}
+
+func NewOutbox(next OutboxStore, m *Metrics) *Outbox {
+	return &Outbox{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (r *Outbox) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	start := time.Now()
+	err := r.next.AppendEvents(ctx, events...)
+	r.metrics.observeRepository("AppendEvents", start, err)
+	return err
+}
+
+func (r *Outbox) OutboxAfter(ctx context.Context, after uint64, limit int) ([]outbox.Record, error) {
+	start := time.Now()
+	records, err := r.next.OutboxAfter(ctx, after, limit)
+	r.metrics.observeRepository("OutboxAfter", start, err)
+	return records, err
+}
+
+func (r *Outbox) PruneOutbox(ctx context.Context, seq uint64) error {
+	start := time.Now()
+	err := r.next.PruneOutbox(ctx, seq)
+	r.metrics.observeRepository("PruneOutbox", start, err)
+	return err
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+const (
+	SpecVersion = "1.0"
+	// ContentType is the media type of a CloudEvent in structured mode.
+	ContentType = "application/cloudevents+json"
+	// TypePrefix is prepended to domain event types, so person.created is
+	// sent as com.lafetz.persons.person.created.
+	TypePrefix = "com.lafetz.persons."
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope in its JSON format.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+type personData struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name,omitempty"`
+	Age        int32          `json:"age,omitempty"`
+	Hobbies    []string       `json:"hobbies,omitempty"`
+	Email      string         `json:"email,omitempty"`
+	Phone      string         `json:"phone,omitempty"`
+	BirthDate  string         `json:"birthDate,omitempty"`
+	Address    *addressData   `json:"address,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type addressData struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postalCode"`
+	Region     string `json:"region,omitempty"`
+	Country    string `json:"country"`
+}
+
+// NewCloudEvent wraps e in a CloudEvent from source. The event ID is kept
+// so consumers can drop the duplicates at-least-once delivery produces.
+func NewCloudEvent(source string, e domain.Event) (CloudEvent, error) {
+	p := e.Person
+	pd := personData{
+		ID:         p.ID,
+		Name:       p.Name,
+		Age:        p.Age,
+		Hobbies:    p.Hobbies,
+		Email:      p.Email,
+		Phone:      p.Phone,
+		Attributes: p.Attributes,
+	}
+	if !p.BirthDate.IsZero() {
+		pd.BirthDate = p.BirthDate.Format(domain.DateLayout)
+	}
+	if a := p.Address; a != nil {
+		pd.Address = &addressData{Street: a.Street, City: a.City, PostalCode: a.PostalCode, Region: a.Region, Country: a.Country}
+	}
+	data, err := json.Marshal(pd)
+	if err != nil {
+		return CloudEvent{}, err
+	}
+	return CloudEvent{
+		SpecVersion:     SpecVersion,
+		ID:              e.ID.String(),
+		Source:          source,
+		Type:            TypePrefix + string(e.Type),
+		Subject:         e.Person.ID.String(),
+		Time:            e.OccurredAt,
+		DataContentType: "application/json",
+		Data:            data,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSink publishes every event to the subject prefix.<event type>, e.g.
+// persons.person.created, and waits for the server to acknowledge it.
+type NATSSink struct {
+	conn   *nats.Conn
+	prefix string
+	// closed is closed once the connection is.
+	closed chan struct{}
+}
+
+func NewNATSSink(url, prefix string) (*NATSSink, error) {
+	closed := make(chan struct{})
+	conn, err := nats.Connect(url, nats.Name("persons-api outbox"), nats.MaxReconnects(-1),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSSink{conn: conn, prefix: prefix, closed: closed}, nil
+}
+
+func (s *NATSSink) Name() string { return "nats" }
+
+func (s *NATSSink) Send(ctx context.Context, e CloudEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(s.prefix + "." + e.Type[len(TypePrefix):])
+	msg.Header.Set("Content-Type", ContentType)
+	msg.Data = body
+	if err := s.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	timeout := 5 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	return s.conn.FlushTimeout(timeout)
+}
+
+// Close flushes pending messages and waits for the connection to close,
+// which nats bounds by its drain timeout.
+func (s *NATSSink) Close() error {
+	if err := s.conn.Drain(); err != nil {
+		return err
+	}
+	<-s.closed
+	return nil
+}
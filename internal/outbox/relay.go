@@ -0,0 +1,198 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// Record is an event stored in the outbox. Seq increases with every
+// appended event.
+type Record struct {
+	Seq   uint64
+	Event domain.Event
+}
+
+// Store reads and trims the outbox the service appends to.
+type Store interface {
+	OutboxAfter(ctx context.Context, after uint64, limit int) ([]Record, error)
+	PruneOutbox(ctx context.Context, seq uint64) error
+}
+
+type Config struct {
+	// Source is the CloudEvents source attribute of relayed events.
+	Source    string
+	BatchSize int
+	// PollInterval bounds how long a stored event waits when its wake-up
+	// was missed.
+	PollInterval time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	// Timeout bounds a single send to a sink.
+	Timeout time.Duration
+}
+
+var DefaultConfig = Config{
+	Source:       "/persons-api",
+	BatchSize:    100,
+	PollInterval: 5 * time.Second,
+	MinBackoff:   500 * time.Millisecond,
+	MaxBackoff:   time.Minute,
+	Timeout:      10 * time.Second,
+}
+
+// Relay delivers the events stored in the outbox to every sink, in order
+// and at least once. Each sink keeps its own position, so a failing sink
+// holds back only itself; records are pruned once every sink has them.
+//
+// Relay implements person.EventPublisher to be woken up after each write.
+type Relay struct {
+	store  Store
+	cfg    Config
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	workers []*sinkWorker
+}
+
+type sinkWorker struct {
+	sink   Sink
+	cursor uint64
+	wake   chan struct{}
+}
+
+func NewRelay(store Store, sinks []Sink, cfg Config, logger *slog.Logger) *Relay {
+	if cfg.Source == "" {
+		cfg.Source = DefaultConfig.Source
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultConfig.BatchSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultConfig.PollInterval
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultConfig.MinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(DefaultConfig.MaxBackoff, cfg.MinBackoff)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultConfig.Timeout
+	}
+	r := &Relay{store: store, cfg: cfg, logger: logger}
+	for _, s := range sinks {
+		r.workers = append(r.workers, &sinkWorker{sink: s, wake: make(chan struct{}, 1)})
+	}
+	return r
+}
+
+// Publish wakes the sink workers up. The event itself is read back from
+// the outbox, where the write stored it.
+func (r *Relay) Publish(ctx context.Context, e domain.Event) {
+	for _, w := range r.workers {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, w := range r.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.run(ctx, w)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *Relay) run(ctx context.Context, w *sinkWorker) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		var wait <-chan time.Time = ticker.C
+		if err := r.drain(ctx, w); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Warn("outbox relay failed", "sink", w.sink.Name(), "error", err, "failures", failures+1)
+			wait = time.After(r.backoff(failures))
+			failures++
+		} else {
+			failures = 0
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-wait:
+		}
+	}
+}
+
+// drain sends every record after the worker's cursor to its sink.
+func (r *Relay) drain(ctx context.Context, w *sinkWorker) error {
+	for {
+		records, err := r.store.OutboxAfter(ctx, r.cursor(w), r.cfg.BatchSize)
+		if err != nil || len(records) == 0 {
+			return err
+		}
+		for _, rec := range records {
+			ce, err := NewCloudEvent(r.cfg.Source, rec.Event)
+			if err != nil {
+				return err
+			}
+			sendCtx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+			err = w.sink.Send(sendCtx, ce)
+			cancel()
+			if err != nil {
+				return err
+			}
+			r.advance(w, rec.Seq)
+		}
+		if err := r.prune(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Relay) cursor(w *sinkWorker) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return w.cursor
+}
+
+func (r *Relay) advance(w *sinkWorker, seq uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w.cursor = seq
+}
+
+// prune drops the records every sink has received.
+func (r *Relay) prune(ctx context.Context) error {
+	r.mu.Lock()
+	low := r.workers[0].cursor
+	for _, w := range r.workers[1:] {
+		low = min(low, w.cursor)
+	}
+	r.mu.Unlock()
+	return r.store.PruneOutbox(ctx, low)
+}
+
+func (r *Relay) backoff(failures int) time.Duration {
+	d := r.cfg.MinBackoff << failures
+	if d <= 0 || d > r.cfg.MaxBackoff {
+		d = r.cfg.MaxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,142 @@
+package outbox_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/outbox"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySink fails as many sends as failures and records the rest.
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	received []outbox.CloudEvent
+}
+
+func (s *flakySink) Name() string { return "flaky" }
+
+func (s *flakySink) Send(ctx context.Context, e outbox.CloudEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.received = append(s.received, e)
+	return nil
+}
+
+func (s *flakySink) events() []outbox.CloudEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]outbox.CloudEvent(nil), s.received...)
+}
+
+var testConfig = outbox.Config{
+	Source:       "/test",
+	PollInterval: time.Hour,
+	MinBackoff:   time.Millisecond,
+	MaxBackoff:   5 * time.Millisecond,
+}
+
+func TestRelay_DeliversInOrderAfterFailures(t *testing.T) {
+	repo := repository.NewRepository()
+	sink := &flakySink{failures: 3}
+	relay := outbox.NewRelay(repo, []outbox.Sink{sink}, testConfig, slog.Default())
+	svc := person.NewPersonSvc(repo, person.WithOutbox(repo), person.WithPublisher(relay))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go relay.Run(ctx)
+
+	p, err := svc.AddPerson(ctx, domain.NewPerson("Ada", 36, []string{"Maths"}))
+	require.NoError(t, err)
+	p.Age = 37
+	_, err = svc.UpdatePerson(ctx, p)
+	require.NoError(t, err)
+	require.NoError(t, svc.DeletePerson(ctx, p.ID))
+
+	assert.Eventually(t, func() bool { return len(sink.events()) == 3 }, time.Second, 5*time.Millisecond)
+	events := sink.events()
+	assert.Equal(t, []string{
+		outbox.TypePrefix + "person.created",
+		outbox.TypePrefix + "person.updated",
+		outbox.TypePrefix + "person.deleted",
+	}, []string{events[0].Type, events[1].Type, events[2].Type})
+	for _, e := range events {
+		assert.Equal(t, outbox.SpecVersion, e.SpecVersion)
+		assert.Equal(t, "/test", e.Source)
+		assert.Equal(t, p.ID.String(), e.Subject)
+	}
+	assert.JSONEq(t, `{"id":"`+p.ID.String()+`","name":"Ada","age":37,"hobbies":["Maths"]}`, string(events[1].Data))
+	assert.Eventually(t, func() bool { return repo.OutboxLen() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestRelay_FailedWriteRaisesNoEvent(t *testing.T) {
+	repo := repository.NewRepository()
+	sink := &flakySink{}
+	relay := outbox.NewRelay(repo, []outbox.Sink{sink}, testConfig, slog.Default())
+	svc := person.NewPersonSvc(repo, person.WithOutbox(repo), person.WithPublisher(relay))
+
+	p := domain.NewPerson("Grace", 45, []string{"Compilers"})
+	_, err := svc.UpdatePerson(context.Background(), p)
+	assert.ErrorIs(t, err, person.ErrNotFound)
+	assert.Zero(t, repo.OutboxLen())
+}
+
+func TestRelay_PrunesOnlyWhatEverySinkReceived(t *testing.T) {
+	repo := repository.NewRepository()
+	healthy, failing := &flakySink{}, &flakySink{failures: 1 << 30}
+	relay := outbox.NewRelay(repo, []outbox.Sink{healthy, failing}, testConfig, slog.Default())
+	svc := person.NewPersonSvc(repo, person.WithOutbox(repo), person.WithPublisher(relay))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go relay.Run(ctx)
+
+	_, err := svc.AddPerson(ctx, domain.NewPerson("Linus", 54, []string{"Diving"}))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(healthy.events()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, repo.OutboxLen())
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := outbox.NewFileSink(path)
+	require.NoError(t, err)
+	p := domain.NewPerson("Barbara", 80, []string{"Lisp"})
+	for _, typ := range []domain.EventType{domain.EventPersonCreated, domain.EventPersonDeleted} {
+		ce, err := outbox.NewCloudEvent("/test", domain.NewEvent(typ, p))
+		require.NoError(t, err)
+		require.NoError(t, sink.Send(context.Background(), ce))
+	}
+	require.NoError(t, sink.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var types []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ce map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ce))
+		assert.Equal(t, "1.0", ce["specversion"])
+		types = append(types, ce["type"].(string))
+	}
+	assert.Equal(t, []string{outbox.TypePrefix + "person.created", outbox.TypePrefix + "person.deleted"}, types)
+}
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Sink receives the events relayed from the outbox. An error makes the
+// relay retry the same event later, so Send must tolerate duplicates.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, e CloudEvent) error
+}
+
+// LogSink writes every event to a logger.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+func NewLogSink(logger *slog.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string { return "log" }
+
+func (s *LogSink) Send(ctx context.Context, e CloudEvent) error {
+	s.logger.InfoContext(ctx, "outbox event",
+		"id", e.ID,
+		"type", e.Type,
+		"subject", e.Subject,
+		"data", string(e.Data),
+	)
+	return nil
+}
+
+// FileSink appends every event to a file as a line of JSON.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f}, nil
+}
+
+func (s *FileSink) Name() string { return "file" }
+
+func (s *FileSink) Send(ctx context.Context, e CloudEvent) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
@@ -0,0 +1,316 @@
+// Package photo stores profile photos of persons with thumbnails in a
+// blob Store.
+package photo
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+var (
+	ErrNotFound = errors.New("photo: not found")
+	ErrTooLarge = errors.New("photo: file too large")
+	// ErrUnsupportedType is returned for uploads that are not JPEG, PNG or
+	// WebP images, whatever their declared type.
+	ErrUnsupportedType = errors.New("photo: must be a JPEG, PNG or WebP image")
+	// ErrInvalidImage is returned for corrupt images and images outside
+	// the dimension limits.
+	ErrInvalidImage = errors.New("photo: invalid image")
+)
+
+// Size names a stored rendition of a photo.
+type Size string
+
+const (
+	// SizeOriginal is the upload at its own dimensions.
+	SizeOriginal Size = "original"
+	SizeSmall    Size = "small"
+	SizeMedium   Size = "medium"
+	SizeLarge    Size = "large"
+)
+
+type Config struct {
+	// MaxBytes bounds the size of an upload.
+	MaxBytes int64
+	// MinDimension and MaxDimension bound the width and height of an
+	// upload in pixels. MaxDimension also bounds the memory used to decode
+	// it.
+	MinDimension int
+	MaxDimension int
+	// Thumbnails maps the thumbnail sizes to the box, in pixels, they are
+	// scaled down to fit.
+	Thumbnails map[Size]int
+}
+
+var DefaultConfig = Config{
+	MaxBytes:     10 << 20,
+	MinDimension: 16,
+	MaxDimension: 4096,
+	Thumbnails: map[Size]int{
+		SizeSmall:  64,
+		SizeMedium: 256,
+		SizeLarge:  1024,
+	},
+}
+
+// Photo describes the stored renditions of a person's photo.
+type Photo struct {
+	PersonID uuid.UUID
+	// Width and Height are those of the original, upright.
+	Width       int
+	Height      int
+	ContentType string
+	UpdatedAt   time.Time
+}
+
+// Persons finds the persons photos belong to.
+type Persons interface {
+	GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error)
+}
+
+// Service stores photos of persons. It implements person.EventPublisher to
+// delete the photos of deleted persons.
+type Service struct {
+	cfg     Config
+	store   Store
+	persons Persons
+	logger  *slog.Logger
+	locks   personLocks
+}
+
+// New fills the zero fields of cfg from DefaultConfig and fails when the
+// result is inconsistent.
+func New(cfg Config, store Store, persons Persons, logger *slog.Logger) (*Service, error) {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultConfig.MaxBytes
+	}
+	if cfg.MinDimension <= 0 {
+		cfg.MinDimension = DefaultConfig.MinDimension
+	}
+	if cfg.MaxDimension <= 0 {
+		cfg.MaxDimension = max(cfg.MinDimension, DefaultConfig.MaxDimension)
+	}
+	if cfg.MaxDimension < cfg.MinDimension {
+		return nil, fmt.Errorf("photo: max dimension %d is less than the min dimension %d", cfg.MaxDimension, cfg.MinDimension)
+	}
+	if len(cfg.Thumbnails) == 0 {
+		cfg.Thumbnails = DefaultConfig.Thumbnails
+	}
+	for size, box := range cfg.Thumbnails {
+		if box < 1 {
+			return nil, fmt.Errorf("photo: thumbnail %s must be at least 1 pixel", size)
+		}
+	}
+	return &Service{cfg: cfg, store: store, persons: persons, logger: logger}, nil
+}
+
+// MaxBytes is the largest upload Upload accepts.
+func (s *Service) MaxBytes() int64 {
+	return s.cfg.MaxBytes
+}
+
+// Upload replaces the photo of a person with the image read from r. The
+// type is sniffed from the content, the image is turned upright according
+// to its EXIF orientation and every rendition is re-encoded, which drops
+// EXIF and other metadata.
+func (s *Service) Upload(ctx context.Context, personID uuid.UUID, r io.Reader) (Photo, error) {
+	if _, err := s.persons.GetPerson(ctx, personID); err != nil {
+		return Photo{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxBytes+1))
+	if err != nil {
+		return Photo{}, err
+	}
+	if int64(len(data)) > s.cfg.MaxBytes {
+		return Photo{}, ErrTooLarge
+	}
+	contentType, f, ok := sniff(data)
+	if !ok {
+		return Photo{}, ErrUnsupportedType
+	}
+	// Check the dimensions before decoding so that a small file can not
+	// claim a huge image.
+	cfg, err := f.decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Photo{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if err := s.checkDimensions(cfg.Width, cfg.Height); err != nil {
+		return Photo{}, err
+	}
+	img, err := f.decode(bytes.NewReader(data))
+	if err != nil {
+		return Photo{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	photo := Photo{PersonID: personID, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	blobs := make(map[Size]Blob, len(s.cfg.Thumbnails)+1)
+	for _, size := range s.Sizes() {
+		rendition, quality := img, jpegQuality
+		if size != SizeOriginal {
+			rendition, quality = fit(img, s.cfg.Thumbnails[size]), thumbnailQuality
+		}
+		encoded, contentType, err := encode(rendition, quality)
+		if err != nil {
+			return Photo{}, err
+		}
+		blobs[size] = Blob{Data: encoded, ContentType: contentType}
+	}
+	photo.ContentType = blobs[SizeOriginal].ContentType
+
+	unlock := s.locks.lock(personID)
+	defer unlock()
+	if err := s.replace(ctx, personID, blobs); err != nil {
+		return Photo{}, err
+	}
+	photo.UpdatedAt = time.Now().UTC()
+	return photo, nil
+}
+
+// replace stores blobs as the renditions of the photo of a person. When a
+// Put fails the renditions already written are put back as they were, so
+// that the sizes of a photo never come from different uploads.
+func (s *Service) replace(ctx context.Context, personID uuid.UUID, blobs map[Size]Blob) error {
+	previous := make(map[Size]*Blob, len(blobs))
+	for size := range blobs {
+		blob, err := s.store.Get(ctx, key(personID, size))
+		switch {
+		case errors.Is(err, ErrNotFound):
+			previous[size] = nil
+		case err != nil:
+			return err
+		default:
+			previous[size] = &blob
+		}
+	}
+	var written []Size
+	for _, size := range s.Sizes() {
+		if err := s.store.Put(ctx, key(personID, size), blobs[size].Data, blobs[size].ContentType); err != nil {
+			if rerr := s.restore(context.WithoutCancel(ctx), personID, previous, written); rerr != nil {
+				s.logger.ErrorContext(ctx, "failed to restore photo", "person_id", personID, "error", rerr)
+			}
+			return err
+		}
+		written = append(written, size)
+	}
+	return nil
+}
+
+func (s *Service) restore(ctx context.Context, personID uuid.UUID, previous map[Size]*Blob, sizes []Size) error {
+	var errs []error
+	for _, size := range sizes {
+		if blob := previous[size]; blob != nil {
+			errs = append(errs, s.store.Put(ctx, key(personID, size), blob.Data, blob.ContentType))
+		} else {
+			errs = append(errs, s.store.Delete(ctx, key(personID, size)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) checkDimensions(w, h int) error {
+	if w < s.cfg.MinDimension || h < s.cfg.MinDimension {
+		return fmt.Errorf("%w: %dx%d is smaller than %d pixels", ErrInvalidImage, w, h, s.cfg.MinDimension)
+	}
+	if w > s.cfg.MaxDimension || h > s.cfg.MaxDimension {
+		return fmt.Errorf("%w: %dx%d is larger than %d pixels", ErrInvalidImage, w, h, s.cfg.MaxDimension)
+	}
+	return nil
+}
+
+// Get returns a rendition of the photo of a person. It fails with
+// ErrNotFound when the person has no photo or size is not configured.
+func (s *Service) Get(ctx context.Context, personID uuid.UUID, size Size) (Blob, error) {
+	if _, ok := s.cfg.Thumbnails[size]; !ok && size != SizeOriginal {
+		return Blob{}, ErrNotFound
+	}
+	return s.store.Get(ctx, key(personID, size))
+}
+
+// Delete removes every rendition of the photo of a person.
+func (s *Service) Delete(ctx context.Context, personID uuid.UUID) error {
+	unlock := s.locks.lock(personID)
+	defer unlock()
+	var errs []error
+	for _, size := range s.Sizes() {
+		errs = append(errs, s.store.Delete(ctx, key(personID, size)))
+	}
+	return errors.Join(errs...)
+}
+
+// Sizes returns the configured renditions, smallest first.
+func (s *Service) Sizes() []Size {
+	sizes := make([]Size, 0, len(s.cfg.Thumbnails)+1)
+	for size := range s.cfg.Thumbnails {
+		sizes = append(sizes, size)
+	}
+	slices.SortFunc(sizes, func(a, b Size) int { return cmp.Compare(s.cfg.Thumbnails[a], s.cfg.Thumbnails[b]) })
+	return append(sizes, SizeOriginal)
+}
+
+// Publish deletes the photo of deleted persons in the background, since
+// the store may be remote.
+func (s *Service) Publish(ctx context.Context, event domain.Event) {
+	if event.Type != domain.EventPersonDeleted {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := s.Delete(ctx, event.Person.ID); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete photo", "person_id", event.Person.ID, "error", err)
+		}
+	}()
+}
+
+func key(personID uuid.UUID, size Size) string {
+	return "persons/" + personID.String() + "/" + string(size)
+}
+
+// personLocks serializes the writes to the photo of each person. Entries
+// are dropped once no one holds or waits for them.
+type personLocks struct {
+	mu    sync.Mutex
+	locks map[uuid.UUID]*personLock
+}
+
+type personLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (l *personLocks) lock(personID uuid.UUID) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[uuid.UUID]*personLock)
+	}
+	pl, ok := l.locks[personID]
+	if !ok {
+		pl = &personLock{}
+		l.locks[personID] = pl
+	}
+	pl.refs++
+	l.mu.Unlock()
+
+	pl.mu.Lock()
+	return func() {
+		pl.mu.Unlock()
+		l.mu.Lock()
+		if pl.refs--; pl.refs == 0 {
+			delete(l.locks, personID)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,272 @@
+package photo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type persons map[uuid.UUID]bool
+
+func (p persons) GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error) {
+	if !p[id] {
+		return domain.Person{}, person.ErrNotFound
+	}
+	return domain.Person{ID: id}, nil
+}
+
+func newService(t *testing.T, cfg Config, ids ...uuid.UUID) *Service {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	known := persons{}
+	for _, id := range ids {
+		known[id] = true
+	}
+	s, err := New(cfg, store, known, slog.Default())
+	require.NoError(t, err)
+	return s
+}
+
+// testImage is w by h pixels, red in the top left corner and white
+// elsewhere.
+func testImage(w, h int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{255, 255, 255, alpha})
+		}
+	}
+	for y := 0; y < h/10; y++ {
+		for x := 0; x < w/10; x++ {
+			img.Set(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment with the given orientation after
+// the start of a JPEG.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(segment)+2))
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestUpload(t *testing.T) {
+	id := uuid.New()
+	s := newService(t, DefaultConfig, id)
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, id, SizeOriginal)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Rotated 90 degrees clockwise for display.
+	data := withOrientation(encodeJPEG(t, testImage(400, 300, 255)), 6)
+	require.Equal(t, 6, jpegOrientation(data))
+	p, err := s.Upload(ctx, id, bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, Photo{PersonID: id, Width: 300, Height: 400, ContentType: "image/jpeg", UpdatedAt: p.UpdatedAt}, p)
+
+	blob, err := s.Get(ctx, id, SizeOriginal)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", blob.ContentType)
+	assert.NotContains(t, string(blob.Data), "Exif")
+	assert.WithinDuration(t, time.Now(), blob.ModTime, time.Minute)
+	img, err := jpeg.Decode(bytes.NewReader(blob.Data))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 300, 400), img.Bounds())
+	// The red corner moved to the top right.
+	r, g, _, _ := img.At(299, 0).RGBA()
+	assert.Greater(t, r, 3*g)
+
+	for size, want := range map[Size]image.Rectangle{
+		SizeSmall:  image.Rect(0, 0, 48, 64),
+		SizeMedium: image.Rect(0, 0, 192, 256),
+		SizeLarge:  image.Rect(0, 0, 300, 400),
+	} {
+		blob, err := s.Get(ctx, id, size)
+		require.NoError(t, err, size)
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(blob.Data))
+		require.NoError(t, err, size)
+		assert.Equal(t, want, image.Rect(0, 0, cfg.Width, cfg.Height), size)
+	}
+	_, err = s.Get(ctx, id, "huge")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Transparency is kept by storing PNG.
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(100, 100, 0)))
+	p, err = s.Upload(ctx, id, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", p.ContentType)
+	blob, err = s.Get(ctx, id, SizeSmall)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", blob.ContentType)
+
+	s.Publish(ctx, domain.NewEvent(domain.EventPersonDeleted, domain.Person{ID: id}))
+	assert.Eventually(t, func() bool {
+		_, err := s.Get(ctx, id, SizeOriginal)
+		return err == ErrNotFound
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestUploadRejects(t *testing.T) {
+	id := uuid.New()
+	s := newService(t, Config{MaxBytes: 4 << 10, MaxDimension: 200}, id)
+	ctx := context.Background()
+
+	_, err := s.Upload(ctx, uuid.New(), bytes.NewReader(encodeJPEG(t, testImage(50, 50, 255))))
+	assert.ErrorIs(t, err, person.ErrNotFound)
+	_, err = s.Upload(ctx, id, bytes.NewReader(make([]byte, 5<<10)))
+	assert.ErrorIs(t, err, ErrTooLarge)
+	_, err = s.Upload(ctx, id, bytes.NewBufferString("GIF89a not really"))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	_, err = s.Upload(ctx, id, bytes.NewReader(encodeJPEG(t, testImage(50, 50, 255))[:200]))
+	assert.ErrorIs(t, err, ErrInvalidImage)
+	_, err = s.Upload(ctx, id, bytes.NewReader(encodeJPEG(t, testImage(10, 50, 255))))
+	assert.ErrorIs(t, err, ErrInvalidImage)
+
+	// A PNG header claiming a huge image is rejected before decoding.
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(20, 20, 255)))
+	huge := buf.Bytes()
+	binary.BigEndian.PutUint32(huge[16:], 100000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+	_, err = s.Upload(ctx, id, bytes.NewReader(huge))
+	assert.ErrorIs(t, err, ErrInvalidImage)
+	assert.Contains(t, err.Error(), "larger than 200 pixels")
+}
+
+func TestFileStoreKeys(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	assert.Error(t, s.Put(ctx, "../escape", []byte("x"), "text/plain"))
+	assert.Error(t, s.Put(ctx, "/abs", []byte("x"), "text/plain"))
+	require.NoError(t, s.Put(ctx, "a/b", []byte("x"), "text/plain"))
+	require.NoError(t, s.Delete(ctx, "a/b"))
+	require.NoError(t, s.Delete(ctx, "a/b"))
+	_, err = s.Get(ctx, "a/b")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNew_RejectsInconsistentConfig(t *testing.T) {
+	_, err := New(Config{MinDimension: 100, MaxDimension: 50}, nil, persons{}, slog.Default())
+	assert.Error(t, err)
+	_, err = New(Config{Thumbnails: map[Size]int{SizeSmall: 0}}, nil, persons{}, slog.Default())
+	assert.Error(t, err)
+}
+
+// failingStore fails the Put of one key while fail is set.
+type failingStore struct {
+	Store
+	key  string
+	fail bool
+}
+
+func (s *failingStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if s.fail && key == s.key {
+		return errors.New("store unavailable")
+	}
+	return s.Store.Put(ctx, key, data, contentType)
+}
+
+func TestUpload_FailedPutRestoresPreviousPhoto(t *testing.T) {
+	id := uuid.New()
+	files, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	store := &failingStore{Store: files, key: key(id, SizeOriginal)}
+	s, err := New(Config{}, store, persons{id: true}, slog.Default())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = s.Upload(ctx, id, bytes.NewReader(encodeJPEG(t, testImage(100, 100, 255))))
+	require.NoError(t, err)
+	before := map[Size][]byte{}
+	for _, size := range s.Sizes() {
+		blob, err := s.Get(ctx, id, size)
+		require.NoError(t, err)
+		before[size] = blob.Data
+	}
+
+	store.fail = true
+	_, err = s.Upload(ctx, id, bytes.NewReader(encodeJPEG(t, testImage(300, 200, 255))))
+	require.Error(t, err)
+	for _, size := range s.Sizes() {
+		blob, err := s.Get(ctx, id, size)
+		require.NoError(t, err)
+		assert.Equal(t, before[size], blob.Data, size)
+	}
+
+	// Without a previous photo nothing is left behind.
+	other := uuid.New()
+	s.persons = persons{id: true, other: true}
+	store.key = key(other, SizeOriginal)
+	_, err = s.Upload(ctx, other, bytes.NewReader(encodeJPEG(t, testImage(100, 100, 255))))
+	require.Error(t, err)
+	for _, size := range s.Sizes() {
+		_, err := s.Get(ctx, other, size)
+		assert.ErrorIs(t, err, ErrNotFound, size)
+	}
+}
+
+func TestUpload_ConcurrentUploadsDoNotMix(t *testing.T) {
+	id := uuid.New()
+	s := newService(t, Config{}, id)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			_, err := s.Upload(ctx, id, bytes.NewReader(encodeJPEG(t, testImage(w, 100, 255))))
+			assert.NoError(t, err)
+		}(100 + 20*i)
+	}
+	wg.Wait()
+
+	original, err := s.Get(ctx, id, SizeOriginal)
+	require.NoError(t, err)
+	img, _, err := image.Decode(bytes.NewReader(original.Data))
+	require.NoError(t, err)
+	for size, box := range s.cfg.Thumbnails {
+		blob, err := s.Get(ctx, id, size)
+		require.NoError(t, err)
+		thumb, _, err := image.Decode(bytes.NewReader(blob.Data))
+		require.NoError(t, err)
+		assert.Equal(t, fit(img, box).Bounds(), thumb.Bounds(), size)
+	}
+	assert.Empty(t, s.locks.locks)
+}
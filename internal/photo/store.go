@@ -0,0 +1,106 @@
+package photo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store keeps blobs by slash-separated key. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Put creates or replaces the blob at key.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get fails with ErrNotFound for missing keys.
+	Get(ctx context.Context, key string) (Blob, error)
+	// Delete removes the blob at key; missing keys are not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+type Blob struct {
+	Data        []byte
+	ContentType string
+	ModTime     time.Time
+}
+
+// FileStore keeps blobs as files under a directory. Content types are
+// sniffed on read rather than stored.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates dir if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put writes to a temporary file first so that readers never see a
+// partial blob.
+func (s *FileStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *FileStore) Get(ctx context.Context, key string) (Blob, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return Blob{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Blob{}, ErrNotFound
+	}
+	if err != nil {
+		return Blob{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Blob{}, err
+	}
+	return Blob{Data: data, ContentType: http.DetectContentType(data), ModTime: info.ModTime()}, nil
+}
+
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) path(key string) (string, error) {
+	if !fs.ValidPath(key) || strings.HasPrefix(key, ".") {
+		return "", fmt.Errorf("photo: invalid key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
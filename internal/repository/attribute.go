@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+)
+
+func (r *Repository) GetAttributeSchema(ctx context.Context, tenant string) (domain.AttributeSchema, error) {
+	defer r.rlock(ctx)()
+
+	s, ok := r.schemas[tenant]
+	if !ok {
+		return domain.AttributeSchema{}, person.ErrNotFound
+	}
+	return s, nil
+}
+
+// PutAttributeSchema replaces the attribute schema of a tenant.
+func (r *Repository) PutAttributeSchema(ctx context.Context, tenant string, s domain.AttributeSchema) error {
+	defer r.lock(ctx)()
+
+	old, existed := r.schemas[tenant]
+	r.undo(ctx, func() {
+		if existed {
+			r.schemas[tenant] = old
+		} else {
+			delete(r.schemas, tenant)
+		}
+	})
+	r.schemas[tenant] = s
+	return nil
+}
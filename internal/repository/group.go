@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+)
+
+// member is a stored membership; groups and persons are loaded on read.
+type member struct {
+	role     domain.GroupRole
+	joinedAt time.Time
+}
+
+func (r *Repository) AddGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	defer r.lock(ctx)()
+
+	if _, exists := r.groups[g.ID]; exists {
+		return domain.Group{}, ErrDuplicatePk
+	}
+	r.undo(ctx, func() { delete(r.groups, g.ID) })
+	r.groups[g.ID] = g
+	return g, nil
+}
+
+func (r *Repository) GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	defer r.rlock(ctx)()
+
+	g, exists := r.groups[id]
+	if !exists {
+		return domain.Group{}, person.ErrNotFound
+	}
+	return g, nil
+}
+
+func (r *Repository) GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error) {
+	defer r.rlock(ctx)()
+
+	groups := make([]domain.Group, 0, len(r.groups))
+	for _, g := range r.groups {
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, compareGroups)
+	groups, meta := paginate(groups, page, size)
+	return groups, meta, nil
+}
+
+// UpdateGroup replaces the name and description of a group.
+func (r *Repository) UpdateGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	defer r.lock(ctx)()
+
+	old, exists := r.groups[g.ID]
+	if !exists {
+		return domain.Group{}, person.ErrNotFound
+	}
+	g.CreatedAt = old.CreatedAt
+	r.undo(ctx, func() { r.groups[g.ID] = old })
+	r.groups[g.ID] = g
+	return g, nil
+}
+
+// DeleteGroup deletes a group and its memberships.
+func (r *Repository) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	defer r.lock(ctx)()
+
+	old, exists := r.groups[id]
+	if !exists {
+		return person.ErrNotFound
+	}
+	for personID := range r.members[id] {
+		r.removeMember(ctx, id, personID)
+	}
+	r.undo(ctx, func() { r.groups[id] = old })
+	delete(r.groups, id)
+	return nil
+}
+
+func (r *Repository) AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole, joinedAt time.Time) (domain.Membership, error) {
+	defer r.lock(ctx)()
+
+	if _, ok := r.groups[groupID]; !ok {
+		return domain.Membership{}, person.ErrNotFound
+	}
+	if _, ok := r.storage[personID]; !ok {
+		return domain.Membership{}, r.notFound(personID)
+	}
+	if _, ok := r.members[groupID][personID]; ok {
+		return domain.Membership{}, person.ErrMemberExists
+	}
+	r.setMember(ctx, groupID, personID, member{role: role, joinedAt: joinedAt})
+	return r.membership(groupID, personID), nil
+}
+
+// UpdateMember changes the role of a member.
+func (r *Repository) UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	defer r.lock(ctx)()
+
+	m, ok := r.members[groupID][personID]
+	if !ok {
+		return domain.Membership{}, person.ErrNotFound
+	}
+	m.role = role
+	r.setMember(ctx, groupID, personID, m)
+	return r.membership(groupID, personID), nil
+}
+
+func (r *Repository) RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error {
+	defer r.lock(ctx)()
+
+	if _, ok := r.members[groupID][personID]; !ok {
+		return person.ErrNotFound
+	}
+	r.removeMember(ctx, groupID, personID)
+	return nil
+}
+
+// GetMembers returns a page of the members of a group ordered by name.
+func (r *Repository) GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	defer r.rlock(ctx)()
+
+	if _, ok := r.groups[groupID]; !ok {
+		return nil, domain.Metadata{}, person.ErrNotFound
+	}
+	members := make([]domain.Membership, 0, len(r.members[groupID]))
+	for personID := range r.members[groupID] {
+		members = append(members, r.membership(groupID, personID))
+	}
+	slices.SortFunc(members, func(a, b domain.Membership) int { return comparePersons(a.Person, b.Person) })
+	members, meta := paginate(members, page, size)
+	return members, meta, nil
+}
+
+// GetPersonGroups returns a page of the groups of a person ordered by name.
+func (r *Repository) GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	defer r.rlock(ctx)()
+
+	if _, ok := r.storage[personID]; !ok {
+		return nil, domain.Metadata{}, r.notFound(personID)
+	}
+	groups := make([]domain.Membership, 0, len(r.memberOf[personID]))
+	for groupID := range r.memberOf[personID] {
+		groups = append(groups, r.membership(groupID, personID))
+	}
+	slices.SortFunc(groups, func(a, b domain.Membership) int { return compareGroups(a.Group, b.Group) })
+	groups, meta := paginate(groups, page, size)
+	return groups, meta, nil
+}
+
+// MoveMemberships moves the memberships of the person from to the person
+// to. Groups to already belongs to keep its role.
+func (r *Repository) MoveMemberships(ctx context.Context, from, to uuid.UUID) error {
+	defer r.lock(ctx)()
+
+	if _, ok := r.storage[to]; !ok {
+		return person.ErrNotFound
+	}
+	for groupID, m := range r.memberOf[from] {
+		if _, ok := r.members[groupID][to]; !ok {
+			r.setMember(ctx, groupID, to, m)
+		}
+		r.removeMember(ctx, groupID, from)
+	}
+	return nil
+}
+
+// dropMemberships removes a deleted person from its groups.
+func (r *Repository) dropMemberships(ctx context.Context, personID uuid.UUID) {
+	for groupID := range r.memberOf[personID] {
+		r.removeMember(ctx, groupID, personID)
+	}
+}
+
+func (r *Repository) membership(groupID, personID uuid.UUID) domain.Membership {
+	m := r.members[groupID][personID]
+	return domain.Membership{
+		Group:    r.groups[groupID],
+		Person:   load(r.storage[personID]),
+		Role:     m.role,
+		JoinedAt: m.joinedAt,
+	}
+}
+
+// setMember stores m in both membership indexes.
+func (r *Repository) setMember(ctx context.Context, groupID, personID uuid.UUID, m member) {
+	old, existed := r.members[groupID][personID]
+	r.undo(ctx, func() {
+		if existed {
+			r.setMember(context.Background(), groupID, personID, old)
+		} else {
+			r.removeMember(context.Background(), groupID, personID)
+		}
+	})
+	if r.members[groupID] == nil {
+		r.members[groupID] = make(map[uuid.UUID]member)
+	}
+	if r.memberOf[personID] == nil {
+		r.memberOf[personID] = make(map[uuid.UUID]member)
+	}
+	r.members[groupID][personID] = m
+	r.memberOf[personID][groupID] = m
+}
+
+func (r *Repository) removeMember(ctx context.Context, groupID, personID uuid.UUID) {
+	if old, ok := r.members[groupID][personID]; ok {
+		r.undo(ctx, func() { r.setMember(context.Background(), groupID, personID, old) })
+	}
+	delete(r.members[groupID], personID)
+	if len(r.members[groupID]) == 0 {
+		delete(r.members, groupID)
+	}
+	delete(r.memberOf[personID], groupID)
+	if len(r.memberOf[personID]) == 0 {
+		delete(r.memberOf, personID)
+	}
+}
+
+func compareGroups(a, b domain.Group) int {
+	if c := cmp.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID.String(), b.ID.String())
+}
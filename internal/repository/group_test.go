@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupMembers(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ids := addPersons(t, repo, "Cid", "Ann", "Bob")
+	team, err := repo.AddGroup(ctx, domain.NewGroup("Platform", ""))
+	require.NoError(t, err)
+	_, err = repo.AddGroup(ctx, domain.NewGroup("Design", ""))
+	require.NoError(t, err)
+
+	for _, id := range ids {
+		_, err := repo.AddMember(ctx, team.ID, id, domain.RoleMember, time.Now())
+		require.NoError(t, err)
+	}
+	_, err = repo.AddMember(ctx, team.ID, ids[0], domain.RoleLead, time.Now())
+	assert.ErrorIs(t, err, person.ErrMemberExists)
+
+	m, err := repo.UpdateMember(ctx, team.ID, ids[0], domain.RoleLead)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleLead, m.Role)
+	assert.Equal(t, "Cid", m.Person.Name)
+
+	members, meta, err := repo.GetMembers(ctx, team.ID, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "Ann", members[0].Person.Name)
+	assert.Equal(t, int32(3), meta.TotalRecords)
+	assert.Equal(t, int32(2), meta.LastPage)
+
+	groups, _, err := repo.GetGroups(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Design", groups[0].Name)
+
+	require.NoError(t, repo.RemoveMember(ctx, team.ID, ids[1]))
+	assert.ErrorIs(t, repo.RemoveMember(ctx, team.ID, ids[1]), person.ErrNotFound)
+	mine, _, err := repo.GetPersonGroups(ctx, ids[1], 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, mine)
+}
+
+func TestDeleteGroupAndPerson_DropMemberships(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ids := addPersons(t, repo, "Ann", "Bob")
+	team, _ := repo.AddGroup(ctx, domain.NewGroup("Platform", ""))
+	for _, id := range ids {
+		_, err := repo.AddMember(ctx, team.ID, id, domain.RoleMember, time.Now())
+		require.NoError(t, err)
+	}
+
+	errFail := errors.New("fail")
+	err := repo.InTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, repo.DeletePerson(ctx, ids[0]))
+		require.NoError(t, repo.DeleteGroup(ctx, team.ID))
+		return errFail
+	})
+	assert.ErrorIs(t, err, errFail)
+	members, _, err := repo.GetMembers(ctx, team.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, members, 2, "rollback restores the memberships")
+
+	require.NoError(t, repo.DeletePerson(ctx, ids[0]))
+	members, _, _ = repo.GetMembers(ctx, team.ID, 0, 10)
+	require.Len(t, members, 1)
+	assert.Equal(t, ids[1], members[0].Person.ID)
+
+	require.NoError(t, repo.DeleteGroup(ctx, team.ID))
+	_, err = repo.GetGroup(ctx, team.ID)
+	assert.ErrorIs(t, err, person.ErrNotFound)
+	groups, meta, err := repo.GetPersonGroups(ctx, ids[1], 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+	assert.Zero(t, meta.TotalRecords)
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+)
+
+// RecordMerge adds m to the history of its target and redirects reads of
+// the source to the target.
+func (r *Repository) RecordMerge(ctx context.Context, m domain.Merge) error {
+	defer r.lock(ctx)()
+
+	if _, ok := r.storage[m.TargetID]; !ok {
+		return person.ErrNotFound
+	}
+	history, sourceHistory := r.merges[m.TargetID], r.merges[m.Source.ID]
+	r.undo(ctx, func() {
+		delete(r.tombstones, m.Source.ID)
+		r.merges[m.TargetID] = history
+		if sourceHistory != nil {
+			r.merges[m.Source.ID] = sourceHistory
+		}
+	})
+	r.tombstones[m.Source.ID] = m.TargetID
+	// Earlier merges into the source move along with it.
+	merged := append(slices.Clip(history), sourceHistory...)
+	r.merges[m.TargetID] = append(merged, m)
+	delete(r.merges, m.Source.ID)
+	return nil
+}
+
+// GetMerges returns the merges into targetID, oldest first.
+func (r *Repository) GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error) {
+	defer r.rlock(ctx)()
+
+	return slices.Clone(r.merges[targetID]), nil
+}
+
+// notFound returns the error for reading a missing id: a MergedError
+// naming the live person it ended up in, or ErrNotFound.
+func (r *Repository) notFound(id uuid.UUID) error {
+	into, merged := r.tombstones[id]
+	for seen := 0; merged && seen < len(r.tombstones); seen++ {
+		if _, ok := r.storage[into]; ok {
+			return &person.MergedError{Into: into}
+		}
+		into, merged = r.tombstones[into]
+	}
+	return person.ErrNotFound
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/lafetz/assessment/internal/outbox"
+)
+
+// AppendEvents stores events in the outbox. Inside InTx they are kept only
+// if the transaction commits.
+func (r *Repository) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	defer r.lock(ctx)()
+
+	n, seq := len(r.outbox), r.lastSeq
+	r.undo(ctx, func() { r.outbox, r.lastSeq = r.outbox[:n], seq })
+	for _, e := range events {
+		r.lastSeq++
+		r.outbox = append(r.outbox, outbox.Record{Seq: r.lastSeq, Event: e})
+	}
+	return nil
+}
+
+// OutboxAfter returns up to limit outbox records with a sequence number
+// greater than after, oldest first.
+func (r *Repository) OutboxAfter(ctx context.Context, after uint64, limit int) ([]outbox.Record, error) {
+	defer r.rlock(ctx)()
+
+	records := make([]outbox.Record, 0, min(limit, len(r.outbox)))
+	for _, rec := range r.outbox {
+		if len(records) == limit {
+			break
+		}
+		if rec.Seq > after {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// PruneOutbox drops outbox records up to and including seq.
+func (r *Repository) PruneOutbox(ctx context.Context, seq uint64) error {
+	defer r.lock(ctx)()
+
+	i := 0
+	for i < len(r.outbox) && r.outbox[i].Seq <= seq {
+		i++
+	}
+	r.outbox = append(r.outbox[:0:0], r.outbox[i:]...)
+	return nil
+}
+
+// OutboxLen returns the number of records waiting in the outbox.
+func (r *Repository) OutboxLen() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.outbox)
+}
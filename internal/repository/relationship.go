@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+)
+
+// The adjacency index keeps every relationship twice: under its source in
+// out and under its target in in. Slices are replaced, never modified in
+// place, so undo entries can keep the previous ones.
+
+func (r *Repository) AddRelationship(ctx context.Context, rel domain.Relationship) error {
+	defer r.lock(ctx)()
+
+	if rel.FromID == rel.ToID {
+		return person.ErrSelfRelationship
+	}
+	if _, ok := r.storage[rel.FromID]; !ok {
+		return r.notFound(rel.FromID)
+	}
+	if _, ok := r.storage[rel.ToID]; !ok {
+		return r.notFound(rel.ToID)
+	}
+	if slices.ContainsFunc(r.out[rel.FromID], func(o domain.Relationship) bool { return o.ToID == rel.ToID && o.Type == rel.Type }) {
+		return person.ErrRelationshipExists
+	}
+	r.saveEdges(ctx, rel.FromID, rel.ToID)
+	r.out[rel.FromID] = append(slices.Clip(r.out[rel.FromID]), rel)
+	r.in[rel.ToID] = append(slices.Clip(r.in[rel.ToID]), rel)
+	return nil
+}
+
+func (r *Repository) DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error {
+	defer r.lock(ctx)()
+
+	match := func(o domain.Relationship) bool { return o.FromID == from && o.ToID == to && o.Type == t }
+	if !slices.ContainsFunc(r.out[from], match) {
+		return person.ErrNotFound
+	}
+	r.saveEdges(ctx, from, to)
+	r.out[from] = slices.DeleteFunc(slices.Clone(r.out[from]), match)
+	r.in[to] = slices.DeleteFunc(slices.Clone(r.in[to]), match)
+	return nil
+}
+
+// GetRelationships returns the relationships from and to id, oldest first.
+func (r *Repository) GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error) {
+	defer r.rlock(ctx)()
+
+	if _, ok := r.storage[id]; !ok {
+		return nil, r.notFound(id)
+	}
+	rels := make([]domain.Relationship, 0, len(r.out[id])+len(r.in[id]))
+	rels = append(rels, r.out[id]...)
+	rels = append(rels, r.in[id]...)
+	slices.SortStableFunc(rels, func(a, b domain.Relationship) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return rels, nil
+}
+
+// ShortestPath finds a path with the fewest relationships from one person
+// to another by breadth-first search.
+func (r *Repository) ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error) {
+	defer r.rlock(ctx)()
+
+	for _, id := range []uuid.UUID{from, to} {
+		if _, ok := r.storage[id]; !ok {
+			return domain.Path{}, r.notFound(id)
+		}
+	}
+	// via maps each reached person to the relationship it was reached by.
+	via := map[uuid.UUID]domain.Relationship{from: {}}
+	frontier := []uuid.UUID{from}
+	for depth := 0; depth < q.MaxDepth && len(frontier) > 0; depth++ {
+		var next []uuid.UUID
+		for _, id := range frontier {
+			for _, rel := range r.edges(id, q.Directed) {
+				other := rel.Other(id)
+				if _, seen := via[other]; seen || !q.Allows(rel.Type) {
+					continue
+				}
+				via[other] = rel
+				if other == to {
+					return r.path(from, to, via), nil
+				}
+				next = append(next, other)
+			}
+		}
+		frontier = next
+	}
+	if from == to {
+		return domain.Path{Persons: []domain.Person{load(r.storage[from])}, Relationships: []domain.Relationship{}}, nil
+	}
+	return domain.Path{}, person.ErrNoPath
+}
+
+// path walks via back from to.
+func (r *Repository) path(from, to uuid.UUID, via map[uuid.UUID]domain.Relationship) domain.Path {
+	p := domain.Path{Persons: []domain.Person{load(r.storage[to])}}
+	for id := to; id != from; {
+		rel := via[id]
+		id = rel.Other(id)
+		p.Relationships = append(p.Relationships, rel)
+		p.Persons = append(p.Persons, load(r.storage[id]))
+	}
+	slices.Reverse(p.Persons)
+	slices.Reverse(p.Relationships)
+	return p
+}
+
+// FriendsOfFriends returns the friends of id's friends who are not its
+// friends yet, most mutual friends first. Friendships count in either
+// direction.
+func (r *Repository) FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error) {
+	defer r.rlock(ctx)()
+
+	if _, ok := r.storage[id]; !ok {
+		return nil, r.notFound(id)
+	}
+	friends := r.friends(id)
+	mutual := make(map[uuid.UUID]int)
+	for friend := range friends {
+		for candidate := range r.friends(friend) {
+			if _, isFriend := friends[candidate]; !isFriend && candidate != id {
+				mutual[candidate]++
+			}
+		}
+	}
+	suggestions := make([]domain.Suggestion, 0, len(mutual))
+	for candidate, n := range mutual {
+		suggestions = append(suggestions, domain.Suggestion{Person: load(r.storage[candidate]), Mutual: n})
+	}
+	slices.SortFunc(suggestions, func(a, b domain.Suggestion) int {
+		if c := cmp.Compare(b.Mutual, a.Mutual); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Person.Name, b.Person.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Person.ID.String(), b.Person.ID.String())
+	})
+	return suggestions, nil
+}
+
+func (r *Repository) friends(id uuid.UUID) map[uuid.UUID]struct{} {
+	friends := make(map[uuid.UUID]struct{})
+	for _, rel := range r.edges(id, false) {
+		if rel.Type == domain.RelationFriend {
+			friends[rel.Other(id)] = struct{}{}
+		}
+	}
+	return friends
+}
+
+// edges returns the relationships leaving id and, unless directed, those
+// arriving at it.
+func (r *Repository) edges(id uuid.UUID, directed bool) []domain.Relationship {
+	if directed {
+		return r.out[id]
+	}
+	return append(slices.Clip(r.out[id]), r.in[id]...)
+}
+
+// dropRelationships removes every relationship of id, so that deleted
+// persons leave no dangling edges.
+func (r *Repository) dropRelationships(ctx context.Context, id uuid.UUID) {
+	touches := func(o domain.Relationship) bool { return o.FromID == id || o.ToID == id }
+	for _, rel := range r.edges(id, false) {
+		other := rel.Other(id)
+		r.saveEdges(ctx, id, other)
+		r.out[other] = slices.DeleteFunc(slices.Clone(r.out[other]), touches)
+		r.in[other] = slices.DeleteFunc(slices.Clone(r.in[other]), touches)
+	}
+	delete(r.out, id)
+	delete(r.in, id)
+}
+
+// saveEdges records how to restore the adjacency lists of a and b.
+func (r *Repository) saveEdges(ctx context.Context, a, b uuid.UUID) {
+	outA, inA, outB, inB := r.out[a], r.in[a], r.out[b], r.in[b]
+	r.undo(ctx, func() {
+		r.out[a], r.in[a], r.out[b], r.in[b] = outA, inA, outB, inB
+	})
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addPersons stores one person per name and returns their IDs.
+func addPersons(t *testing.T, repo *Repository, names ...string) []uuid.UUID {
+	t.Helper()
+	ids := make([]uuid.UUID, len(names))
+	for i, name := range names {
+		p, err := repo.AddPerson(context.Background(), domain.NewPerson(name, 30, nil))
+		require.NoError(t, err)
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func relate(t *testing.T, repo *Repository, from, to uuid.UUID, rt domain.RelationType) {
+	t.Helper()
+	require.NoError(t, repo.AddRelationship(context.Background(), domain.NewRelationship(from, to, rt)))
+}
+
+func TestAddRelationship(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ids := addPersons(t, repo, "Ann", "Bob")
+
+	relate(t, repo, ids[0], ids[1], domain.RelationParent)
+	relate(t, repo, ids[1], ids[0], domain.RelationFriend)
+
+	err := repo.AddRelationship(ctx, domain.NewRelationship(ids[0], ids[1], domain.RelationParent))
+	assert.ErrorIs(t, err, person.ErrRelationshipExists)
+	err = repo.AddRelationship(ctx, domain.NewRelationship(ids[0], ids[0], domain.RelationFriend))
+	assert.ErrorIs(t, err, person.ErrSelfRelationship)
+	err = repo.AddRelationship(ctx, domain.NewRelationship(ids[0], uuid.New(), domain.RelationFriend))
+	assert.ErrorIs(t, err, person.ErrNotFound)
+
+	rels, err := repo.GetRelationships(ctx, ids[1])
+	require.NoError(t, err)
+	require.Len(t, rels, 2)
+	assert.Equal(t, domain.RelationParent, rels[0].Type)
+	assert.Equal(t, domain.RelationFriend, rels[1].Type)
+
+	require.NoError(t, repo.DeleteRelationship(ctx, ids[0], ids[1], domain.RelationParent))
+	assert.ErrorIs(t, repo.DeleteRelationship(ctx, ids[0], ids[1], domain.RelationParent), person.ErrNotFound)
+	rels, _ = repo.GetRelationships(ctx, ids[0])
+	assert.Len(t, rels, 1)
+}
+
+func TestDeletePerson_DropsRelationships(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ids := addPersons(t, repo, "Ann", "Bob", "Cid")
+	relate(t, repo, ids[0], ids[1], domain.RelationFriend)
+	relate(t, repo, ids[2], ids[1], domain.RelationManager)
+	relate(t, repo, ids[0], ids[2], domain.RelationFriend)
+
+	errFail := errors.New("fail")
+	err := repo.InTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, repo.DeletePerson(ctx, ids[1]))
+		return errFail
+	})
+	assert.ErrorIs(t, err, errFail)
+	rels, _ := repo.GetRelationships(ctx, ids[1])
+	assert.Len(t, rels, 2, "rollback restores the relationships")
+
+	require.NoError(t, repo.DeletePerson(ctx, ids[1]))
+	for _, id := range []uuid.UUID{ids[0], ids[2]} {
+		rels, err := repo.GetRelationships(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, rels, 1)
+		assert.NotEqual(t, ids[1], rels[0].Other(id))
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ids := addPersons(t, repo, "Ann", "Bob", "Cid", "Dee", "Eve")
+	// Ann -friend-> Bob -friend-> Cid -friend-> Dee, Dee -manager-> Ann
+	relate(t, repo, ids[0], ids[1], domain.RelationFriend)
+	relate(t, repo, ids[1], ids[2], domain.RelationFriend)
+	relate(t, repo, ids[2], ids[3], domain.RelationFriend)
+	relate(t, repo, ids[3], ids[0], domain.RelationManager)
+
+	path, err := repo.ShortestPath(ctx, ids[0], ids[3], domain.PathQuery{MaxDepth: 6})
+	require.NoError(t, err)
+	require.Len(t, path.Relationships, 1)
+	assert.Equal(t, domain.RelationManager, path.Relationships[0].Type)
+	assert.Equal(t, "Dee", path.Persons[1].Name)
+
+	path, err = repo.ShortestPath(ctx, ids[0], ids[3], domain.PathQuery{MaxDepth: 6, Directed: true})
+	require.NoError(t, err)
+	require.Len(t, path.Persons, 4)
+	for i, name := range []string{"Ann", "Bob", "Cid", "Dee"} {
+		assert.Equal(t, name, path.Persons[i].Name)
+	}
+
+	_, err = repo.ShortestPath(ctx, ids[0], ids[3], domain.PathQuery{MaxDepth: 2, Directed: true})
+	assert.ErrorIs(t, err, person.ErrNoPath)
+	_, err = repo.ShortestPath(ctx, ids[0], ids[3], domain.PathQuery{MaxDepth: 6, Types: []domain.RelationType{domain.RelationSpouse}})
+	assert.ErrorIs(t, err, person.ErrNoPath)
+	_, err = repo.ShortestPath(ctx, ids[0], ids[4], domain.PathQuery{MaxDepth: 6})
+	assert.ErrorIs(t, err, person.ErrNoPath)
+
+	path, err = repo.ShortestPath(ctx, ids[0], ids[0], domain.PathQuery{MaxDepth: 6})
+	require.NoError(t, err)
+	assert.Len(t, path.Persons, 1)
+	assert.Empty(t, path.Relationships)
+}
+
+func TestFriendsOfFriends(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ids := addPersons(t, repo, "Ann", "Bob", "Cid", "Dee", "Eve", "Fay")
+	ann, bob, cid, dee, eve := ids[0], ids[1], ids[2], ids[3], ids[4]
+	relate(t, repo, ann, bob, domain.RelationFriend)
+	relate(t, repo, cid, ann, domain.RelationFriend)
+	relate(t, repo, bob, cid, domain.RelationFriend)
+	relate(t, repo, bob, dee, domain.RelationFriend)
+	relate(t, repo, eve, cid, domain.RelationFriend)
+	relate(t, repo, dee, cid, domain.RelationFriend)
+	relate(t, repo, bob, ids[5], domain.RelationManager)
+
+	suggestions, err := repo.FriendsOfFriends(ctx, ann)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, dee, suggestions[0].Person.ID)
+	assert.Equal(t, 2, suggestions[0].Mutual)
+	assert.Equal(t, eve, suggestions[1].Person.ID)
+	assert.Equal(t, 1, suggestions[1].Mutual)
+
+	_, err = repo.FriendsOfFriends(ctx, uuid.New())
+	assert.ErrorIs(t, err, person.ErrNotFound)
+}
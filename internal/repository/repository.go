@@ -1,13 +1,17 @@
 package repository
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lafetz/assessment/internal/core/domain"
 	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/outbox"
 )
 
 var (
@@ -17,52 +21,116 @@ var (
 type Repository struct {
 	mu      sync.RWMutex
 	storage map[uuid.UUID]domain.Person
+	indexes []*uniqueIndex
+	// tombstones redirect persons merged away to the one they merged into.
+	tombstones map[uuid.UUID]uuid.UUID
+	merges     map[uuid.UUID][]domain.Merge
+	// out and in index relationships by their source and target.
+	out    map[uuid.UUID][]domain.Relationship
+	in     map[uuid.UUID][]domain.Relationship
+	groups map[uuid.UUID]domain.Group
+	// members and memberOf index memberships by group and by person.
+	members  map[uuid.UUID]map[uuid.UUID]member
+	memberOf map[uuid.UUID]map[uuid.UUID]member
+	stats    counters
+	// schemas maps tenants to their attribute schema.
+	schemas map[string]domain.AttributeSchema
+	outbox  []outbox.Record
+	lastSeq uint64
 }
 
-func NewRepository() *Repository {
-	return &Repository{
-		storage: make(map[uuid.UUID]domain.Person),
+// Option configures a Repository.
+type Option func(*Repository)
+
+// WithUniqueIndexes replaces the default unique index on email with
+// indexes on fields, which must be listed in UniqueFields.
+func WithUniqueIndexes(fields ...string) Option {
+	return func(r *Repository) {
+		r.indexes = nil
+		for _, field := range fields {
+			if key, ok := uniqueKeys[field]; ok {
+				r.indexes = append(r.indexes, &uniqueIndex{field: field, key: key, ids: make(map[string]uuid.UUID)})
+			}
+		}
 	}
 }
 
+func NewRepository(opts ...Option) *Repository {
+	r := &Repository{
+		storage:    make(map[uuid.UUID]domain.Person),
+		tombstones: make(map[uuid.UUID]uuid.UUID),
+		merges:     make(map[uuid.UUID][]domain.Merge),
+		out:        make(map[uuid.UUID][]domain.Relationship),
+		in:         make(map[uuid.UUID][]domain.Relationship),
+		groups:     make(map[uuid.UUID]domain.Group),
+		members:    make(map[uuid.UUID]map[uuid.UUID]member),
+		memberOf:   make(map[uuid.UUID]map[uuid.UUID]member),
+		stats:      newCounters(),
+		schemas:    make(map[string]domain.AttributeSchema),
+	}
+	WithUniqueIndexes(DefaultUniqueFields...)(r)
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
 func (r *Repository) AddPerson(ctx context.Context, person domain.Person) (domain.Person, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	defer r.lock(ctx)()
 
 	if _, exists := r.storage[person.ID]; exists {
 		return domain.Person{}, ErrDuplicatePk
 	}
+	if err := r.checkUnique(person); err != nil {
+		return domain.Person{}, err
+	}
 
-	r.storage[person.ID] = person
-	return person, nil
+	r.undo(ctx, func() { r.remove(person.ID) })
+	r.put(person)
+	return load(person), nil
 }
 
 func (r *Repository) GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	defer r.rlock(ctx)()
 
 	p, exists := r.storage[id]
 	if !exists {
-		return domain.Person{}, person.ErrNotFound
+		return domain.Person{}, r.notFound(id)
 	}
-	return p, nil
+	return load(p), nil
 }
 
-func (r *Repository) GetPersons(ctx context.Context, page, size int32) ([]domain.Person, domain.Metadata, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+func (r *Repository) GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error) {
+	defer r.rlock(ctx)()
 
 	persons := make([]domain.Person, 0, len(r.storage))
 	for _, person := range r.storage {
-		persons = append(persons, person)
+		if filter.Matches(person) {
+			persons = append(persons, load(person))
+		}
 	}
+	// Map iteration order is random; sort so pages are stable between calls.
+	slices.SortFunc(persons, comparePersons)
+	persons, meta := paginate(persons, page, size)
+	return persons, meta, nil
+}
+
+func comparePersons(a, b domain.Person) int {
+	if c := cmp.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID.String(), b.ID.String())
+}
 
-	totalRecords := int32(len(persons))
+// paginate returns the page of items at the zero-based page of the given
+// size, with its metadata.
+func paginate[T any](items []T, page, size int32) ([]T, domain.Metadata) {
+	totalRecords := int32(len(items))
 	offset := page * size
 	limit := size
 
 	if offset > totalRecords {
-		return []domain.Person{}, domain.CalculateMetadata(totalRecords, offset, limit), nil
+		return []T{}, domain.CalculateMetadata(totalRecords, offset, limit)
 	}
 
 	end := offset + limit
@@ -70,29 +138,94 @@ func (r *Repository) GetPersons(ctx context.Context, page, size int32) ([]domain
 		end = totalRecords
 	}
 
-	return persons[offset:end], domain.CalculateMetadata(totalRecords, offset, limit), nil
+	return items[offset:end], domain.CalculateMetadata(totalRecords, offset, limit)
 }
 
 func (r *Repository) DeletePerson(ctx context.Context, id uuid.UUID) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	defer r.lock(ctx)()
 
-	if _, exists := r.storage[id]; !exists {
+	old, exists := r.storage[id]
+	if !exists {
 		return person.ErrNotFound
 	}
 
-	delete(r.storage, id)
+	r.undo(ctx, func() { r.put(old) })
+	r.remove(id)
+	r.dropRelationships(ctx, id)
+	r.dropMemberships(ctx, id)
 	return nil
 }
 
 func (r *Repository) UpdatePerson(ctx context.Context, p domain.Person) (domain.Person, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	defer r.lock(ctx)()
 
-	if _, exists := r.storage[p.ID]; !exists {
+	old, exists := r.storage[p.ID]
+	if !exists {
 		return domain.Person{}, person.ErrNotFound
 	}
+	if err := r.checkUnique(p); err != nil {
+		return domain.Person{}, err
+	}
 
+	r.undo(ctx, func() { r.remove(p.ID); r.put(old) })
+	r.remove(p.ID)
+	r.put(p)
+	return load(p), nil
+}
+
+// put stores p and adds it to the unique indexes and counters. Ages
+// derived from a birth date are not stored.
+func (r *Repository) put(p domain.Person) {
+	if !p.BirthDate.IsZero() {
+		p.Age = 0
+	}
 	r.storage[p.ID] = p
-	return p, nil
+	for _, idx := range r.indexes {
+		if k := idx.key(p); k != "" {
+			idx.ids[k] = p.ID
+		}
+	}
+	r.stats.add(p, 1)
+}
+
+func (r *Repository) remove(id uuid.UUID) {
+	if p, ok := r.storage[id]; ok {
+		for _, idx := range r.indexes {
+			if k := idx.key(p); k != "" {
+				delete(idx.ids, k)
+			}
+		}
+		r.stats.add(p, -1)
+	}
+	delete(r.storage, id)
+}
+
+// load returns a stored person as seen by callers.
+func load(p domain.Person) domain.Person {
+	return p.WithDerivedAge(time.Now())
+}
+
+// Count returns the number of stored persons.
+func (r *Repository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.storage)
+}
+
+// Ping reports whether the store can be read before ctx expires. It fails
+// when a writer holds the lock for too long.
+func (r *Repository) Ping(ctx context.Context) error {
+	acquired := make(chan struct{})
+	go func() {
+		r.mu.RLock()
+		r.mu.RUnlock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lafetz/assessment/internal/core/domain"
@@ -138,7 +140,7 @@ func TestGetPersons(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			retrievedPersons, metadata, err := repo.GetPersons(context.Background(), tt.page, tt.size)
+			retrievedPersons, metadata, err := repo.GetPersons(context.Background(), domain.PersonFilter{}, tt.page, tt.size)
 			assert.NoError(t, err, "expected no error when getting persons")
 			assert.Len(t, retrievedPersons, tt.expectedLen, "expected length of retrieved persons to match")
 			assert.Equal(t, tt.expectedTotalRecords, int32(metadata.TotalRecords), "expected total records to match the added persons count")
@@ -147,3 +149,205 @@ func TestGetPersons(t *testing.T) {
 		})
 	}
 }
+
+func TestPing(t *testing.T) {
+	repo := NewRepository()
+	assert.NoError(t, repo.Ping(context.Background()))
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, repo.Ping(ctx), context.DeadlineExceeded)
+}
+
+func TestInTx_RollsBackOnError(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	kept := domain.Person{ID: uuid.New(), Name: "Kept", Age: 40, Hobbies: []string{"Chess"}}
+	removed := domain.Person{ID: uuid.New(), Name: "Removed", Age: 50, Hobbies: []string{"Golf"}}
+	_, _ = repo.AddPerson(ctx, kept)
+	_, _ = repo.AddPerson(ctx, removed)
+
+	errFail := errors.New("fail")
+	err := repo.InTx(ctx, func(ctx context.Context) error {
+		_, err := repo.AddPerson(ctx, domain.Person{ID: uuid.New(), Name: "Added"})
+		assert.NoError(t, err)
+		_, err = repo.UpdatePerson(ctx, domain.Person{ID: kept.ID, Name: "Renamed"})
+		assert.NoError(t, err)
+		assert.NoError(t, repo.DeletePerson(ctx, removed.ID))
+		assert.NoError(t, repo.AppendEvents(ctx, domain.NewEvent(domain.EventPersonDeleted, removed)))
+
+		got, err := repo.GetPerson(ctx, kept.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Renamed", got.Name)
+		return errFail
+	})
+	assert.ErrorIs(t, err, errFail)
+
+	assert.Equal(t, 2, repo.Count())
+	got, err := repo.GetPerson(ctx, kept.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, kept, got)
+	_, err = repo.GetPerson(ctx, removed.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, repo.OutboxLen())
+}
+
+func TestInTx_Commits(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	p := domain.NewPerson("Committed", 30, []string{"Running"})
+
+	err := repo.InTx(ctx, func(ctx context.Context) error {
+		if _, err := repo.AddPerson(ctx, p); err != nil {
+			return err
+		}
+		return repo.AppendEvents(ctx, domain.NewEvent(domain.EventPersonCreated, p))
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.GetPerson(ctx, p.ID)
+	assert.NoError(t, err)
+	records, err := repo.OutboxAfter(ctx, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, uint64(1), records[0].Seq)
+	assert.Equal(t, p.ID, records[0].Event.Person.ID)
+}
+
+func TestOutbox_AfterAndPrune(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.AppendEvents(ctx, domain.NewEvent(domain.EventPersonCreated, domain.Person{ID: uuid.New()})))
+	}
+
+	records, err := repo.OutboxAfter(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, []uint64{records[0].Seq, records[1].Seq})
+
+	assert.NoError(t, repo.PruneOutbox(ctx, 3))
+	assert.Equal(t, 2, repo.OutboxLen())
+	records, err = repo.OutboxAfter(ctx, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), records[0].Seq)
+}
+
+func TestEmailUniqueness(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	alice := domain.NewPerson("Alice", 30, []string{"Chess"})
+	alice.Email = "alice@example.com"
+	_, err := repo.AddPerson(ctx, alice)
+	assert.NoError(t, err)
+
+	other := domain.NewPerson("Alice Again", 31, []string{"Chess"})
+	other.Email = " ALICE@example.com"
+	_, err = repo.AddPerson(ctx, other)
+	var violation *person.UniqueViolation
+	assert.ErrorAs(t, err, &violation)
+	assert.Equal(t, "email", violation.Field)
+	assert.Equal(t, alice.ID, violation.PersonID)
+	assert.ErrorIs(t, err, person.ErrConflict)
+
+	// Keeping one's own email is not a conflict.
+	alice.Name = "Alice B"
+	_, err = repo.UpdatePerson(ctx, alice)
+	assert.NoError(t, err)
+
+	// The email is released when it changes or its holder is deleted.
+	alice.Email = "alice.b@example.com"
+	_, err = repo.UpdatePerson(ctx, alice)
+	assert.NoError(t, err)
+	_, err = repo.AddPerson(ctx, other)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.DeletePerson(ctx, other.ID))
+	bob := domain.NewPerson("Bob", 40, []string{"Golf"})
+	bob.Email = "alice@example.com"
+	_, err = repo.AddPerson(ctx, bob)
+	assert.NoError(t, err)
+}
+
+func TestAgeDerivedFromBirthDate(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	p := domain.NewPerson("Born", 99, []string{"Reading"})
+	p.BirthDate = time.Now().UTC().AddDate(-20, 0, -1).Truncate(24 * time.Hour)
+
+	added, err := repo.AddPerson(ctx, p)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(20), added.Age)
+	got, err := repo.GetPerson(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(20), got.Age)
+	assert.Zero(t, repo.storage[p.ID].Age)
+}
+
+func TestAgeOn(t *testing.T) {
+	birth := time.Date(2000, time.March, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int32(23), domain.AgeOn(birth, time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int32(24), domain.AgeOn(birth, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int32(0), domain.AgeOn(birth, time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestWithUniqueIndexes(t *testing.T) {
+	ctx := context.Background()
+	a := domain.NewPerson("A", 30, []string{"x"})
+	a.Email, a.Phone = "same@example.com", "+14155552671"
+	b := domain.NewPerson("B", 30, []string{"x"})
+	b.Email, b.Phone = "same@example.com", "+14155552671"
+
+	repo := NewRepository(WithUniqueIndexes("phone"))
+	_, err := repo.AddPerson(ctx, a)
+	assert.NoError(t, err)
+	_, err = repo.AddPerson(ctx, b)
+	var violation *person.UniqueViolation
+	assert.ErrorAs(t, err, &violation)
+	assert.Equal(t, "phone", violation.Field)
+
+	repo = NewRepository(WithUniqueIndexes())
+	_, _ = repo.AddPerson(ctx, a)
+	_, err = repo.AddPerson(ctx, b)
+	assert.NoError(t, err)
+}
+
+func TestHobbyCounts(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	a, _ := repo.AddPerson(ctx, domain.NewPerson("A", 30, []string{"Chess", "Golf", "Chess"}))
+	_, _ = repo.AddPerson(ctx, domain.NewPerson("B", 30, []string{"Chess"}))
+
+	counts, err := repo.HobbyCounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"Chess": 2, "Golf": 1}, counts)
+
+	a.Hobbies = []string{"Golf", "Yoga"}
+	_, _ = repo.UpdatePerson(ctx, a)
+	counts, _ = repo.HobbyCounts(ctx)
+	assert.Equal(t, map[string]int{"Chess": 1, "Golf": 1, "Yoga": 1}, counts)
+
+	_ = repo.DeletePerson(ctx, a.ID)
+	counts, _ = repo.HobbyCounts(ctx)
+	assert.Equal(t, map[string]int{"Chess": 1}, counts)
+}
+
+func TestSeedData_NormalizesHobbies(t *testing.T) {
+	repo := NewRepository()
+	catalog, err := domain.NewHobbyCatalog(domain.DefaultHobbies)
+	assert.NoError(t, err)
+	repo.SeedData(catalog)
+
+	counts, err := repo.HobbyCounts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counts["Video Games"])
+	assert.Equal(t, 2, counts["Dancing"])
+	assert.NotContains(t, counts, "Gaming")
+	assert.NotContains(t, counts, "Dance")
+	assert.Equal(t, len(SeedPersons()), repo.Count())
+}
+
+func TestCheckUniqueFields(t *testing.T) {
+	assert.NoError(t, CheckUniqueFields("email", "phone"))
+	assert.ErrorContains(t, CheckUniqueFields("email", "name"), `"name" must be one of email, phone`)
+}
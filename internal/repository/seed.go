@@ -5,11 +5,10 @@ import (
 	"github.com/lafetz/assessment/internal/core/domain"
 )
 
-func (r *Repository) SeedData() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	people := []domain.Person{
+// SeedPersons returns the sample persons used to seed a fresh store, each
+// with a new ID.
+func SeedPersons() []domain.Person {
+	return []domain.Person{
 		{ID: uuid.New(), Name: "Alice Johnson", Age: 28, Hobbies: []string{"Photography", "Traveling", "Cooking"}},
 		{ID: uuid.New(), Name: "Bob Smith", Age: 32, Hobbies: []string{"Reading", "Hiking", "Cycling"}},
 		{ID: uuid.New(), Name: "Charlie Brown", Age: 25, Hobbies: []string{"Gaming", "Music", "Drawing"}},
@@ -27,8 +26,16 @@ func (r *Repository) SeedData() {
 		{ID: uuid.New(), Name: "Olivia Clark", Age: 31, Hobbies: []string{"Reading", "Yoga", "Traveling"}},
 		{ID: uuid.New(), Name: "Paul Lewis", Age: 29, Hobbies: []string{"Video Games", "Football", "Photography"}},
 	}
+}
+
+// SeedData stores the SeedPersons with their hobbies normalized against
+// hobbies, as the service would have stored them.
+func (r *Repository) SeedData(hobbies *domain.HobbyCatalog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	for _, person := range people {
-		r.storage[person.ID] = person
+	for _, person := range SeedPersons() {
+		person.Hobbies = hobbies.Normalize(person.Hobbies)
+		r.put(person)
 	}
 }
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// counters aggregate the stored persons. put and remove keep them up to
+// date so that reading them does not scan the persons.
+type counters struct {
+	// ages counts persons without a birth date by age; birthDates counts
+	// the others, whose age changes with time.
+	ages       map[int32]int
+	birthDates map[time.Time]int
+	hobbies    map[string]int
+	pairs      map[domain.HobbyPair]int
+	// holders indexes persons by hobby.
+	holders map[string]map[uuid.UUID]struct{}
+}
+
+func newCounters() counters {
+	return counters{
+		ages:       make(map[int32]int),
+		birthDates: make(map[time.Time]int),
+		hobbies:    make(map[string]int),
+		pairs:      make(map[domain.HobbyPair]int),
+		holders:    make(map[string]map[uuid.UUID]struct{}),
+	}
+}
+
+// add counts p delta times, where delta is 1 when p is stored and -1 when
+// it is removed.
+func (c counters) add(p domain.Person, delta int) {
+	if p.BirthDate.IsZero() {
+		addCount(c.ages, p.Age, delta)
+	} else {
+		addCount(c.birthDates, p.BirthDate, delta)
+	}
+	hobbies := distinct(p.Hobbies)
+	for i, h := range hobbies {
+		addCount(c.hobbies, h, delta)
+		switch {
+		case delta > 0 && c.holders[h] == nil:
+			c.holders[h] = map[uuid.UUID]struct{}{p.ID: {}}
+		case delta > 0:
+			c.holders[h][p.ID] = struct{}{}
+		case len(c.holders[h]) > 1:
+			delete(c.holders[h], p.ID)
+		default:
+			delete(c.holders, h)
+		}
+		for _, other := range hobbies[i+1:] {
+			addCount(c.pairs, domain.NewHobbyPair(h, other), delta)
+		}
+	}
+}
+
+func addCount[K comparable](counts map[K]int, k K, delta int) {
+	if counts[k] += delta; counts[k] <= 0 {
+		delete(counts, k)
+	}
+}
+
+func distinct(hobbies []string) []string {
+	hobbies = slices.Clone(hobbies)
+	slices.Sort(hobbies)
+	return slices.Compact(hobbies)
+}
+
+// HobbyCounts returns the number of persons having each stored hobby.
+func (r *Repository) HobbyCounts(ctx context.Context) (map[string]int, error) {
+	defer r.rlock(ctx)()
+
+	return maps.Clone(r.stats.hobbies), nil
+}
+
+// PersonsSharingHobbies returns the persons having any of hobbies, found
+// through an index rather than by scanning every person.
+func (r *Repository) PersonsSharingHobbies(ctx context.Context, hobbies []string) ([]domain.Person, error) {
+	defer r.rlock(ctx)()
+
+	seen := make(map[uuid.UUID]bool)
+	var persons []domain.Person
+	for _, h := range hobbies {
+		for id := range r.stats.holders[h] {
+			if !seen[id] {
+				seen[id] = true
+				persons = append(persons, load(r.storage[id]))
+			}
+		}
+	}
+	return persons, nil
+}
+
+// Aggregates returns the counters of the persons matching filter, with ages
+// derived from birth dates as of now. Without a filter they are read from
+// the counters kept up to date; a filter counts the matching persons.
+func (r *Repository) Aggregates(ctx context.Context, filter domain.PersonFilter) (domain.Aggregates, error) {
+	defer r.rlock(ctx)()
+
+	if len(filter.Attributes) == 0 {
+		return r.stats.aggregates(len(r.storage), time.Now()), nil
+	}
+	matching, total := newCounters(), 0
+	for _, p := range r.storage {
+		if filter.Matches(p) {
+			matching.add(p, 1)
+			total++
+		}
+	}
+	return matching.aggregates(total, time.Now()), nil
+}
+
+func (c counters) aggregates(total int, now time.Time) domain.Aggregates {
+	ages := maps.Clone(c.ages)
+	for birth, n := range c.birthDates {
+		ages[domain.AgeOn(birth, now)] += n
+	}
+	return domain.Aggregates{
+		Total:      total,
+		Ages:       ages,
+		Hobbies:    maps.Clone(c.hobbies),
+		HobbyPairs: maps.Clone(c.pairs),
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregates(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	a, _ := repo.AddPerson(ctx, domain.NewPerson("A", 30, []string{"Chess", "Golf", "Yoga"}))
+	b := domain.NewPerson("B", 0, []string{"Golf", "Chess"})
+	b.BirthDate = time.Date(time.Now().Year()-40, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, _ = repo.AddPerson(ctx, b)
+
+	got, err := repo.Aggregates(ctx, domain.PersonFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Total)
+	assert.Equal(t, map[int32]int{30: 1, 40: 1}, got.Ages)
+	assert.Equal(t, map[domain.HobbyPair]int{
+		{"Chess", "Golf"}: 2,
+		{"Chess", "Yoga"}: 1,
+		{"Golf", "Yoga"}:  1,
+	}, got.HobbyPairs)
+
+	a.Age, a.Hobbies = 31, []string{"Chess"}
+	_, _ = repo.UpdatePerson(ctx, a)
+	_ = repo.DeletePerson(ctx, b.ID)
+	got, _ = repo.Aggregates(ctx, domain.PersonFilter{})
+	assert.Equal(t, 1, got.Total)
+	assert.Equal(t, map[int32]int{31: 1}, got.Ages)
+	assert.Equal(t, map[string]int{"Chess": 1}, got.Hobbies)
+	assert.Empty(t, got.HobbyPairs)
+}
+
+func TestAggregates_Filter(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	a := domain.NewPerson("A", 30, []string{"Chess", "Golf"})
+	a.Attributes = domain.Attributes{"department": "sales"}
+	_, _ = repo.AddPerson(ctx, a)
+	b := domain.NewPerson("B", 0, []string{"Golf"})
+	b.BirthDate = time.Date(time.Now().Year()-40, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.Attributes = domain.Attributes{"department": "sales"}
+	_, _ = repo.AddPerson(ctx, b)
+	_, _ = repo.AddPerson(ctx, domain.NewPerson("C", 50, []string{"Yoga"}))
+
+	got, err := repo.Aggregates(ctx, domain.PersonFilter{Attributes: map[string]string{"department": "sales"}})
+	require.NoError(t, err)
+	assert.Equal(t, domain.Aggregates{
+		Total:      2,
+		Ages:       map[int32]int{30: 1, 40: 1},
+		Hobbies:    map[string]int{"Chess": 1, "Golf": 2},
+		HobbyPairs: map[domain.HobbyPair]int{{"Chess", "Golf"}: 1},
+	}, got)
+
+	got, err = repo.Aggregates(ctx, domain.PersonFilter{Attributes: map[string]string{"department": "hr"}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.Total)
+	assert.Empty(t, got.Ages)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import "context"
+
+// tx is an open transaction on a Repository. It holds the write lock for
+// its whole lifetime and records how to revert every change made in it.
+type tx struct {
+	repo *Repository
+	undo []func()
+}
+
+type txKey struct{}
+
+// InTx runs fn holding the write lock. Writes made with the context passed
+// to fn, including appended outbox events, are reverted if fn returns an
+// error or panics. Calls nested in fn join the enclosing transaction.
+func (r *Repository) InTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if r.current(ctx) != nil {
+		return fn(ctx)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := &tx{repo: r}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		for i := len(t.undo) - 1; i >= 0; i-- {
+			t.undo[i]()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// current returns the transaction of r carried by ctx, if any.
+func (r *Repository) current(ctx context.Context) *tx {
+	t, _ := ctx.Value(txKey{}).(*tx)
+	if t == nil || t.repo != r {
+		return nil
+	}
+	return t
+}
+
+// lock takes the write lock unless ctx carries a transaction that already
+// holds it, and returns the matching unlock.
+func (r *Repository) lock(ctx context.Context) func() {
+	if r.current(ctx) != nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock is lock for readers.
+func (r *Repository) rlock(ctx context.Context) func() {
+	if r.current(ctx) != nil {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// undo records how to revert a change when it is made inside a transaction.
+func (r *Repository) undo(ctx context.Context, fn func()) {
+	if t := r.current(ctx); t != nil {
+		t.undo = append(t.undo, fn)
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+)
+
+// DefaultUniqueFields are indexed unless WithUniqueIndexes says otherwise.
+var DefaultUniqueFields = []string{"email"}
+
+// uniqueKeys maps each field that can be indexed to the normalized value it
+// is indexed by. Empty values are not indexed.
+var uniqueKeys = map[string]func(domain.Person) string{
+	"email": func(p domain.Person) string { return strings.ToLower(strings.TrimSpace(p.Email)) },
+	"phone": func(p domain.Person) string { return p.Phone },
+}
+
+// UniqueFields lists the fields WithUniqueIndexes accepts.
+func UniqueFields() []string {
+	fields := make([]string, 0, len(uniqueKeys))
+	for field := range uniqueKeys {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// CheckUniqueFields fails for fields WithUniqueIndexes does not accept,
+// which it would otherwise ignore.
+func CheckUniqueFields(fields ...string) error {
+	for _, field := range fields {
+		if _, ok := uniqueKeys[field]; !ok {
+			return fmt.Errorf("unique field %q must be one of %s", field, strings.Join(UniqueFields(), ", "))
+		}
+	}
+	return nil
+}
+
+type uniqueIndex struct {
+	field string
+	key   func(domain.Person) string
+	ids   map[string]uuid.UUID
+}
+
+// checkUnique fails when another person holds an indexed value of p.
+func (r *Repository) checkUnique(p domain.Person) error {
+	for _, idx := range r.indexes {
+		k := idx.key(p)
+		if k == "" {
+			continue
+		}
+		if holder, ok := idx.ids[k]; ok && holder != p.ID {
+			return &person.UniqueViolation{Field: idx.field, PersonID: holder}
+		}
+	}
+	return nil
+}
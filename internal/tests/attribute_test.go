@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributes(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	do := func(tenant, method, path, body string, v any) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if tenant != "" {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if v != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		}
+		return resp.StatusCode
+	}
+	const schemaPath = "/api/v1/admin/attribute-schema"
+
+	assert.Equal(t, http.StatusNotFound, do("acme", http.MethodGet, schemaPath, "", nil))
+	assert.Equal(t, http.StatusBadRequest, do("acme", http.MethodPut, schemaPath, `{"type":"object","properties":{"tags":{"type":"string","format":"email"}}}`, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, do("acme", http.MethodPut, schemaPath, `{"type":"array"}`, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, do("acme", http.MethodPut, schemaPath, `{"type":"object","required":["badge"]}`, nil))
+	assert.Equal(t, http.StatusBadRequest, do("acme corp", http.MethodGet, schemaPath, "", nil))
+
+	var schema dto.AttributeSchema
+	require.Equal(t, http.StatusOK, do("acme", http.MethodPut, schemaPath, `{
+		"type": "object",
+		"properties": {
+			"department": {"type": "string", "enum": ["sales", "engineering"]},
+			"badge": {"type": "integer", "minimum": 1}
+		},
+		"required": ["department"],
+		"additionalProperties": false
+	}`, &schema))
+	assert.Len(t, schema.Properties, 2)
+	require.Equal(t, http.StatusOK, do("acme", http.MethodGet, schemaPath, "", &schema))
+	assert.Equal(t, []string{"department"}, schema.Required)
+	assert.False(t, *schema.AdditionalProperties)
+
+	var invalid customvalidator.ValidationErrorResponse
+	require.Equal(t, http.StatusUnprocessableEntity, do("acme", http.MethodPost, "/api/v1/persons",
+		`{"name":"Ann","age":30,"hobbies":["Chess"],"attributes":{"badge":0,"shirt":"M"}}`, &invalid))
+	assert.Equal(t, map[string]any{
+		"attributes.department": "This field is required",
+		"attributes.badge":      "can not be less than 1",
+		"attributes.shirt":      "is not a defined attribute",
+	}, invalid.Errors)
+
+	var ann, bob dto.JSONPerson
+	require.Equal(t, http.StatusCreated, do("acme", http.MethodPost, "/api/v1/persons",
+		`{"name":"Ann","age":30,"hobbies":["Chess"],"attributes":{"department":"sales","badge":7}}`, &ann))
+	assert.Equal(t, map[string]any{"department": "sales", "badge": 7.0}, ann.Attributes)
+	require.Equal(t, http.StatusCreated, do("acme", http.MethodPost, "/api/v1/persons",
+		`{"name":"Bob","age":40,"hobbies":["Golf"],"attributes":{"department":"engineering"}}`, &bob))
+
+	// Tenants without a schema accept no attributes.
+	assert.Equal(t, http.StatusUnprocessableEntity, do("", http.MethodPost, "/api/v1/persons",
+		`{"name":"Cy","age":20,"hobbies":["Go"],"attributes":{"department":"sales"}}`, nil))
+	assert.Equal(t, http.StatusCreated, do("", http.MethodPost, "/api/v1/persons", `{"name":"Cy","age":20,"hobbies":["Go"]}`, nil))
+
+	var patched dto.JSONPerson
+	require.Equal(t, http.StatusOK, do("acme", http.MethodPatch, "/api/v1/persons/"+ann.ID.String(), `{"attributes":{"badge":null,"department":"engineering"}}`, &patched))
+	assert.Equal(t, map[string]any{"department": "engineering"}, patched.Attributes)
+	assert.Equal(t, http.StatusUnprocessableEntity, do("acme", http.MethodPatch, "/api/v1/persons/"+ann.ID.String(), `{"attributes":{"department":null}}`, nil))
+
+	var list dto.GetPersonsResponse
+	require.Equal(t, http.StatusOK, do("acme", http.MethodGet, "/api/v1/persons?attributes.department=engineering", "", &list))
+	assert.Len(t, list.Persons, 2)
+	require.Equal(t, http.StatusOK, do("acme", http.MethodPut, "/api/v1/persons/"+bob.ID.String(),
+		`{"name":"Bob","age":40,"hobbies":["Golf"],"attributes":{"department":"engineering","badge":12}}`, nil))
+	require.Equal(t, http.StatusOK, do("acme", http.MethodGet, "/api/v1/persons?attributes.department=engineering&attributes.badge=12", "", &list))
+	require.Len(t, list.Persons, 1)
+	assert.Equal(t, "Bob", list.Persons[0].Name)
+	assert.Equal(t, int32(1), list.Meta.TotalRecords)
+
+	// Persons are shared, so another tenant's schema only checks the
+	// attributes its writes change.
+	require.Equal(t, http.StatusOK, do("globex", http.MethodPut, schemaPath, `{
+		"type": "object",
+		"properties": {"level": {"type": "integer"}},
+		"required": ["level"],
+		"additionalProperties": false
+	}`, nil))
+	require.Equal(t, http.StatusOK, do("globex", http.MethodPatch, "/api/v1/persons/"+bob.ID.String(), `{"age":41,"attributes":{"level":3}}`, &patched))
+	assert.Equal(t, map[string]any{"department": "engineering", "badge": 12.0, "level": 3.0}, patched.Attributes)
+	require.Equal(t, http.StatusOK, do("globex", http.MethodPut, "/api/v1/persons/"+bob.ID.String(), `{"name":"Bob","age":42,"hobbies":["Golf"]}`, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, do("globex", http.MethodPatch, "/api/v1/persons/"+bob.ID.String(), `{"attributes":{"badge":13}}`, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, do("globex", http.MethodPatch, "/api/v1/persons/"+ann.ID.String(), `{"attributes":{"level":"high"}}`, nil))
+	require.Equal(t, http.StatusOK, do("acme", http.MethodPatch, "/api/v1/persons/"+bob.ID.String(), `{"attributes":{"badge":13}}`, &patched))
+	assert.Equal(t, 13.0, patched.Attributes["badge"])
+}
+
+func TestAttributeSchema_AdminToken(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithAdminToken("s3cret"))
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	put := func(authorization string) int {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/api/v1/admin/attribute-schema", bytes.NewBufferString(`{"type":"object","properties":{}}`))
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, put(""))
+	assert.Equal(t, http.StatusUnauthorized, put("Bearer wrong"))
+	assert.Equal(t, http.StatusOK, put("Bearer s3cret"))
+}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/cors"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+	policy, err := cors.New(cors.Config{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true})
+	assert.NoError(t, err)
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithCORS(policy))
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	t.Run("preflight lists route methods", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodOptions, server.URL+"/api/v1/persons/123", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, PUT, PATCH, DELETE", resp.Header.Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("preflight rejects method not served on route", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodOptions, server.URL+"/api/v1/persons", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("actual request from disallowed origin", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/persons", nil)
+		req.Header.Set("Origin", "https://evil.com")
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Origin", resp.Header.Get("Vary"))
+	})
+}
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/events"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseMessage struct {
+	id, event, data string
+	comment         bool
+}
+
+// readSSE parses messages from an event stream until it ends.
+func readSSE(resp *http.Response) <-chan sseMessage {
+	messages := make(chan sseMessage)
+	go func() {
+		defer close(messages)
+		scanner := bufio.NewScanner(resp.Body)
+		var msg sseMessage
+		for scanner.Scan() {
+			line := scanner.Text()
+			field, value, _ := strings.Cut(line, ": ")
+			switch {
+			case line == "":
+				if msg != (sseMessage{}) {
+					messages <- msg
+				}
+				msg = sseMessage{}
+			case strings.HasPrefix(line, ":"):
+				msg.comment = true
+			case field == "id":
+				msg.id = value
+			case field == "event":
+				msg.event = value
+			case field == "data":
+				msg.data = value
+			}
+		}
+	}()
+	return messages
+}
+
+func nextEvent(t *testing.T, messages <-chan sseMessage) sseMessage {
+	t.Helper()
+	for {
+		select {
+		case msg, ok := <-messages:
+			require.True(t, ok, "stream ended")
+			if msg.event != "" {
+				return msg
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event received")
+		}
+	}
+}
+
+func TestPersonEvents(t *testing.T) {
+	bus := events.NewBus(2)
+	personSvc := person.NewPersonSvc(repository.NewRepository(), person.WithPublisher(bus))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithEvents(bus), web.WithEventHeartbeat(10*time.Millisecond))
+
+	server := httptest.NewUnstartedServer(web.Router)
+	// Streams must outlive the write timeout of regular responses.
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	// Registered first so it runs after the streams are cancelled.
+	t.Cleanup(server.Close)
+
+	createPerson := func(name string) dto.JSONPerson {
+		resp, err := http.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(`{"name":"`+name+`","age":30,"hobbies":["Reading"]}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var p dto.JSONPerson
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+		return p
+	}
+	subscribe := func(query string, lastEventID string) <-chan sseMessage {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/persons/events"+query, nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		return readSSE(resp)
+	}
+
+	t.Run("live events with filter and heartbeats", func(t *testing.T) {
+		stream := subscribe("?types=person.deleted", "")
+		p := createPerson("John")
+		req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/v1/persons/"+p.ID.String(), nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		msg := nextEvent(t, stream)
+		assert.Equal(t, "person.deleted", msg.event)
+		var event dto.JSONPersonEvent
+		require.NoError(t, json.Unmarshal([]byte(msg.data), &event))
+		assert.Equal(t, p.ID, event.PersonID)
+		assert.Nil(t, event.Person)
+
+		time.Sleep(100 * time.Millisecond)
+		heartbeat := false
+		for !heartbeat {
+			msg, ok := <-stream
+			require.True(t, ok, "stream ended at the write timeout")
+			heartbeat = msg.comment
+		}
+	})
+
+	t.Run("resume from Last-Event-ID", func(t *testing.T) {
+		live := subscribe("", "")
+		createPerson("Jane")
+		first := nextEvent(t, live)
+		createPerson("Jack")
+		second := nextEvent(t, live)
+
+		resumed := subscribe("", first.id)
+		msg := nextEvent(t, resumed)
+		assert.Equal(t, second.id, msg.id)
+		assert.Contains(t, msg.data, "Jack")
+	})
+
+	t.Run("resync when events were evicted", func(t *testing.T) {
+		resumed := subscribe("", "1")
+		assert.Equal(t, "resync", nextEvent(t, resumed).event)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v1/persons/events?types=person.renamed")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+}
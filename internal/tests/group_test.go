@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroups(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithGroups(person.NewGroupSvc(repo)))
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	ctx := context.Background()
+	ann, _ := personSvc.AddPerson(ctx, domain.NewPerson("Ann", 30, []string{"Chess"}))
+	bob, _ := personSvc.AddPerson(ctx, domain.NewPerson("Bob", 40, []string{"Golf"}))
+
+	do := func(method, path, body string, v any) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if v != nil && resp.StatusCode < 300 {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		}
+		return resp.StatusCode
+	}
+
+	var group dto.JSONGroup
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/api/v1/groups", `{"name":"Platform","description":"Runs the cluster"}`, &group))
+	assert.Equal(t, "Platform", group.Name)
+	assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, "/api/v1/groups", `{}`, nil))
+	members := "/api/v1/groups/" + group.ID.String() + "/members"
+
+	var member dto.JSONMember
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, members, `{"personId":"`+ann.ID.String()+`","role":"owner"}`, &member))
+	assert.Equal(t, "owner", member.Role)
+	assert.Equal(t, "Ann", member.Person.Name)
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, members, `{"personId":"`+bob.ID.String()+`"}`, &member))
+	assert.Equal(t, "member", member.Role)
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, members, `{"personId":"`+bob.ID.String()+`"}`, nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, members, `{"personId":"`+bob.ID.String()+`","role":"boss"}`, nil))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, members, `{"personId":"7c9e6679-7425-40de-944b-e07fc1f90ae7"}`, nil))
+
+	require.Equal(t, http.StatusOK, do(http.MethodPut, members+"/"+bob.ID.String(), `{"role":"lead"}`, &member))
+	assert.Equal(t, "lead", member.Role)
+
+	var list dto.GetMembersResponse
+	require.Equal(t, http.StatusOK, do(http.MethodGet, members+"?size=1&page=0", "", &list))
+	require.Len(t, list.Members, 1)
+	assert.Equal(t, int32(1), list.Meta.CurrentPage)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, members+"?size=1&page=1", "", &list))
+	require.Len(t, list.Members, 1)
+	assert.Equal(t, "Bob", list.Members[0].Person.Name)
+	assert.Equal(t, int32(2), list.Meta.TotalRecords)
+	assert.Equal(t, int32(2), list.Meta.CurrentPage)
+
+	var renamed dto.JSONGroup
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/api/v1/groups/"+group.ID.String(), `{"name":"Infra"}`, &renamed))
+	assert.Equal(t, "Infra", renamed.Name)
+	assert.Equal(t, group.CreatedAt, renamed.CreatedAt)
+
+	t.Run("merge moves memberships", func(t *testing.T) {
+		carl, _ := personSvc.AddPerson(ctx, domain.NewPerson("Carl", 50, []string{"Golf"}))
+		_, err := personSvc.MergePersons(ctx, carl.ID, bob.ID, nil)
+		require.NoError(t, err)
+
+		var groups dto.GetPersonGroupsResponse
+		require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/persons/"+carl.ID.String()+"/groups", "", &groups))
+		require.Len(t, groups.Groups, 1)
+		assert.Equal(t, "Infra", groups.Groups[0].Group.Name)
+		assert.Equal(t, "lead", groups.Groups[0].Role)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, members+"/"+ann.ID.String(), "", nil))
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, members+"/"+ann.ID.String(), "", nil))
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/groups/"+group.ID.String(), "", nil))
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/groups/"+group.ID.String(), "", nil))
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, members, "", nil))
+	})
+}
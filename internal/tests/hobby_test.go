@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHobbyCatalog(t *testing.T) {
+	repo := repository.NewRepository()
+	catalog, err := domain.NewHobbyCatalog(domain.DefaultHobbies)
+	require.NoError(t, err)
+	personSvc := person.NewPersonSvc(repo, person.WithHobbyCatalog(catalog))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	create := func(payload string) dto.JSONPerson {
+		resp, err := http.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(payload))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.JSONPerson
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		return created
+	}
+
+	ann := create(`{"name":"Ann","age":30,"hobbies":["gaming","Video-Games","Jogging"]}`)
+	assert.Equal(t, []string{"Video Games", "Running"}, ann.Hobbies)
+	create(`{"name":"Bob","age":31,"hobbies":["video games","Board games"]}`)
+	create(`{"name":"Cid","age":32,"hobbies":["Gardening","Game design"]}`)
+
+	get := func(query string) (int, dto.GetHobbiesResponse) {
+		resp, err := http.Get(server.URL + "/api/v1/hobbies" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var out dto.GetHobbiesResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		}
+		return resp.StatusCode, out
+	}
+
+	status, got := get("?prefix=GAM")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []dto.JSONHobby{
+		{Name: "Video Games", Category: "Games", Persons: 2},
+		{Name: "Board Games", Category: "Games", Persons: 1},
+		{Name: "Game design", Persons: 1},
+	}, got.Hobbies)
+
+	_, got = get("?limit=1")
+	require.Len(t, got.Hobbies, 1)
+	assert.Equal(t, "Video Games", got.Hobbies[0].Name)
+
+	status, _ = get("?limit=0")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
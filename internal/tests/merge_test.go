@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, e domain.Event) {
+	p.events = append(p.events, e)
+}
+
+func TestMergePersons(t *testing.T) {
+	repo := repository.NewRepository()
+	published := &recordingPublisher{}
+	personSvc := person.NewPersonSvc(repo, person.WithPublisher(published))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	ctx := context.Background()
+	target := domain.NewPerson("Anna Muller", 34, []string{"Chess", "Running"})
+	target.Email = "anna@example.com"
+	target, _ = personSvc.AddPerson(ctx, target)
+	source := domain.NewPerson("Anna Müller", 35, []string{"running", "Piano"})
+	source.Email = "anna.m@example.com"
+	source.Phone = "+4930123456"
+	source, _ = personSvc.AddPerson(ctx, source)
+	published.events = nil
+
+	merge := func(targetID, payload string) (*http.Response, []byte) {
+		resp, err := http.Post(server.URL+"/api/v1/persons/"+targetID+"/merge", "application/json", bytes.NewBufferString(payload))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(resp.Body)
+		return resp, body.Bytes()
+	}
+
+	t.Run("invalid strategy", func(t *testing.T) {
+		resp, _ := merge(target.ID.String(), `{"sourceId":"`+source.ID.String()+`","strategies":{"name":"union"}}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		resp, _ := merge(target.ID.String(), `{"sourceId":"`+target.ID.String()+`"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	resp, body := merge(target.ID.String(), `{"sourceId":"`+source.ID.String()+`","strategies":{
+		"age": "keepSource", "hobbies": "union", "email": "keepSource", "phone": "keepSource"
+	}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var merged dto.JSONPerson
+	require.NoError(t, json.Unmarshal(body, &merged))
+	assert.Equal(t, target.ID, merged.ID)
+	assert.Equal(t, "Anna Muller", merged.Name)
+	assert.Equal(t, int32(35), merged.Age)
+	assert.Equal(t, []string{"Chess", "Running", "Piano"}, merged.Hobbies)
+	assert.Equal(t, "anna.m@example.com", merged.Email)
+	assert.Equal(t, "+4930123456", merged.Phone)
+	assert.Equal(t, 1, repo.Count())
+
+	if assert.Len(t, published.events, 2) {
+		assert.Equal(t, domain.EventPersonDeleted, published.events[0].Type)
+		assert.Equal(t, source.ID, published.events[0].Person.ID)
+		assert.Equal(t, domain.EventPersonUpdated, published.events[1].Type)
+	}
+
+	t.Run("source redirects to target", func(t *testing.T) {
+		resp, err := noRedirect.Get(server.URL + "/api/v1/persons/" + source.ID.String())
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusPermanentRedirect, resp.StatusCode)
+		assert.Equal(t, "/api/v1/persons/"+target.ID.String(), resp.Header.Get("Location"))
+
+		resp, err = http.Get(server.URL + "/api/v1/persons/" + source.ID.String())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var followed dto.JSONPerson
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&followed))
+		assert.Equal(t, target.ID, followed.ID)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/v1/persons/" + target.ID.String() + "/merges")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var history dto.GetMergesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&history))
+		require.Len(t, history.Merges, 1)
+		assert.Equal(t, source.ID, history.Merges[0].Source.ID)
+		assert.Equal(t, "Anna Müller", history.Merges[0].Source.Name)
+		assert.Equal(t, "union", history.Merges[0].Strategies["hobbies"])
+	})
+
+	t.Run("merged source can not be merged again", func(t *testing.T) {
+		resp, _ := merge(target.ID.String(), `{"sourceId":"`+source.ID.String()+`"}`)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+// failingMerges fails to record merges, after the source was deleted and
+// the target updated.
+type failingMerges struct {
+	*repository.Repository
+}
+
+func (failingMerges) RecordMerge(ctx context.Context, m domain.Merge) error {
+	return errors.New("history unavailable")
+}
+
+func TestMergePersons_RollsBackOnFailure(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(failingMerges{repo})
+	ctx := context.Background()
+	target, _ := personSvc.AddPerson(ctx, domain.NewPerson("Target", 30, []string{"x"}))
+	source, _ := personSvc.AddPerson(ctx, domain.NewPerson("Source", 31, []string{"y"}))
+
+	_, err := personSvc.MergePersons(ctx, target.ID, source.ID, domain.MergeStrategies{"name": domain.KeepSource})
+	assert.Error(t, err)
+
+	got, err := personSvc.GetPerson(ctx, source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, source, got)
+	got, err = personSvc.GetPerson(ctx, target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Target", got.Name)
+}
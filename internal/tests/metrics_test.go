@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/metrics"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	repo := repository.NewRepository()
+	m.RegisterPersonsTotal(repo.Count)
+	personSvc := person.NewPersonSvc(metrics.NewRepository(repo, m))
+	custonmVal := customvalidator.NewCustomValidator(validator.New(), customvalidator.WithFailureObserver(m.ValidationFailed))
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithMetrics(m))
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	for _, payload := range []string{`{"name":"John","age":30,"hobbies":["Reading"]}`, `{"name":"","age":30,"hobbies":["Reading"]}`} {
+		resp, err := client.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(payload))
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	resp, err := client.Get(server.URL + "/metrics")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(body), `persons_api_http_requests_total{method="POST",route="/api/v1/persons",status="201"} 1`)
+	assert.Contains(t, string(body), `persons_api_http_requests_total{method="POST",route="/api/v1/persons",status="422"} 1`)
+	assert.Contains(t, string(body), `persons_api_validation_failures_total{field="name",rule="required"} 1`)
+	assert.Contains(t, string(body), `persons_api_repository_operation_duration_seconds_count{operation="AddPerson",outcome="success"} 1`)
+	assert.Contains(t, string(body), "persons_api_persons_total 1")
+}
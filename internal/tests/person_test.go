@@ -7,17 +7,22 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/lafetz/assessment/internal/core/domain"
 	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/events"
 	"github.com/lafetz/assessment/internal/repository"
 	"github.com/lafetz/assessment/internal/web"
 	"github.com/lafetz/assessment/internal/web/dto"
+	"github.com/lafetz/assessment/internal/web/idempotency"
 	customvalidator "github.com/lafetz/assessment/internal/web/validation"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddPerson(t *testing.T) {
@@ -250,3 +255,298 @@ func TestDeletePerson(t *testing.T) {
 		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
 	})
 }
+
+func TestAddPerson_IdempotencyKey(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithIdempotency(idempotency.New(time.Hour, idempotency.WithScope(web.RequestTenant))))
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	createAs := func(tenant, key, payload string) (*http.Response, dto.JSONPerson) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/persons", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.Header, key)
+		if tenant != "" {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var p dto.JSONPerson
+		_ = json.NewDecoder(resp.Body).Decode(&p)
+		return resp, p
+	}
+	create := func(key, payload string) (*http.Response, dto.JSONPerson) {
+		return createAs("", key, payload)
+	}
+
+	first, created := create("create-john", `{"name":"John","age":30,"hobbies":["Reading"]}`)
+	assert.Equal(t, http.StatusCreated, first.StatusCode)
+
+	retry, replayed := create("create-john", `{"name":"John","age":30,"hobbies":["Reading"]}`)
+	assert.Equal(t, http.StatusCreated, retry.StatusCode)
+	assert.Equal(t, "true", retry.Header.Get(idempotency.ReplayedHeader))
+	assert.Equal(t, created.ID, replayed.ID)
+	assert.Equal(t, 1, repo.Count())
+	assert.NotEmpty(t, retry.Header.Get("X-Request-ID"))
+	assert.NotEqual(t, first.Header.Get("X-Request-ID"), retry.Header.Get("X-Request-ID"))
+
+	other, _ := createAs("acme", "create-john", `{"name":"John","age":30,"hobbies":["Reading"]}`)
+	assert.Equal(t, http.StatusCreated, other.StatusCode)
+	assert.Empty(t, other.Header.Get(idempotency.ReplayedHeader))
+	assert.Equal(t, 2, repo.Count())
+
+	reused, _ := create("create-john", `{"name":"Jane","age":30,"hobbies":["Reading"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.StatusCode)
+	assert.Equal(t, 2, repo.Count())
+}
+
+func TestPersonProfile(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	send := func(method, path, payload string) (*http.Response, []byte) {
+		req, _ := http.NewRequest(method, server.URL+path, bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(resp.Body)
+		return resp, body.Bytes()
+	}
+	birthDate := time.Now().UTC().AddDate(-30, 0, -1).Format("2006-01-02")
+
+	resp, body := send(http.MethodPost, "/api/v1/persons", `{
+		"name": "Ada", "hobbies": ["Maths"], "email": "ada@example.com", "phone": "+442071234567",
+		"birthDate": "`+birthDate+`",
+		"address": {"street": "1 Main St", "city": "London", "postalCode": "SW1A 1AA", "country": "GB"}
+	}`)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.JSONPerson
+	assert.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, int32(30), created.Age)
+	assert.Equal(t, birthDate, created.BirthDate)
+	assert.Equal(t, "+442071234567", created.Phone)
+	assert.Equal(t, "GB", created.Address.Country)
+
+	t.Run("age still accepted without birth date", func(t *testing.T) {
+		resp, body := send(http.MethodPost, "/api/v1/persons", `{"name":"Old Client","age":42,"hobbies":["Chess"]}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `42`, string(mustField(t, body, "age")))
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		resp, _ := send(http.MethodPost, "/api/v1/persons", `{"name":"Ada Two","age":31,"hobbies":["Maths"],"email":"ADA@example.com"}`)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		resp, body := send(http.MethodPost, "/api/v1/persons", `{
+			"name": "Bad", "hobbies": ["x"], "email": "nope", "phone": "0207 123",
+			"birthDate": "2999-01-01", "address": {"street": "x", "city": "y", "postalCode": "z", "country": "XX"}
+		}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		var errs customvalidator.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(body, &errs))
+		assert.Equal(t, map[string]any{
+			"email":     "must be a valid email address",
+			"phone":     "must be an E.164 phone number, e.g. +14155552671",
+			"birthdate": "must be a past date formatted as YYYY-MM-DD",
+			"country":   "must be an ISO 3166-1 alpha-2 country code",
+		}, errs.Errors)
+	})
+
+	t.Run("age or birth date required", func(t *testing.T) {
+		resp, body := send(http.MethodPost, "/api/v1/persons", `{"name":"Ageless","hobbies":["x"]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Contains(t, string(body), "This field is required unless birthDate is set")
+	})
+
+	t.Run("patch email", func(t *testing.T) {
+		resp, body := send(http.MethodPatch, "/api/v1/persons/"+created.ID.String(), `{"email":"ada.l@example.com"}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `"ada.l@example.com"`, string(mustField(t, body, "email")))
+		assert.JSONEq(t, `30`, string(mustField(t, body, "age")))
+	})
+
+	t.Run("patch null removes optional fields", func(t *testing.T) {
+		resp, body := send(http.MethodPatch, "/api/v1/persons/"+created.ID.String(), `{"phone":null,"address":null,"birthDate":null}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Nil(t, mustField(t, body, "phone"))
+		assert.Nil(t, mustField(t, body, "address"))
+		assert.Nil(t, mustField(t, body, "birthDate"))
+		assert.JSONEq(t, `"ada.l@example.com"`, string(mustField(t, body, "email")))
+
+		resp, body = send(http.MethodPatch, "/api/v1/persons/"+created.ID.String(), `{"email":null}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Nil(t, mustField(t, body, "email"))
+		assert.JSONEq(t, `"Ada"`, string(mustField(t, body, "name")))
+
+		resp, _ = send(http.MethodPatch, "/api/v1/persons/"+created.ID.String(), `{"name":null}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("put keeps profile fields not sent", func(t *testing.T) {
+		path := "/api/v1/persons/" + created.ID.String()
+		resp, _ := send(http.MethodPatch, path, `{"email":"ada@example.com","phone":"+442071234567"}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, body := send(http.MethodPut, path, `{"name":"Ada King","age":36,"hobbies":["Maths"]}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `"Ada King"`, string(mustField(t, body, "name")))
+		assert.JSONEq(t, `"ada@example.com"`, string(mustField(t, body, "email")))
+		assert.JSONEq(t, `"+442071234567"`, string(mustField(t, body, "phone")))
+
+		resp, body = send(http.MethodPut, path, `{"name":"Ada King","age":36,"hobbies":["Maths"],"phone":null}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Nil(t, mustField(t, body, "phone"))
+		assert.JSONEq(t, `"ada@example.com"`, string(mustField(t, body, "email")))
+	})
+}
+
+func mustField(t *testing.T, body []byte, field string) json.RawMessage {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(body, &fields))
+	return fields[field]
+}
+
+func TestGetDuplicates(t *testing.T) {
+	repo := repository.NewRepository(repository.WithUniqueIndexes("email", "phone"))
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	ctx := context.Background()
+	anna, _ := personSvc.AddPerson(ctx, domain.NewPerson("Anna Müller", 34, []string{"Chess", "Running"}))
+	twin, _ := personSvc.AddPerson(ctx, domain.NewPerson("muller, anna", 34, []string{"running", "chess"}))
+	near, _ := personSvc.AddPerson(ctx, domain.NewPerson("Ana Muller", 35, []string{"Chess"}))
+	_, _ = personSvc.AddPerson(ctx, domain.NewPerson("Bob Stone", 60, []string{"Golf"}))
+
+	get := func(query string) (*http.Response, dto.GetDuplicatesResponse) {
+		resp, err := http.Get(server.URL + "/api/v1/persons/" + anna.ID.String() + "/duplicates" + query)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var body dto.GetDuplicatesResponse
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return resp, body
+	}
+
+	resp, body := get("")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	if assert.Len(t, body.Duplicates, 2) {
+		assert.Equal(t, twin.ID, body.Duplicates[0].Person.ID)
+		assert.Equal(t, 1.0, body.Duplicates[0].Score)
+		assert.Equal(t, near.ID, body.Duplicates[1].Person.ID)
+	}
+
+	_, body = get("?limit=1")
+	assert.Len(t, body.Duplicates, 1)
+
+	resp, _ = get("?minScore=2")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err := http.Get(server.URL + "/api/v1/persons/" + uuid.NewString() + "/duplicates")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	t.Run("unique phone", func(t *testing.T) {
+		payload := `{"name":"Caller","age":30,"hobbies":["x"],"phone":"+14155552671"}`
+		first, err := http.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(payload))
+		assert.NoError(t, err)
+		first.Body.Close()
+		assert.Equal(t, http.StatusCreated, first.StatusCode)
+
+		second, err := http.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(payload))
+		assert.NoError(t, err)
+		defer second.Body.Close()
+		assert.Equal(t, http.StatusConflict, second.StatusCode)
+		var msg map[string]any
+		_ = json.NewDecoder(second.Body).Decode(&msg)
+		assert.Equal(t, "phone is already in use", msg["message"])
+	})
+}
+
+func TestPatchPerson_ConcurrentPatchesAreNotLost(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	ctx := context.Background()
+	p, err := personSvc.AddPerson(ctx, domain.NewPerson("Ada", 0, []string{"Maths"}))
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := personSvc.PatchPerson(ctx, p.ID, func(p domain.Person) domain.Person {
+				p.Age++
+				return p
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	got, err := personSvc.GetPerson(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(50), got.Age)
+}
+
+// slowPublisher takes a while to publish creations.
+type slowPublisher struct{}
+
+func (slowPublisher) Publish(ctx context.Context, event domain.Event) {
+	if event.Type == domain.EventPersonCreated {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestWrites_PublishedInCommitOrder(t *testing.T) {
+	bus := events.NewBus(events.DefaultBufferSize)
+	defer bus.Close()
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo, person.WithPublisher(slowPublisher{}), person.WithPublisher(bus))
+	ctx := context.Background()
+	ada := domain.NewPerson("Ada", 36, []string{"Maths"})
+
+	// The patch commits while the creation is still being published.
+	patched := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := personSvc.GetPerson(ctx, ada.ID); err == nil {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		_, err := personSvc.PatchPerson(ctx, ada.ID, func(p domain.Person) domain.Person {
+			p.Age++
+			return p
+		})
+		patched <- err
+	}()
+	_, err := personSvc.AddPerson(ctx, ada)
+	require.NoError(t, err)
+	require.NoError(t, <-patched)
+
+	_, backlog, _ := bus.Subscribe(0, true)
+	require.Len(t, backlog, 2)
+	assert.Equal(t, domain.EventPersonCreated, backlog[0].Type)
+	assert.Equal(t, domain.EventPersonUpdated, backlog[1].Type)
+}
@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/photo"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhotos(t *testing.T) {
+	repo := repository.NewRepository()
+	store, err := photo.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	photos, err := photo.New(photo.Config{MaxBytes: 1 << 20}, store, repo, slog.Default())
+	require.NoError(t, err)
+	personSvc := person.NewPersonSvc(repo, person.WithPublisher(photos))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithPhotos(photos))
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var jpg bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpg, img, nil))
+
+	upload := func(path, field string, data []byte, v any) int {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, err := mw.CreateFormFile(field, "me.jpg")
+		require.NoError(t, err)
+		_, err = fw.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		req, err := http.NewRequest(http.MethodPut, server.URL+path, &body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if v != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		}
+		return resp.StatusCode
+	}
+	get := func(path string, header http.Header) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	var ann dto.JSONPerson
+	resp, err := http.Post(server.URL+"/api/v1/persons", "application/json",
+		bytes.NewBufferString(`{"name":"Ann","age":30,"hobbies":["Chess"]}`))
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&ann))
+	resp.Body.Close()
+	path := "/api/v1/persons/" + ann.ID.String() + "/photo"
+
+	assert.Equal(t, http.StatusNotFound, get(path, nil).StatusCode)
+	assert.Equal(t, http.StatusNotFound, upload("/api/v1/persons/"+uuid.NewString()+"/photo", "photo", jpg.Bytes(), nil))
+	assert.Equal(t, http.StatusBadRequest, upload(path, "file", jpg.Bytes(), nil))
+	assert.Equal(t, http.StatusUnsupportedMediaType, upload(path, "photo", []byte("<svg></svg>"), nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, upload(path, "photo", jpg.Bytes()[:100], nil))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload(path, "photo", make([]byte, 2<<20), nil))
+
+	req, err := http.NewRequest(http.MethodPut, server.URL+path, bytes.NewReader(jpg.Bytes()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "image/jpeg")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	var p dto.JSONPhoto
+	require.Equal(t, http.StatusOK, upload(path, "photo", jpg.Bytes(), &p))
+	assert.Equal(t, 640, p.Width)
+	assert.Equal(t, 480, p.Height)
+	assert.Equal(t, "image/jpeg", p.ContentType)
+	assert.Equal(t, path+"?size=small", p.URLs["small"])
+
+	resp = get(p.URLs["small"], nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+	assert.Equal(t, "private, max-age=300", resp.Header.Get("Cache-Control"))
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 64, cfg.Width)
+	assert.Equal(t, 48, cfg.Height)
+
+	assert.Equal(t, http.StatusNotModified, get(p.URLs["small"], http.Header{"If-None-Match": {etag}}).StatusCode)
+	assert.Equal(t, http.StatusOK, get(path, nil).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, get(path+"?size=huge", nil).StatusCode)
+
+	req, err = http.NewRequest(http.MethodDelete, server.URL+"/api/v1/persons/"+ann.ID.String(), nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Eventually(t, func() bool {
+		_, err := photos.Get(req.Context(), ann.ID, photo.SizeOriginal)
+		return err == photo.ErrNotFound
+	}, time.Second, 10*time.Millisecond)
+}
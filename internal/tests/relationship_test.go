@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationships(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	ctx := context.Background()
+	var ids []string
+	for _, name := range []string{"Ann", "Bob", "Cid", "Dee"} {
+		p, err := personSvc.AddPerson(ctx, domain.NewPerson(name, 30, []string{"Chess"}))
+		require.NoError(t, err)
+		ids = append(ids, p.ID.String())
+	}
+	ann, bob, cid, dee := ids[0], ids[1], ids[2], ids[3]
+	persons := server.URL + "/api/v1/persons/"
+
+	relate := func(from, to, relType string) int {
+		resp, err := http.Post(persons+from+"/relationships", "application/json",
+			bytes.NewBufferString(`{"toId":"`+to+`","type":"`+relType+`"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	get := func(path string, v any) int {
+		resp, err := http.Get(persons + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		}
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusCreated, relate(ann, bob, "friend"))
+	require.Equal(t, http.StatusCreated, relate(bob, cid, "friend"))
+	require.Equal(t, http.StatusCreated, relate(cid, dee, "manager"))
+	assert.Equal(t, http.StatusConflict, relate(ann, bob, "friend"))
+	assert.Equal(t, http.StatusUnprocessableEntity, relate(ann, ann, "friend"))
+	assert.Equal(t, http.StatusUnprocessableEntity, relate(ann, cid, "cousin"))
+	assert.Equal(t, http.StatusNotFound, relate(ann, "7c9e6679-7425-40de-944b-e07fc1f90ae7", "friend"))
+
+	t.Run("list", func(t *testing.T) {
+		var rels dto.GetRelationshipsResponse
+		require.Equal(t, http.StatusOK, get(bob+"/relationships", &rels))
+		assert.Len(t, rels.Relationships, 2)
+		require.Equal(t, http.StatusOK, get(bob+"/relationships?direction=out", &rels))
+		require.Len(t, rels.Relationships, 1)
+		assert.Equal(t, cid, rels.Relationships[0].ToID.String())
+		require.Equal(t, http.StatusOK, get(cid+"/relationships?type=manager", &rels))
+		assert.Len(t, rels.Relationships, 1)
+		assert.Equal(t, http.StatusBadRequest, get(cid+"/relationships?direction=up", &rels))
+	})
+
+	t.Run("shortest path", func(t *testing.T) {
+		var path dto.PathResponse
+		require.Equal(t, http.StatusOK, get(ann+"/path/"+dee, &path))
+		assert.Equal(t, 3, path.Length)
+		require.Len(t, path.Persons, 4)
+		assert.Equal(t, "Dee", path.Persons[3].Name)
+
+		assert.Equal(t, http.StatusOK, get(dee+"/path/"+ann, &path))
+		assert.Equal(t, http.StatusNotFound, get(dee+"/path/"+ann+"?directed=true", &path))
+		assert.Equal(t, http.StatusNotFound, get(ann+"/path/"+dee+"?types=friend", &path))
+		assert.Equal(t, http.StatusNotFound, get(ann+"/path/"+dee+"?maxDepth=2", &path))
+		assert.Equal(t, http.StatusBadRequest, get(ann+"/path/"+dee+"?maxDepth=11", &path))
+	})
+
+	t.Run("friends of friends", func(t *testing.T) {
+		var fof dto.FriendsOfFriendsResponse
+		require.Equal(t, http.StatusOK, get(ann+"/friends-of-friends", &fof))
+		require.Len(t, fof.Suggestions, 1)
+		assert.Equal(t, "Cid", fof.Suggestions[0].Person.Name)
+		assert.Equal(t, 1, fof.Suggestions[0].Mutual)
+	})
+
+	t.Run("merge moves relationships", func(t *testing.T) {
+		_, err := personSvc.MergePersons(ctx, uuid.MustParse(cid), uuid.MustParse(bob), nil)
+		require.NoError(t, err)
+		var rels dto.GetRelationshipsResponse
+		require.Equal(t, http.StatusOK, get(cid+"/relationships", &rels))
+		require.Len(t, rels.Relationships, 2, "the relationship between bob and cid is dropped")
+		assert.Equal(t, ann, rels.Relationships[0].FromID.String())
+		assert.Equal(t, "friend", rels.Relationships[0].Type)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, persons+ann+"/relationships/friend/"+cid, nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		require.NoError(t, personSvc.DeletePerson(ctx, uuid.MustParse(dee)))
+		var rels dto.GetRelationshipsResponse
+		require.Equal(t, http.StatusOK, get(cid+"/relationships", &rels))
+		assert.Empty(t, rels.Relationships)
+	})
+}
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func accessLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		if line["msg"] == "request completed" {
+			lines = append(lines, line)
+		}
+	}
+	buf.Reset()
+	return lines
+}
+
+func TestRequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, logger, personSvc, custonmVal,
+		web.WithAccessLogSampling(map[string]float64{"GET /api/v1/persons": 0}),
+	)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	t.Run("generates request id", func(t *testing.T) {
+		resp, err := client.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(`{"name":"John","age":30,"hobbies":["Reading"]}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		id := resp.Header.Get("X-Request-ID")
+		assert.NotEmpty(t, id)
+		lines := accessLogLines(t, &buf)
+		assert.Len(t, lines, 1)
+		assert.Equal(t, id, lines[0]["request_id"])
+		assert.Equal(t, "POST", lines[0]["method"])
+		assert.Equal(t, "/api/v1/persons", lines[0]["route"])
+		assert.Equal(t, "127.0.0.1", lines[0]["remote_ip"])
+		assert.Equal(t, float64(http.StatusCreated), lines[0]["status"])
+		assert.Greater(t, lines[0]["bytes"], float64(0))
+		assert.Contains(t, lines[0], "duration")
+	})
+
+	t.Run("propagates request id", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/persons/not-a-uuid", nil)
+		req.Header.Set("X-Request-ID", "abc-123")
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, "abc-123", resp.Header.Get("X-Request-ID"))
+		lines := accessLogLines(t, &buf)
+		assert.Len(t, lines, 1)
+		assert.Equal(t, "abc-123", lines[0]["request_id"])
+		assert.Equal(t, "/api/v1/persons/{personId}", lines[0]["route"])
+	})
+
+	t.Run("replaces invalid request id", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/persons/not-a-uuid", nil)
+		req.Header.Set("X-Request-ID", "has spaces")
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.NotEqual(t, "has spaces", resp.Header.Get("X-Request-ID"))
+		accessLogLines(t, &buf)
+	})
+
+	t.Run("sampled route skips successful requests", func(t *testing.T) {
+		resp, err := client.Get(server.URL + "/api/v1/persons")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("X-Request-ID"))
+		assert.Empty(t, accessLogLines(t, &buf))
+	})
+}
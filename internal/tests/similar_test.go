@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSimilar(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	ctx := context.Background()
+	add := func(name string, age int32, hobbies ...string) domain.Person {
+		p, err := personSvc.AddPerson(ctx, domain.NewPerson(name, age, hobbies))
+		require.NoError(t, err)
+		return p
+	}
+	me := add("Me", 30, "Chess", "Reading", "Falconry")
+	add("Reader", 30, "Reading")
+	add("Reader Two", 40, "Reading")
+	add("Falconer", 70, "Falconry")
+	add("Golfer", 30, "Golf")
+	moved := add("Moved", 30, "Chess")
+	moved.Hobbies = []string{"Golf"}
+	_, err := personSvc.UpdatePerson(ctx, moved)
+	require.NoError(t, err)
+
+	get := func(query string) (int, dto.GetSimilarResponse) {
+		resp, err := http.Get(server.URL + "/api/v1/persons/" + me.ID.String() + "/similar" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var out dto.GetSimilarResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		}
+		return resp.StatusCode, out
+	}
+
+	status, got := get("")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, got.Similar, 3)
+	assert.Equal(t, "Falconer", got.Similar[0].Person.Name)
+	assert.Equal(t, []string{"Falconry"}, got.Similar[0].SharedHobbies)
+	assert.Equal(t, "Reader", got.Similar[1].Person.Name, "same score, closer in age")
+	assert.Equal(t, "Reader Two", got.Similar[2].Person.Name)
+
+	_, got = get("?limit=1")
+	assert.Len(t, got.Similar, 1)
+	status, _ = get("?limit=0")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	resp, err := http.Get(server.URL + "/api/v1/persons/7c9e6679-7425-40de-944b-e07fc1f90ae7/similar")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
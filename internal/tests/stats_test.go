@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStats(t *testing.T) {
+	repo := repository.NewRepository()
+	personSvc := person.NewPersonSvc(repo)
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal)
+
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	ctx := context.Background()
+	_, err := personSvc.SetAttributeSchema(ctx, domain.AttributeSchema{AdditionalProperties: true})
+	require.NoError(t, err)
+	for i, p := range []domain.Person{
+		domain.NewPerson("A", 16, []string{"Chess", "Reading"}),
+		domain.NewPerson("B", 24, []string{"Chess", "Reading", "Golf"}),
+		domain.NewPerson("C", 41, []string{"Golf"}),
+		domain.NewPerson("D", 70, []string{"Reading"}),
+	} {
+		if i%2 == 1 {
+			p.Attributes = domain.Attributes{"team": "ops"}
+		}
+		_, err := personSvc.AddPerson(ctx, p)
+		require.NoError(t, err)
+	}
+
+	get := func(query string) (int, dto.StatsResponse) {
+		resp, err := http.Get(server.URL + "/api/v1/persons/stats" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var out dto.StatsResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		}
+		return resp.StatusCode, out
+	}
+
+	status, stats := get("?buckets=18,65&percentiles=50,99.5&top=2&pairs=1")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 37.75, stats.Ages.Mean)
+	assert.Equal(t, 32.5, stats.Ages.Median)
+	assert.Equal(t, map[string]float64{"p50": 24, "p99.5": 70}, stats.Ages.Percentiles)
+	assert.Equal(t, []dto.JSONAgeBucket{{Min: 0, Max: 18, Count: 1}, {Min: 18, Max: 65, Count: 2}, {Min: 65, Count: 1}}, stats.Ages.Histogram)
+	assert.Equal(t, []dto.JSONHobbyCount{{Hobby: "Reading", Count: 3}, {Hobby: "Chess", Count: 2}}, stats.TopHobbies)
+	assert.Equal(t, []dto.JSONHobbyPair{{Hobbies: [2]string{"Chess", "Reading"}, Count: 2}}, stats.HobbyPairs)
+
+	status, stats = get("")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, stats.Ages.Histogram, 7)
+	assert.Len(t, stats.Ages.Percentiles, 5)
+
+	status, stats = get("?attributes.team=ops&top=1")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, stats.Total)
+	assert.Equal(t, 47.0, stats.Ages.Mean)
+	assert.Equal(t, []dto.JSONHobbyCount{{Hobby: "Reading", Count: 2}}, stats.TopHobbies)
+
+	for _, query := range []string{"?buckets=30,20", "?buckets=x", "?percentiles=0", "?top=101"} {
+		status, _ = get(query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
+}
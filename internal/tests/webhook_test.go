@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/lafetz/assessment/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan delivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{r.Header, body}
+	}))
+	defer receiver.Close()
+
+	webhooks := webhook.New(webhook.Config{MinBackoff: time.Millisecond, AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}, slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go webhooks.Run(ctx)
+
+	personSvc := person.NewPersonSvc(repository.NewRepository(), person.WithPublisher(webhooks))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithWebhooks(webhooks))
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+
+	post := func(path, payload string) *http.Response {
+		resp, err := http.Post(server.URL+path, "application/json", bytes.NewBufferString(payload))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("internal address", func(t *testing.T) {
+		resp := post("/api/v1/webhooks", `{"url":"http://169.254.169.254/latest/meta-data"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("validation failure", func(t *testing.T) {
+		resp := post("/api/v1/webhooks", `{"url":"ftp://example.com","events":["person.renamed"]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		var body customvalidator.ValidationErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Contains(t, body.Errors, "url")
+		assert.Contains(t, body.Errors, "events[0]")
+	})
+
+	resp := post("/api/v1/webhooks", `{"url":"`+receiver.URL+`","events":["person.created"],"secret":"0123456789abcdef"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var hook dto.JSONWebhook
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&hook))
+	assert.Equal(t, "0123456789abcdef", hook.Secret)
+
+	resp = post("/api/v1/persons", `{"name":"John","age":30,"hobbies":["Reading"]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	select {
+	case d := <-received:
+		assert.Equal(t, "person.created", d.header.Get(webhook.EventHeader))
+		assert.NoError(t, webhook.Verify("0123456789abcdef", d.header.Get(webhook.SignatureHeader), d.body, time.Minute))
+		assert.Contains(t, string(d.body), `"name":"John"`)
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(server.URL + "/api/v1/webhooks/" + hook.ID.String() + "/deliveries")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var deliveries []dto.JSONDelivery
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&deliveries))
+		return len(deliveries) == 1 && deliveries[0].Status == "succeeded" && len(deliveries[0].Attempts) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	resp, err := http.Get(server.URL + "/api/v1/webhooks/" + hook.ID.String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var got dto.JSONWebhook
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Empty(t, got.Secret, "the secret is only returned on creation")
+}
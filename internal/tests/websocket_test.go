@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/events"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	"github.com/lafetz/assessment/internal/web/cors"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonUpdatesSocket(t *testing.T) {
+	bus := events.NewBus(events.DefaultBufferSize)
+	personSvc := person.NewPersonSvc(repository.NewRepository(), person.WithPublisher(bus))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+	policy, err := cors.New(cors.Config{AllowedOrigins: []string{"https://app.example.com"}})
+	require.NoError(t, err)
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithEvents(bus), web.WithCORS(policy))
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+
+	createPerson := func(name string, age int) dto.JSONPerson {
+		resp, err := http.Post(server.URL+"/api/v1/persons", "application/json", bytes.NewBufferString(fmt.Sprintf(`{"name":%q,"age":%d,"hobbies":["Reading"]}`, name, age)))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var p dto.JSONPerson
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+		return p
+	}
+	dial := func() *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	send := func(conn *websocket.Conn, msg dto.WSClientMessage) {
+		require.NoError(t, conn.WriteJSON(msg))
+	}
+	receive := func(conn *websocket.Conn) dto.WSServerMessage {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		var msg dto.WSServerMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+
+	t.Run("subscribe, receive and unsubscribe", func(t *testing.T) {
+		conn := dial()
+		send(conn, dto.WSClientMessage{Type: dto.WSSubscribe, ID: "adults", Filter: "type == person.created && age >= 18"})
+		assert.Equal(t, dto.WSServerMessage{Type: dto.WSSubscribed, ID: "adults"}, receive(conn))
+
+		createPerson("Kid", 10)
+		adult := createPerson("Adult", 40)
+		msg := receive(conn)
+		require.Equal(t, dto.WSEvent, msg.Type)
+		assert.Equal(t, []string{"adults"}, msg.Subscriptions)
+		assert.Equal(t, "Adult", msg.Event.Person.Name)
+
+		send(conn, dto.WSClientMessage{Type: dto.WSSubscribe, ID: "adult", PersonIDs: []uuid.UUID{adult.ID}})
+		assert.Equal(t, dto.WSSubscribed, receive(conn).Type)
+
+		req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/v1/persons/"+adult.ID.String(), nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		msg = receive(conn)
+		assert.Equal(t, []string{"adult"}, msg.Subscriptions)
+		assert.Equal(t, "person.deleted", msg.Event.Type)
+
+		send(conn, dto.WSClientMessage{Type: dto.WSUnsubscribe, ID: "adults"})
+		assert.Equal(t, dto.WSServerMessage{Type: dto.WSUnsubscribed, ID: "adults"}, receive(conn))
+		createPerson("Late", 50)
+		send(conn, dto.WSClientMessage{Type: dto.WSPing})
+		assert.Equal(t, dto.WSPong, receive(conn).Type, "no event after unsubscribing")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		conn := dial()
+		send(conn, dto.WSClientMessage{Type: dto.WSSubscribe, ID: "bad", Filter: "age >= old"})
+		msg := receive(conn)
+		assert.Equal(t, dto.WSError, msg.Type)
+		assert.Equal(t, "bad", msg.ID)
+
+		send(conn, dto.WSClientMessage{Type: dto.WSUnsubscribe, ID: "missing"})
+		assert.Equal(t, dto.WSError, receive(conn).Type)
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+		assert.Equal(t, dto.WSError, receive(conn).Type)
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {"https://evil.com"}})
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {"https://app.example.com"}})
+		require.NoError(t, err)
+		conn.Close()
+	})
+
+	t.Run("closed on shutdown", func(t *testing.T) {
+		conn := dial()
+		bus.Close()
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		_, _, err := conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+	})
+}
+
+func TestPersonUpdatesSocket_AnyOriginCORS(t *testing.T) {
+	bus := events.NewBus(events.DefaultBufferSize)
+	defer bus.Close()
+	personSvc := person.NewPersonSvc(repository.NewRepository(), person.WithPublisher(bus))
+	custonmVal := customvalidator.NewCustomValidator(validator.New())
+	// The default policy allows any origin for CORS, but not for WebSockets.
+	web := web.NewApp(8080, slog.Default(), personSvc, custonmVal, web.WithEvents(bus))
+	server := httptest.NewServer(web.Router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {"https://evil.com"}})
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {server.URL}})
+	require.NoError(t, err)
+	conn.Close()
+}
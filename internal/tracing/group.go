@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GroupSvc creates a span for every call to the wrapped service.
+type GroupSvc struct {
+	person.GroupSvcApi
+}
+
+func NewGroupSvc(next person.GroupSvcApi) *GroupSvc {
+	return &GroupSvc{
+		GroupSvcApi: next,
+	}
+}
+
+func (s *GroupSvc) AddGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	ctx, span := start(ctx, "GroupSvc.AddGroup", attribute.String("group.id", g.ID.String()))
+	g, err := s.GroupSvcApi.AddGroup(ctx, g)
+	end(span, err)
+	return g, err
+}
+
+func (s *GroupSvc) GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	ctx, span := start(ctx, "GroupSvc.GetGroup", attribute.String("group.id", id.String()))
+	g, err := s.GroupSvcApi.GetGroup(ctx, id)
+	end(span, err)
+	return g, err
+}
+
+func (s *GroupSvc) GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error) {
+	ctx, span := start(ctx, "GroupSvc.GetGroups", attribute.Int("page", int(page)), attribute.Int("size", int(size)))
+	groups, meta, err := s.GroupSvcApi.GetGroups(ctx, page, size)
+	end(span, err)
+	return groups, meta, err
+}
+
+func (s *GroupSvc) UpdateGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	ctx, span := start(ctx, "GroupSvc.UpdateGroup", attribute.String("group.id", g.ID.String()))
+	g, err := s.GroupSvcApi.UpdateGroup(ctx, g)
+	end(span, err)
+	return g, err
+}
+
+func (s *GroupSvc) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	ctx, span := start(ctx, "GroupSvc.DeleteGroup", attribute.String("group.id", id.String()))
+	err := s.GroupSvcApi.DeleteGroup(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *GroupSvc) AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	ctx, span := start(ctx, "GroupSvc.AddMember", attribute.String("group.id", groupID.String()), attribute.String("person.id", personID.String()), attribute.String("group.role", string(role)))
+	m, err := s.GroupSvcApi.AddMember(ctx, groupID, personID, role)
+	end(span, err)
+	return m, err
+}
+
+func (s *GroupSvc) UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	ctx, span := start(ctx, "GroupSvc.UpdateMember", attribute.String("group.id", groupID.String()), attribute.String("person.id", personID.String()), attribute.String("group.role", string(role)))
+	m, err := s.GroupSvcApi.UpdateMember(ctx, groupID, personID, role)
+	end(span, err)
+	return m, err
+}
+
+func (s *GroupSvc) RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error {
+	ctx, span := start(ctx, "GroupSvc.RemoveMember", attribute.String("group.id", groupID.String()), attribute.String("person.id", personID.String()))
+	err := s.GroupSvcApi.RemoveMember(ctx, groupID, personID)
+	end(span, err)
+	return err
+}
+
+func (s *GroupSvc) GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	ctx, span := start(ctx, "GroupSvc.GetMembers", attribute.String("group.id", groupID.String()), attribute.Int("page", int(page)), attribute.Int("size", int(size)))
+	members, meta, err := s.GroupSvcApi.GetMembers(ctx, groupID, page, size)
+	end(span, err)
+	return members, meta, err
+}
+
+func (s *GroupSvc) GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	ctx, span := start(ctx, "GroupSvc.GetPersonGroups", attribute.String("person.id", personID.String()), attribute.Int("page", int(page)), attribute.Int("size", int(size)))
+	groups, meta, err := s.GroupSvcApi.GetPersonGroups(ctx, personID, page, size)
+	end(span, err)
+	return groups, meta, err
+}
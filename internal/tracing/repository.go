@@ -0,0 +1,281 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/outbox"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Repository creates a span for every call to the wrapped repository.
+type Repository struct {
+	next person.Repository
+}
+
+var _ person.Repository = (*Repository)(nil)
+
+func NewRepository(next person.Repository) *Repository {
+	return &Repository{
+		next: next,
+	}
+}
+
+func (r *Repository) AddPerson(ctx context.Context, p domain.Person) (domain.Person, error) {
+	ctx, span := start(ctx, "Repository.AddPerson", attribute.String("person.id", p.ID.String()))
+	p, err := r.next.AddPerson(ctx, p)
+	end(span, err)
+	return p, err
+}
+
+func (r *Repository) GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error) {
+	ctx, span := start(ctx, "Repository.GetPerson", attribute.String("person.id", id.String()))
+	p, err := r.next.GetPerson(ctx, id)
+	end(span, err)
+	return p, err
+}
+
+func (r *Repository) GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error) {
+	ctx, span := start(ctx, "Repository.GetPersons", attribute.Int("page", int(page)), attribute.Int("size", int(size)), attribute.Int("filter.attributes", len(filter.Attributes)))
+	persons, meta, err := r.next.GetPersons(ctx, filter, page, size)
+	end(span, err)
+	return persons, meta, err
+}
+
+func (r *Repository) DeletePerson(ctx context.Context, id uuid.UUID) error {
+	ctx, span := start(ctx, "Repository.DeletePerson", attribute.String("person.id", id.String()))
+	err := r.next.DeletePerson(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *Repository) UpdatePerson(ctx context.Context, p domain.Person) (domain.Person, error) {
+	ctx, span := start(ctx, "Repository.UpdatePerson", attribute.String("person.id", p.ID.String()))
+	p, err := r.next.UpdatePerson(ctx, p)
+	end(span, err)
+	return p, err
+}
+
+func (r *Repository) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, span := start(ctx, "Repository.InTx")
+	err := r.next.InTx(ctx, fn)
+	end(span, err)
+	return err
+}
+
+func (r *Repository) RecordMerge(ctx context.Context, m domain.Merge) error {
+	ctx, span := start(ctx, "Repository.RecordMerge", attribute.String("person.id", m.TargetID.String()))
+	err := r.next.RecordMerge(ctx, m)
+	end(span, err)
+	return err
+}
+
+func (r *Repository) GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error) {
+	ctx, span := start(ctx, "Repository.GetMerges", attribute.String("person.id", targetID.String()))
+	merges, err := r.next.GetMerges(ctx, targetID)
+	end(span, err)
+	return merges, err
+}
+
+func (r *Repository) AddRelationship(ctx context.Context, rel domain.Relationship) error {
+	ctx, span := start(ctx, "Repository.AddRelationship", attribute.String("relationship.from", rel.FromID.String()), attribute.String("relationship.to", rel.ToID.String()))
+	err := r.next.AddRelationship(ctx, rel)
+	end(span, err)
+	return err
+}
+
+func (r *Repository) DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error {
+	ctx, span := start(ctx, "Repository.DeleteRelationship", attribute.String("relationship.from", from.String()), attribute.String("relationship.to", to.String()))
+	err := r.next.DeleteRelationship(ctx, from, to, t)
+	end(span, err)
+	return err
+}
+
+func (r *Repository) GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error) {
+	ctx, span := start(ctx, "Repository.GetRelationships", attribute.String("person.id", id.String()))
+	rels, err := r.next.GetRelationships(ctx, id)
+	end(span, err)
+	return rels, err
+}
+
+func (r *Repository) ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error) {
+	ctx, span := start(ctx, "Repository.ShortestPath", attribute.String("path.from", from.String()), attribute.String("path.to", to.String()))
+	path, err := r.next.ShortestPath(ctx, from, to, q)
+	end(span, err)
+	return path, err
+}
+
+func (r *Repository) FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error) {
+	ctx, span := start(ctx, "Repository.FriendsOfFriends", attribute.String("person.id", id.String()))
+	suggestions, err := r.next.FriendsOfFriends(ctx, id)
+	end(span, err)
+	return suggestions, err
+}
+
+func (r *Repository) MoveMemberships(ctx context.Context, from, to uuid.UUID) error {
+	ctx, span := start(ctx, "Repository.MoveMemberships", attribute.String("person.from", from.String()), attribute.String("person.to", to.String()))
+	err := r.next.MoveMemberships(ctx, from, to)
+	end(span, err)
+	return err
+}
+
+func (r *Repository) HobbyCounts(ctx context.Context) (map[string]int, error) {
+	ctx, span := start(ctx, "Repository.HobbyCounts")
+	counts, err := r.next.HobbyCounts(ctx)
+	end(span, err)
+	return counts, err
+}
+
+func (r *Repository) Aggregates(ctx context.Context, filter domain.PersonFilter) (domain.Aggregates, error) {
+	ctx, span := start(ctx, "Repository.Aggregates", attribute.Int("filter.attributes", len(filter.Attributes)))
+	aggregates, err := r.next.Aggregates(ctx, filter)
+	end(span, err)
+	return aggregates, err
+}
+
+func (r *Repository) PersonsSharingHobbies(ctx context.Context, hobbies []string) ([]domain.Person, error) {
+	ctx, span := start(ctx, "Repository.PersonsSharingHobbies", attribute.Int("hobbies", len(hobbies)))
+	persons, err := r.next.PersonsSharingHobbies(ctx, hobbies)
+	end(span, err)
+	return persons, err
+}
+
+func (r *Repository) GetAttributeSchema(ctx context.Context, tenant string) (domain.AttributeSchema, error) {
+	ctx, span := start(ctx, "Repository.GetAttributeSchema", attribute.String("tenant", tenant))
+	schema, err := r.next.GetAttributeSchema(ctx, tenant)
+	end(span, err)
+	return schema, err
+}
+
+func (r *Repository) PutAttributeSchema(ctx context.Context, tenant string, schema domain.AttributeSchema) error {
+	ctx, span := start(ctx, "Repository.PutAttributeSchema", attribute.String("tenant", tenant))
+	err := r.next.PutAttributeSchema(ctx, tenant, schema)
+	end(span, err)
+	return err
+}
+
+// GroupRepository creates a span for every call to the wrapped group
+// repository.
+type GroupRepository struct {
+	next person.GroupRepository
+}
+
+var _ person.GroupRepository = (*GroupRepository)(nil)
+
+func NewGroupRepository(next person.GroupRepository) *GroupRepository {
+	return &GroupRepository{
+		next: next,
+	}
+}
+
+func (r *GroupRepository) AddGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	ctx, span := start(ctx, "GroupRepository.AddGroup", attribute.String("group.id", g.ID.String()))
+	g, err := r.next.AddGroup(ctx, g)
+	end(span, err)
+	return g, err
+}
+
+func (r *GroupRepository) GetGroup(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	ctx, span := start(ctx, "GroupRepository.GetGroup", attribute.String("group.id", id.String()))
+	g, err := r.next.GetGroup(ctx, id)
+	end(span, err)
+	return g, err
+}
+
+func (r *GroupRepository) GetGroups(ctx context.Context, page, size int32) ([]domain.Group, domain.Metadata, error) {
+	ctx, span := start(ctx, "GroupRepository.GetGroups", attribute.Int("page", int(page)), attribute.Int("size", int(size)))
+	groups, meta, err := r.next.GetGroups(ctx, page, size)
+	end(span, err)
+	return groups, meta, err
+}
+
+func (r *GroupRepository) UpdateGroup(ctx context.Context, g domain.Group) (domain.Group, error) {
+	ctx, span := start(ctx, "GroupRepository.UpdateGroup", attribute.String("group.id", g.ID.String()))
+	g, err := r.next.UpdateGroup(ctx, g)
+	end(span, err)
+	return g, err
+}
+
+func (r *GroupRepository) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	ctx, span := start(ctx, "GroupRepository.DeleteGroup", attribute.String("group.id", id.String()))
+	err := r.next.DeleteGroup(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *GroupRepository) AddMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole, joinedAt time.Time) (domain.Membership, error) {
+	ctx, span := start(ctx, "GroupRepository.AddMember", attribute.String("group.id", groupID.String()), attribute.String("person.id", personID.String()))
+	membership, err := r.next.AddMember(ctx, groupID, personID, role, joinedAt)
+	end(span, err)
+	return membership, err
+}
+
+func (r *GroupRepository) UpdateMember(ctx context.Context, groupID, personID uuid.UUID, role domain.GroupRole) (domain.Membership, error) {
+	ctx, span := start(ctx, "GroupRepository.UpdateMember", attribute.String("group.id", groupID.String()), attribute.String("person.id", personID.String()))
+	membership, err := r.next.UpdateMember(ctx, groupID, personID, role)
+	end(span, err)
+	return membership, err
+}
+
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, personID uuid.UUID) error {
+	ctx, span := start(ctx, "GroupRepository.RemoveMember", attribute.String("group.id", groupID.String()), attribute.String("person.id", personID.String()))
+	err := r.next.RemoveMember(ctx, groupID, personID)
+	end(span, err)
+	return err
+}
+
+func (r *GroupRepository) GetMembers(ctx context.Context, groupID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	ctx, span := start(ctx, "GroupRepository.GetMembers", attribute.String("group.id", groupID.String()), attribute.Int("page", int(page)), attribute.Int("size", int(size)))
+	members, meta, err := r.next.GetMembers(ctx, groupID, page, size)
+	end(span, err)
+	return members, meta, err
+}
+
+func (r *GroupRepository) GetPersonGroups(ctx context.Context, personID uuid.UUID, page, size int32) ([]domain.Membership, domain.Metadata, error) {
+	ctx, span := start(ctx, "GroupRepository.GetPersonGroups", attribute.String("person.id", personID.String()), attribute.Int("page", int(page)), attribute.Int("size", int(size)))
+	groups, meta, err := r.next.GetPersonGroups(ctx, personID, page, size)
+	end(span, err)
+	return groups, meta, err
+}
+
+// OutboxStore is the outbox as appended to by the service and read by the
+// relay.
+type OutboxStore interface {
+	person.Outbox
+	outbox.Store
+}
+
+// Outbox creates a span for every call to the wrapped outbox.
+type Outbox struct {
+	next OutboxStore
+}
+
+func NewOutbox(next OutboxStore) *Outbox {
+	return &Outbox{
+		next: next,
+	}
+}
+
+func (r *Outbox) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	ctx, span := start(ctx, "Outbox.AppendEvents", attribute.Int("events", len(events)))
+	err := r.next.AppendEvents(ctx, events...)
+	end(span, err)
+	return err
+}
+
+func (r *Outbox) OutboxAfter(ctx context.Context, after uint64, limit int) ([]outbox.Record, error) {
+	ctx, span := start(ctx, "Outbox.OutboxAfter", attribute.Int("limit", limit))
+	records, err := r.next.OutboxAfter(ctx, after, limit)
+	end(span, err)
+	return records, err
+}
+
+func (r *Outbox) PruneOutbox(ctx context.Context, seq uint64) error {
+	ctx, span := start(ctx, "Outbox.PruneOutbox")
+	err := r.next.PruneOutbox(ctx, seq)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,154 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PersonSvc creates a span for every call to the wrapped service.
+type PersonSvc struct {
+	person.PersonSvcApi
+}
+
+func NewPersonSvc(next person.PersonSvcApi) *PersonSvc {
+	return &PersonSvc{
+		PersonSvcApi: next,
+	}
+}
+
+func (s *PersonSvc) AddPerson(ctx context.Context, p domain.Person) (domain.Person, error) {
+	ctx, span := start(ctx, "PersonSvc.AddPerson", attribute.String("person.id", p.ID.String()))
+	p, err := s.PersonSvcApi.AddPerson(ctx, p)
+	end(span, err)
+	return p, err
+}
+
+func (s *PersonSvc) GetPerson(ctx context.Context, id uuid.UUID) (domain.Person, error) {
+	ctx, span := start(ctx, "PersonSvc.GetPerson", attribute.String("person.id", id.String()))
+	p, err := s.PersonSvcApi.GetPerson(ctx, id)
+	end(span, err)
+	return p, err
+}
+
+func (s *PersonSvc) GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error) {
+	ctx, span := start(ctx, "PersonSvc.GetPersons", attribute.Int("page", int(page)), attribute.Int("size", int(size)), attribute.Int("filter.attributes", len(filter.Attributes)))
+	persons, meta, err := s.PersonSvcApi.GetPersons(ctx, filter, page, size)
+	end(span, err)
+	return persons, meta, err
+}
+
+func (s *PersonSvc) DeletePerson(ctx context.Context, id uuid.UUID) error {
+	ctx, span := start(ctx, "PersonSvc.DeletePerson", attribute.String("person.id", id.String()))
+	err := s.PersonSvcApi.DeletePerson(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *PersonSvc) UpdatePerson(ctx context.Context, p domain.Person) (domain.Person, error) {
+	ctx, span := start(ctx, "PersonSvc.UpdatePerson", attribute.String("person.id", p.ID.String()))
+	p, err := s.PersonSvcApi.UpdatePerson(ctx, p)
+	end(span, err)
+	return p, err
+}
+
+func (s *PersonSvc) PatchPerson(ctx context.Context, id uuid.UUID, patch func(domain.Person) domain.Person) (domain.Person, error) {
+	ctx, span := start(ctx, "PersonSvc.PatchPerson", attribute.String("person.id", id.String()))
+	p, err := s.PersonSvcApi.PatchPerson(ctx, id, patch)
+	end(span, err)
+	return p, err
+}
+
+func (s *PersonSvc) FindDuplicates(ctx context.Context, id uuid.UUID, minScore float64, limit int) ([]domain.DuplicateCandidate, error) {
+	ctx, span := start(ctx, "PersonSvc.FindDuplicates", attribute.String("person.id", id.String()))
+	candidates, err := s.PersonSvcApi.FindDuplicates(ctx, id, minScore, limit)
+	end(span, err)
+	return candidates, err
+}
+
+func (s *PersonSvc) MergePersons(ctx context.Context, targetID, sourceID uuid.UUID, strategies domain.MergeStrategies) (domain.Person, error) {
+	ctx, span := start(ctx, "PersonSvc.MergePersons", attribute.String("person.id", targetID.String()), attribute.String("person.source_id", sourceID.String()))
+	p, err := s.PersonSvcApi.MergePersons(ctx, targetID, sourceID, strategies)
+	end(span, err)
+	return p, err
+}
+
+func (s *PersonSvc) GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error) {
+	ctx, span := start(ctx, "PersonSvc.GetMerges", attribute.String("person.id", targetID.String()))
+	merges, err := s.PersonSvcApi.GetMerges(ctx, targetID)
+	end(span, err)
+	return merges, err
+}
+
+func (s *PersonSvc) AddRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) (domain.Relationship, error) {
+	ctx, span := start(ctx, "PersonSvc.AddRelationship", attribute.String("person.id", from.String()), attribute.String("person.other_id", to.String()), attribute.String("relationship.type", string(t)))
+	r, err := s.PersonSvcApi.AddRelationship(ctx, from, to, t)
+	end(span, err)
+	return r, err
+}
+
+func (s *PersonSvc) DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error {
+	ctx, span := start(ctx, "PersonSvc.DeleteRelationship", attribute.String("person.id", from.String()), attribute.String("person.other_id", to.String()), attribute.String("relationship.type", string(t)))
+	err := s.PersonSvcApi.DeleteRelationship(ctx, from, to, t)
+	end(span, err)
+	return err
+}
+
+func (s *PersonSvc) GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error) {
+	ctx, span := start(ctx, "PersonSvc.GetRelationships", attribute.String("person.id", id.String()))
+	rels, err := s.PersonSvcApi.GetRelationships(ctx, id)
+	end(span, err)
+	return rels, err
+}
+
+func (s *PersonSvc) ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error) {
+	ctx, span := start(ctx, "PersonSvc.ShortestPath", attribute.String("person.id", from.String()), attribute.String("person.other_id", to.String()), attribute.Int("path.max_depth", q.MaxDepth))
+	p, err := s.PersonSvcApi.ShortestPath(ctx, from, to, q)
+	end(span, err)
+	return p, err
+}
+
+func (s *PersonSvc) FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error) {
+	ctx, span := start(ctx, "PersonSvc.FriendsOfFriends", attribute.String("person.id", id.String()))
+	suggestions, err := s.PersonSvcApi.FriendsOfFriends(ctx, id)
+	end(span, err)
+	return suggestions, err
+}
+
+func (s *PersonSvc) SuggestHobbies(ctx context.Context, prefix string, limit int) ([]domain.HobbySuggestion, error) {
+	ctx, span := start(ctx, "PersonSvc.SuggestHobbies", attribute.String("hobby.prefix", prefix), attribute.Int("limit", limit))
+	suggestions, err := s.PersonSvcApi.SuggestHobbies(ctx, prefix, limit)
+	end(span, err)
+	return suggestions, err
+}
+
+func (s *PersonSvc) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	ctx, span := start(ctx, "PersonSvc.Stats")
+	stats, err := s.PersonSvcApi.Stats(ctx, q)
+	end(span, err)
+	return stats, err
+}
+
+func (s *PersonSvc) SimilarPersons(ctx context.Context, id uuid.UUID, limit int) ([]domain.SimilarPerson, error) {
+	ctx, span := start(ctx, "PersonSvc.SimilarPersons", attribute.String("person.id", id.String()), attribute.Int("limit", limit))
+	similar, err := s.PersonSvcApi.SimilarPersons(ctx, id, limit)
+	end(span, err)
+	return similar, err
+}
+
+func (s *PersonSvc) GetAttributeSchema(ctx context.Context) (domain.AttributeSchema, error) {
+	ctx, span := start(ctx, "PersonSvc.GetAttributeSchema", attribute.String("tenant", person.TenantFromContext(ctx)))
+	schema, err := s.PersonSvcApi.GetAttributeSchema(ctx)
+	end(span, err)
+	return schema, err
+}
+
+func (s *PersonSvc) SetAttributeSchema(ctx context.Context, schema domain.AttributeSchema) (domain.AttributeSchema, error) {
+	ctx, span := start(ctx, "PersonSvc.SetAttributeSchema", attribute.String("tenant", person.TenantFromContext(ctx)))
+	schema, err := s.PersonSvcApi.SetAttributeSchema(ctx, schema)
+	end(span, err)
+	return schema, err
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/repository"
+	"github.com/lafetz/assessment/internal/web"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+	for i, s := range spans {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+func TestPersonSvc_ChildSpans(t *testing.T) {
+	recorder := setupRecorder(t)
+	svc := NewPersonSvc(person.NewPersonSvc(NewRepository(repository.NewRepository())))
+
+	_, err := svc.GetPerson(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, person.ErrNotFound)
+
+	spans := recorder.Ended()
+	assert.Equal(t, []string{"Repository.GetPerson", "PersonSvc.GetPerson"}, spanNames(spans))
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestPersonSvc_TransactionSpans(t *testing.T) {
+	recorder := setupRecorder(t)
+	svc := NewPersonSvc(person.NewPersonSvc(NewRepository(repository.NewRepository())))
+
+	_, err := svc.AddPerson(context.Background(), domain.NewPerson("John", 30, []string{"Reading"}))
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Equal(t, []string{"Repository.GetAttributeSchema", "Repository.AddPerson", "Repository.InTx", "PersonSvc.AddPerson"}, spanNames(spans))
+	assert.Equal(t, spans[2].SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Equal(t, spans[3].SpanContext().SpanID(), spans[2].Parent().SpanID())
+}
+
+func TestGroupSvc_ChildSpans(t *testing.T) {
+	recorder := setupRecorder(t)
+	svc := NewGroupSvc(person.NewGroupSvc(NewGroupRepository(repository.NewRepository())))
+
+	_, err := svc.AddGroup(context.Background(), domain.NewGroup("Platform", ""))
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Equal(t, []string{"GroupRepository.AddGroup", "GroupSvc.AddGroup"}, spanNames(spans))
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestApp_PropagatesTraceparent(t *testing.T) {
+	recorder := setupRecorder(t)
+	repo := repository.NewRepository()
+	p, err := repo.AddPerson(context.Background(), domain.NewPerson("John", 30, []string{"Reading"}))
+	assert.NoError(t, err)
+	svc := NewPersonSvc(person.NewPersonSvc(NewRepository(repo)))
+	app := web.NewApp(8080, slog.Default(), svc, customvalidator.NewCustomValidator(validator.New()))
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/persons/"+p.ID.String(), nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	spans := recorder.Ended()
+	assert.Equal(t, []string{"Repository.GetPerson", "PersonSvc.GetPerson", "GET /api/v1/persons/{personId}"}, spanNames(spans))
+	for _, s := range spans {
+		assert.Equal(t, traceID, s.SpanContext().TraceID().String())
+	}
+	assert.Equal(t, "00f067aa0ba902b7", spans[2].Parent().SpanID().String())
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.ErrorIs(t, err, ErrUnknownExporter)
+}
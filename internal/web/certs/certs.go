@@ -0,0 +1,157 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const DefaultReloadInterval = 30 * time.Second
+
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+var (
+	ErrNoClientCAs       = errors.New("no certificates found in client CA file")
+	ErrUnknownClientAuth = errors.New("unknown client auth mode")
+)
+
+// Reloader serves a certificate/key pair from disk and picks up rotated files
+// without a restart.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	logger   *slog.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	certTime time.Time
+	keyTime  time.Time
+}
+
+func NewReloader(certFile, keyFile string, logger *slog.Logger) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// Reload reads the pair again when either file changed since the last load
+// and reports whether the certificate was replaced. A pair that fails to
+// load leaves the current certificate in place.
+func (r *Reloader) Reload() (bool, error) {
+	certTime, err := modTime(r.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyTime, err := modTime(r.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := r.cert != nil && certTime.Equal(r.certTime) && keyTime.Equal(r.keyTime)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("loading certificate: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.certTime = certTime
+	r.keyTime = keyTime
+	r.mu.Unlock()
+	return true, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Run checks the files for changes every interval until ctx is cancelled.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				r.logger.Error("failed to reload TLS certificate", "error", err)
+				continue
+			}
+			if reloaded {
+				r.logger.Info("reloaded TLS certificate", "cert", r.certFile)
+			}
+		}
+	}
+}
+
+type Config struct {
+	ClientCAFile string
+	// ClientAuth is one of ClientAuthNone, ClientAuthOptional or
+	// ClientAuthRequire. Client certificates are verified against
+	// ClientCAFile.
+	ClientAuth string
+}
+
+// ServerConfig builds the TLS configuration for the server, serving the
+// certificate held by r and optionally authenticating clients.
+func ServerConfig(cfg Config, r *Reloader) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+	switch cfg.ClientAuth {
+	case ClientAuthNone, "":
+		return tlsConfig, nil
+	case ClientAuthOptional:
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownClientAuth, cfg.ClientAuth)
+	}
+
+	pem, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ErrNoClientCAs
+	}
+	tlsConfig.ClientCAs = pool
+	return tlsConfig, nil
+}
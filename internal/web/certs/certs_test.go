@@ -0,0 +1,171 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newKeyPair(t *testing.T, name string, parent *keyPair, isCA bool) *keyPair {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &keyPair{cert: cert, key: key, der: der}
+}
+
+func (kp *keyPair) certPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: kp.der})
+}
+
+func (kp *keyPair) keyPEM(t *testing.T) []byte {
+	der, err := x509.MarshalECPrivateKey(kp.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (kp *keyPair) write(t *testing.T, dir string, modTime time.Time) (string, string) {
+	t.Helper()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, kp.certPEM(), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, kp.keyPEM(t), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	return certFile, keyFile
+}
+
+func (kp *keyPair) tlsCertificate(t *testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair(kp.certPEM(), kp.keyPEM(t))
+	require.NoError(t, err)
+	return cert
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	first := newKeyPair(t, "first", nil, false)
+	certFile, keyFile := first.write(t, dir, time.Now().Add(-time.Minute))
+
+	r, err := NewReloader(certFile, keyFile, slog.Default())
+	require.NoError(t, err)
+	cert, _ := r.GetCertificate(nil)
+	assert.Equal(t, first.der, cert.Certificate[0])
+
+	reloaded, err := r.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded, "unchanged files are not reloaded")
+
+	second := newKeyPair(t, "second", nil, false)
+	second.write(t, dir, time.Now())
+	reloaded, err = r.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, second.der, cert.Certificate[0])
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+	_, err = r.Reload()
+	assert.Error(t, err)
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, second.der, cert.Certificate[0], "a broken pair keeps the current certificate")
+}
+
+func TestServerConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newKeyPair(t, "ca", nil, true)
+	server := newKeyPair(t, "server", ca, false)
+	client := newKeyPair(t, "client", ca, false)
+	certFile, keyFile := server.write(t, dir, time.Now())
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM(), 0o600))
+
+	r, err := NewReloader(certFile, keyFile, slog.Default())
+	require.NoError(t, err)
+	tlsConfig, err := ServerConfig(Config{ClientAuth: ClientAuthRequire, ClientCAFile: caFile}, r)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = tlsConfig
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+	// httptest installs its own certificate, which the server only prefers
+	// over GetCertificate when the client sends no SNI.
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certs, ServerName: "localhost"},
+			ForceAttemptHTTP2: true,
+		}}
+	}
+
+	resp, err := newClient(client.tlsCertificate(t)).Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor, "HTTP/2 is negotiated over TLS")
+
+	_, err = newClient().Get(srv.URL)
+	assert.Error(t, err, "clients without a certificate are rejected")
+}
+
+func TestServerConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := newKeyPair(t, "server", nil, false).write(t, dir, time.Now())
+	r, err := NewReloader(certFile, keyFile, slog.Default())
+	require.NoError(t, err)
+
+	_, err = ServerConfig(Config{ClientAuth: "sometimes"}, r)
+	assert.ErrorIs(t, err, ErrUnknownClientAuth)
+
+	emptyCA := filepath.Join(dir, "empty.crt")
+	require.NoError(t, os.WriteFile(emptyCA, nil, 0o600))
+	_, err = ServerConfig(Config{ClientAuth: ClientAuthOptional, ClientCAFile: emptyCA}, r)
+	assert.ErrorIs(t, err, ErrNoClientCAs)
+}
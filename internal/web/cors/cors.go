@@ -0,0 +1,292 @@
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrWildcardWithCredentials = errors.New("cors: wildcard origin \"*\" can not be combined with credentials")
+	ErrInvalidOrigin           = errors.New("cors: invalid origin pattern")
+	// ErrWildcardWebSocketOrigin is returned for "*" among the WebSocket
+	// origins, which must be listed explicitly.
+	ErrWildcardWebSocketOrigin = errors.New("cors: wildcard origin \"*\" is not allowed for WebSockets")
+)
+
+var (
+	DefaultAllowedHeaders = []string{"Accept", "Authorization", "Cache-Control", "Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "X-Requested-With", "X-CSRF-Token", "X-Request-ID", "X-Tenant-ID"}
+	DefaultExposedHeaders = []string{"ETag", "Idempotent-Replayed", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Request-ID"}
+)
+
+const DefaultMaxAge = time.Hour
+
+// Config describes the cross-origin policy. AllowedOrigins accepts exact
+// origins ("https://app.example.com"), wildcard subdomain patterns
+// ("https://*.example.com") or "*" to allow any origin.
+//
+// WebSocketOrigins are the origins allowed to open WebSockets, which the
+// browser does not subject to CORS. They take the same patterns except
+// "*". When empty, the explicit AllowedOrigins apply, and "*" among them
+// allows no WebSocket origin.
+type Config struct {
+	AllowedOrigins   []string
+	WebSocketOrigins []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+type wildcardOrigin struct {
+	scheme string
+	suffix string
+	port   string
+}
+
+type origins struct {
+	any       bool
+	exact     map[string]struct{}
+	wildcards []wildcardOrigin
+}
+
+// Policy applies a Config to requests. The methods allowed on each path are
+// collected from the registered routes through AllowMethod.
+type Policy struct {
+	mu               sync.RWMutex
+	origins          origins
+	websocket        origins
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+	methods          map[string][]string
+}
+
+func New(cfg Config) (*Policy, error) {
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = DefaultAllowedHeaders
+	}
+	if cfg.ExposedHeaders == nil {
+		cfg.ExposedHeaders = DefaultExposedHeaders
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
+	p := &Policy{
+		allowedHeaders:   strings.Join(cfg.AllowedHeaders, ", "),
+		exposedHeaders:   strings.Join(cfg.ExposedHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+		maxAge:           strconv.Itoa(int(cfg.MaxAge.Seconds())),
+		methods:          make(map[string][]string),
+	}
+	if err := p.SetOrigins(cfg.AllowedOrigins, cfg.WebSocketOrigins); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetAllowedOrigins replaces the allowed origins of a running policy.
+func (p *Policy) SetAllowedOrigins(allowed []string) error {
+	o, err := parseOrigins(allowed)
+	if err != nil {
+		return err
+	}
+	if o.any && p.allowCredentials {
+		return ErrWildcardWithCredentials
+	}
+	p.mu.Lock()
+	p.origins = o
+	p.mu.Unlock()
+	return nil
+}
+
+// SetOrigins replaces both the allowed and the WebSocket origins of a
+// running policy. Either both lists are installed or, on error, neither.
+func (p *Policy) SetOrigins(allowed, websocket []string) error {
+	o, err := parseOrigins(allowed)
+	if err != nil {
+		return err
+	}
+	if o.any && p.allowCredentials {
+		return ErrWildcardWithCredentials
+	}
+	ws, err := parseWebSocketOrigins(websocket)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.origins = o
+	p.websocket = ws
+	p.mu.Unlock()
+	return nil
+}
+
+func parseWebSocketOrigins(allowed []string) (origins, error) {
+	o, err := parseOrigins(allowed)
+	if err != nil {
+		return origins{}, err
+	}
+	if o.any {
+		return origins{}, ErrWildcardWebSocketOrigin
+	}
+	return o, nil
+}
+
+func parseOrigins(allowed []string) (origins, error) {
+	o := origins{exact: make(map[string]struct{})}
+	for _, raw := range allowed {
+		origin := strings.ToLower(strings.TrimSpace(raw))
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			o.any = true
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+			return origins{}, fmt.Errorf("%w: %q", ErrInvalidOrigin, raw)
+		}
+		host := u.Hostname()
+		if strings.Contains(host, "*") {
+			if !strings.HasPrefix(host, "*.") || strings.Count(host, "*") != 1 || len(host) < 3 {
+				return origins{}, fmt.Errorf("%w: %q", ErrInvalidOrigin, raw)
+			}
+			o.wildcards = append(o.wildcards, wildcardOrigin{scheme: u.Scheme, suffix: host[1:], port: u.Port()})
+			continue
+		}
+		o.exact[origin] = struct{}{}
+	}
+	return o, nil
+}
+
+func (o origins) empty() bool {
+	return !o.any && len(o.exact) == 0 && len(o.wildcards) == 0
+}
+
+func (o origins) allows(origin string) bool {
+	if o.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := o.exact[origin]; ok {
+		return true
+	}
+	if len(o.wildcards) == 0 {
+		return false
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	for _, w := range o.wildcards {
+		if u.Scheme == w.scheme && u.Port() == w.port && strings.HasSuffix(host, w.suffix) && len(host) > len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOrigin reports whether requests from origin are allowed.
+func (p *Policy) AllowsOrigin(origin string) bool {
+	p.mu.RLock()
+	o := p.origins
+	p.mu.RUnlock()
+	return o.allows(origin)
+}
+
+// AllowsWebSocketOrigin reports whether WebSockets may be opened from
+// origin. It never allows an origin only because of "*".
+func (p *Policy) AllowsWebSocketOrigin(origin string) bool {
+	p.mu.RLock()
+	o := p.websocket
+	if o.empty() {
+		o = p.origins
+		o.any = false
+	}
+	p.mu.RUnlock()
+	return o.allows(origin)
+}
+
+// AllowMethod records that method is served on the given path pattern so that
+// preflight requests for the path advertise it.
+func (p *Policy) AllowMethod(path, method string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !slices.Contains(p.methods[path], method) {
+		p.methods[path] = append(p.methods[path], method)
+	}
+}
+
+// AllowedMethods returns the methods registered for path.
+func (p *Policy) AllowedMethods(path string) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.methods[path])
+}
+
+// setOriginHeaders writes the origin related headers and reports whether the
+// origin is allowed.
+func (p *Policy) setOriginHeaders(w http.ResponseWriter, r *http.Request) bool {
+	p.mu.RLock()
+	o := p.origins
+	p.mu.RUnlock()
+
+	h := w.Header()
+	if !o.any || p.allowCredentials {
+		h.Add("Vary", "Origin")
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" || !o.allows(origin) {
+		return false
+	}
+	if o.any && !p.allowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.allowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+// Handler applies the policy to actual (non-preflight) requests.
+func (p *Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.setOriginHeaders(w, r) && p.exposedHeaders != "" {
+			w.Header().Set("Access-Control-Expose-Headers", p.exposedHeaders)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Preflight answers OPTIONS requests for path using the methods registered
+// through AllowMethod.
+func (p *Policy) Preflight(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		methods := p.AllowedMethods(path)
+		h.Set("Allow", strings.Join(append(methods, http.MethodOptions), ", "))
+
+		requested := r.Header.Get("Access-Control-Request-Method")
+		if !p.setOriginHeaders(w, r) || requested == "" || !slices.Contains(methods, requested) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		h.Set("Access-Control-Allow-Headers", p.allowedHeaders)
+		h.Set("Access-Control-Max-Age", p.maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
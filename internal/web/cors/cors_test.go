@@ -0,0 +1,185 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{"exact origin", Config{AllowedOrigins: []string{"https://app.example.com"}}, nil},
+		{"wildcard subdomain", Config{AllowedOrigins: []string{"https://*.example.com"}}, nil},
+		{"any origin", Config{AllowedOrigins: []string{"*"}}, nil},
+		{"any origin with credentials", Config{AllowedOrigins: []string{"*"}, AllowCredentials: true}, ErrWildcardWithCredentials},
+		{"missing scheme", Config{AllowedOrigins: []string{"app.example.com"}}, ErrInvalidOrigin},
+		{"path in origin", Config{AllowedOrigins: []string{"https://app.example.com/api"}}, ErrInvalidOrigin},
+		{"misplaced wildcard", Config{AllowedOrigins: []string{"https://app.*.com"}}, ErrInvalidOrigin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	policy, err := New(Config{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+	})
+	assert.NoError(t, err)
+	handler := policy.Handler(okHandler())
+
+	tests := []struct {
+		name          string
+		origin        string
+		expectedAllow string
+	}{
+		{"exact match", "https://app.example.com", "https://app.example.com"},
+		{"wildcard match", "https://eu.example.org", "https://eu.example.org"},
+		{"nested wildcard match", "https://a.b.example.org", "https://a.b.example.org"},
+		{"wildcard does not match apex", "https://example.org", ""},
+		{"scheme mismatch", "http://app.example.com", ""},
+		{"unknown origin", "https://evil.com", ""},
+		{"suffix trick", "https://evilexample.org", ""},
+		{"no origin", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedAllow, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Contains(t, w.Header().Values("Vary"), "Origin")
+			if tt.expectedAllow != "" {
+				assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+				assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "ETag")
+			} else {
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+			}
+		})
+	}
+}
+
+func TestHandler_AnyOrigin(t *testing.T) {
+	policy, err := New(Config{AllowedOrigins: []string{"*"}})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Origin", "https://anything.example.com")
+	w := httptest.NewRecorder()
+	policy.Handler(okHandler()).ServeHTTP(w, req)
+
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Values("Vary"))
+}
+
+func TestPreflight(t *testing.T) {
+	policy, err := New(Config{AllowedOrigins: []string{"https://app.example.com"}})
+	assert.NoError(t, err)
+	policy.AllowMethod("/api/v1/persons/{personId}", http.MethodGet)
+	policy.AllowMethod("/api/v1/persons/{personId}", http.MethodPut)
+	policy.AllowMethod("/api/v1/persons/{personId}", http.MethodGet)
+	preflight := policy.Preflight("/api/v1/persons/{personId}")
+
+	tests := []struct {
+		name            string
+		origin          string
+		method          string
+		expectedMethods string
+	}{
+		{"registered method", "https://app.example.com", http.MethodPut, "GET, PUT"},
+		{"unregistered method", "https://app.example.com", http.MethodPost, ""},
+		{"disallowed origin", "https://evil.com", http.MethodPut, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/v1/persons/1", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			w := httptest.NewRecorder()
+			preflight.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Equal(t, tt.expectedMethods, w.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "GET, PUT, OPTIONS", w.Header().Get("Allow"))
+			assert.ElementsMatch(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, w.Header().Values("Vary"))
+			if tt.expectedMethods != "" {
+				assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+				assert.NotEmpty(t, w.Header().Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+}
+
+func TestSetAllowedOrigins(t *testing.T) {
+	policy, err := New(Config{AllowedOrigins: []string{"https://old.example.com"}, AllowCredentials: true})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, policy.SetAllowedOrigins([]string{"*"}), ErrWildcardWithCredentials)
+	assert.NoError(t, policy.SetAllowedOrigins([]string{"https://new.example.com"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://old.example.com")
+	w := httptest.NewRecorder()
+	policy.Handler(okHandler()).ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestAllowsWebSocketOrigin(t *testing.T) {
+	policy, err := New(Config{AllowedOrigins: []string{"*"}})
+	assert.NoError(t, err)
+	assert.True(t, policy.AllowsOrigin("https://evil.com"))
+	assert.False(t, policy.AllowsWebSocketOrigin("https://evil.com"))
+
+	policy, err = New(Config{AllowedOrigins: []string{"*", "https://*.example.com"}})
+	assert.NoError(t, err)
+	assert.True(t, policy.AllowsWebSocketOrigin("https://app.example.com"))
+	assert.False(t, policy.AllowsWebSocketOrigin("https://evil.com"))
+
+	policy, err = New(Config{AllowedOrigins: []string{"https://app.example.com"}, WebSocketOrigins: []string{"https://ws.example.com"}})
+	assert.NoError(t, err)
+	assert.True(t, policy.AllowsWebSocketOrigin("https://ws.example.com"))
+	assert.False(t, policy.AllowsWebSocketOrigin("https://app.example.com"))
+
+	_, err = New(Config{WebSocketOrigins: []string{"*"}})
+	assert.ErrorIs(t, err, ErrWildcardWebSocketOrigin)
+	assert.NoError(t, policy.SetOrigins([]string{"https://app.example.com"}, nil))
+	assert.True(t, policy.AllowsWebSocketOrigin("https://app.example.com"))
+}
+
+func TestSetOrigins_InvalidWebSocketOriginsChangeNothing(t *testing.T) {
+	policy, err := New(Config{AllowedOrigins: []string{"https://old.example.com"}, WebSocketOrigins: []string{"https://ws.example.com"}})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, policy.SetOrigins([]string{"https://new.example.com"}, []string{"*"}), ErrWildcardWebSocketOrigin)
+	assert.True(t, policy.AllowsOrigin("https://old.example.com"))
+	assert.False(t, policy.AllowsOrigin("https://new.example.com"))
+	assert.True(t, policy.AllowsWebSocketOrigin("https://ws.example.com"))
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// AttributeSchema describes the attributes of persons. Its keywords are
+// named after their JSON Schema counterparts, but it is its own, smaller
+// format: only the keywords listed here are accepted.
+type AttributeSchema struct {
+	Title       string                           `json:"title,omitempty"`
+	Description string                           `json:"description,omitempty"`
+	Type        string                           `json:"type" validate:"required,eq=object" example:"object"`
+	Properties  map[string]JSONAttributeProperty `json:"properties" validate:"dive"`
+	Required    []string                         `json:"required,omitempty"`
+	// AdditionalProperties defaults to true.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+}
+
+type JSONAttributeProperty struct {
+	Type        string   `json:"type" validate:"required,oneof=string integer number boolean" example:"string"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Enum        []any    `json:"enum,omitempty" swaggertype:"array,string"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	MinLength   *int     `json:"minLength,omitempty" validate:"omitnil,gte=0"`
+	MaxLength   *int     `json:"maxLength,omitempty" validate:"omitnil,gte=0"`
+	Pattern     string   `json:"pattern,omitempty"`
+}
+
+// ToSchema returns the schema to compile. It must be validated first.
+func (in AttributeSchema) ToSchema() domain.AttributeSchema {
+	s := domain.AttributeSchema{
+		Title:                in.Title,
+		Description:          in.Description,
+		Properties:           make(map[string]domain.AttributeProperty, len(in.Properties)),
+		Required:             in.Required,
+		AdditionalProperties: in.AdditionalProperties == nil || *in.AdditionalProperties,
+	}
+	for name, p := range in.Properties {
+		s.Properties[name] = domain.AttributeProperty{
+			Type:        domain.AttributeType(p.Type),
+			Title:       p.Title,
+			Description: p.Description,
+			Enum:        p.Enum,
+			Minimum:     p.Minimum,
+			Maximum:     p.Maximum,
+			MinLength:   p.MinLength,
+			MaxLength:   p.MaxLength,
+			Pattern:     p.Pattern,
+		}
+	}
+	return s
+}
+
+func ConvertToAttributeSchema(s domain.AttributeSchema) AttributeSchema {
+	additional := s.AdditionalProperties
+	out := AttributeSchema{
+		Title:                s.Title,
+		Description:          s.Description,
+		Type:                 "object",
+		Properties:           make(map[string]JSONAttributeProperty, len(s.Properties)),
+		Required:             s.Required,
+		AdditionalProperties: &additional,
+	}
+	for name, p := range s.Properties {
+		out.Properties[name] = JSONAttributeProperty{
+			Type:        string(p.Type),
+			Title:       p.Title,
+			Description: p.Description,
+			Enum:        p.Enum,
+			Minimum:     p.Minimum,
+			Maximum:     p.Maximum,
+			MinLength:   p.MinLength,
+			MaxLength:   p.MaxLength,
+			Pattern:     p.Pattern,
+		}
+	}
+	return out
+}
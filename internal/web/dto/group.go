@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// CreateGroup is also the body of group updates.
+type CreateGroup struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description,omitempty" validate:"max=1000"`
+}
+
+func (in CreateGroup) ToGroup() domain.Group {
+	return domain.NewGroup(in.Name, in.Description)
+}
+
+type AddMember struct {
+	PersonID uuid.UUID `json:"personId" validate:"required"`
+	// Role defaults to member.
+	Role string `json:"role,omitempty" validate:"omitempty,oneof=owner lead member" example:"member"`
+}
+
+func (in AddMember) DomainRole() domain.GroupRole {
+	if in.Role == "" {
+		return domain.RoleMember
+	}
+	return domain.GroupRole(in.Role)
+}
+
+type UpdateMember struct {
+	Role string `json:"role" validate:"required,oneof=owner lead member" example:"lead"`
+}
+
+type JSONGroup struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func ConvertToJSONGroup(g domain.Group) JSONGroup {
+	return JSONGroup{ID: g.ID, Name: g.Name, Description: g.Description, CreatedAt: g.CreatedAt}
+}
+
+type GetGroupsResponse struct {
+	Meta   JSONMetadata `json:"meta"`
+	Groups []JSONGroup  `json:"groups"`
+}
+
+func ConvertToGetGroupsResponse(groups []domain.Group, meta domain.Metadata) GetGroupsResponse {
+	out := make([]JSONGroup, len(groups))
+	for i, g := range groups {
+		out[i] = ConvertToJSONGroup(g)
+	}
+	return GetGroupsResponse{Meta: ConvertToJSONMetadata(meta), Groups: out}
+}
+
+// JSONMember is a person in a group.
+type JSONMember struct {
+	Person   JSONPerson `json:"person"`
+	Role     string     `json:"role"`
+	JoinedAt time.Time  `json:"joinedAt"`
+}
+
+func ConvertToJSONMember(m domain.Membership) JSONMember {
+	return JSONMember{Person: ConvertToJSONPerson(m.Person), Role: string(m.Role), JoinedAt: m.JoinedAt}
+}
+
+type GetMembersResponse struct {
+	Meta    JSONMetadata `json:"meta"`
+	Members []JSONMember `json:"members"`
+}
+
+func ConvertToGetMembersResponse(members []domain.Membership, meta domain.Metadata) GetMembersResponse {
+	out := make([]JSONMember, len(members))
+	for i, m := range members {
+		out[i] = ConvertToJSONMember(m)
+	}
+	return GetMembersResponse{Meta: ConvertToJSONMetadata(meta), Members: out}
+}
+
+// JSONPersonGroup is a group a person belongs to.
+type JSONPersonGroup struct {
+	Group    JSONGroup `json:"group"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type GetPersonGroupsResponse struct {
+	Meta   JSONMetadata      `json:"meta"`
+	Groups []JSONPersonGroup `json:"groups"`
+}
+
+func ConvertToGetPersonGroupsResponse(groups []domain.Membership, meta domain.Metadata) GetPersonGroupsResponse {
+	out := make([]JSONPersonGroup, len(groups))
+	for i, m := range groups {
+		out[i] = JSONPersonGroup{Group: ConvertToJSONGroup(m.Group), Role: string(m.Role), JoinedAt: m.JoinedAt}
+	}
+	return GetPersonGroupsResponse{Meta: ConvertToJSONMetadata(meta), Groups: out}
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/photo"
+)
+
+type JSONPhoto struct {
+	PersonID    uuid.UUID `json:"personId"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	ContentType string    `json:"contentType" example:"image/jpeg"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	// URLs maps the sizes of the photo to the paths serving them.
+	URLs map[string]string `json:"urls"`
+}
+
+func ConvertToJSONPhoto(p photo.Photo, sizes []photo.Size) JSONPhoto {
+	path := "/api/v1/persons/" + p.PersonID.String() + "/photo?size="
+	urls := make(map[string]string, len(sizes))
+	for _, size := range sizes {
+		urls[string(size)] = path + string(size)
+	}
+	return JSONPhoto{
+		PersonID:    p.PersonID,
+		Width:       p.Width,
+		Height:      p.Height,
+		ContentType: p.ContentType,
+		UpdatedAt:   p.UpdatedAt,
+		URLs:        urls,
+	}
+}
@@ -1,12 +1,264 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+// CreatePerson takes either age or birthDate; when birthDate is sent the
+// age is derived from it.
 type CreatePerson struct {
-	Name    string   `json:"name" validate:"required"`
-	Age     int32    `json:"age" validate:"required,gte=0,lte=120"`
-	Hobbies []string `json:"hobbies" validate:"required,dive,required"`
+	Name      string       `json:"name" validate:"required"`
+	Age       int32        `json:"age" validate:"required_without=BirthDate,gte=0,lte=120"`
+	Hobbies   []string     `json:"hobbies" validate:"required,dive,required"`
+	Email     string       `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone     string       `json:"phone,omitempty" validate:"omitempty,e164"`
+	BirthDate string       `json:"birthDate,omitempty" validate:"omitempty,birthdate" example:"1990-04-21"`
+	Address   *JSONAddress `json:"address,omitempty" validate:"omitnil"`
+	// Attributes are checked against the tenant's attribute schema.
+	Attributes map[string]any `json:"attributes,omitempty" swaggertype:"object"`
 }
+
+// UpdatePerson replaces a person. Profile fields that are not sent keep
+// their value, so that clients predating them do not wipe them; null
+// removes one.
 type UpdatePerson struct {
-	Name    string   `json:"name" validate:"required"`
-	Age     int32    `json:"age" validate:"required,gte=0,lte=120"`
-	Hobbies []string `json:"hobbies" validate:"required,dive,required"`
+	Name      string       `json:"name" validate:"required"`
+	Age       int32        `json:"age" validate:"required_without=BirthDate,gte=0,lte=120"`
+	Hobbies   []string     `json:"hobbies" validate:"required,dive,required"`
+	Email     string       `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone     string       `json:"phone,omitempty" validate:"omitempty,e164"`
+	BirthDate string       `json:"birthDate,omitempty" validate:"omitempty,birthdate" example:"1990-04-21"`
+	Address   *JSONAddress `json:"address,omitempty" validate:"omitnil"`
+	// Attributes are checked against the tenant's attribute schema.
+	Attributes map[string]any `json:"attributes,omitempty" swaggertype:"object"`
+
+	// sent lists the fields present in the body.
+	sent map[string]bool
+}
+
+func (in *UpdatePerson) UnmarshalJSON(data []byte) error {
+	type plain UpdatePerson
+	if err := json.Unmarshal(data, (*plain)(in)); err != nil {
+		return err
+	}
+	fields, err := jsonFields(data)
+	if err != nil {
+		return err
+	}
+	in.sent = make(map[string]bool, len(fields))
+	for name := range fields {
+		in.sent[name] = true
+	}
+	return nil
+}
+
+// jsonFields returns the raw members of a JSON object.
+func jsonFields(data []byte) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+type JSONAddress struct {
+	Street     string `json:"street" validate:"required"`
+	City       string `json:"city" validate:"required"`
+	PostalCode string `json:"postalCode" validate:"required"`
+	Region     string `json:"region,omitempty"`
+	Country    string `json:"country" validate:"required,iso3166_1_alpha2" example:"DE"`
+}
+
+func (a *JSONAddress) toDomain() *domain.Address {
+	if a == nil {
+		return nil
+	}
+	return &domain.Address{
+		Street:     a.Street,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Region:     a.Region,
+		Country:    a.Country,
+	}
+}
+
+// ToPerson returns a new person with the given fields. It must be
+// validated first.
+func (in CreatePerson) ToPerson() domain.Person {
+	p := domain.NewPerson(in.Name, in.Age, in.Hobbies)
+	p.Attributes = in.Attributes
+	return withProfile(p, in.Email, in.Phone, in.BirthDate, in.Address)
+}
+
+// Apply returns current replaced by the fields, keeping the profile
+// fields that were not sent. It must be validated first.
+func (in UpdatePerson) Apply(current domain.Person) domain.Person {
+	p := domain.NewPerson(in.Name, in.Age, in.Hobbies)
+	p.ID = current.ID
+	p.Attributes = in.Attributes
+	p = withProfile(p, in.Email, in.Phone, in.BirthDate, in.Address)
+	if !in.sent["email"] {
+		p.Email = current.Email
+	}
+	if !in.sent["phone"] {
+		p.Phone = current.Phone
+	}
+	if !in.sent["birthDate"] {
+		p.BirthDate = current.BirthDate
+	}
+	if !in.sent["address"] {
+		p.Address = current.Address
+	}
+	if !in.sent["attributes"] {
+		p.Attributes = current.Attributes
+	}
+	return p
+}
+
+func withProfile(p domain.Person, email, phone, birthDate string, address *JSONAddress) domain.Person {
+	p.Email = strings.TrimSpace(email)
+	p.Phone = phone
+	p.BirthDate = parseDate(birthDate)
+	p.Address = address.toDomain()
+	return p
+}
+
+func parseDate(s string) time.Time {
+	d, _ := time.Parse(domain.DateLayout, s)
+	return d
+}
+
+// PatchPerson is a JSON merge patch: only the fields present are changed,
+// and null removes the optional ones.
+type PatchPerson struct {
+	Name      *string      `json:"name,omitempty" validate:"omitnil,min=1"`
+	Age       *int32       `json:"age,omitempty" validate:"omitnil,gte=0,lte=120"`
+	Hobbies   *[]string    `json:"hobbies,omitempty" validate:"omitnil,min=1,dive,required"`
+	Email     *string      `json:"email,omitempty" validate:"omitnil,email,max=254"`
+	Phone     *string      `json:"phone,omitempty" validate:"omitnil,e164"`
+	BirthDate *string      `json:"birthDate,omitempty" validate:"omitnil,birthdate" example:"1990-04-21"`
+	Address   *JSONAddress `json:"address,omitempty" validate:"omitnil"`
+	// Attributes are checked against the tenant's attribute schema.
+	Attributes map[string]any `json:"attributes,omitempty" swaggertype:"object"`
+
+	// removed lists the fields sent as null.
+	removed map[string]bool
+}
+
+// requiredFields can not be removed by a patch.
+var requiredFields = []string{"name", "age", "hobbies"}
+
+// UnmarshalJSON records the fields sent as null, which plain pointers can
+// not tell apart from missing ones.
+func (in *PatchPerson) UnmarshalJSON(data []byte) error {
+	type plain PatchPerson
+	if err := json.Unmarshal(data, (*plain)(in)); err != nil {
+		return err
+	}
+	fields, err := jsonFields(data)
+	if err != nil {
+		return err
+	}
+	in.removed = nil
+	for name, v := range fields {
+		if string(bytes.TrimSpace(v)) != "null" {
+			continue
+		}
+		if slices.Contains(requiredFields, name) {
+			return fmt.Errorf("%s can not be null", name)
+		}
+		if in.removed == nil {
+			in.removed = make(map[string]bool)
+		}
+		in.removed[name] = true
+	}
+	return nil
+}
+
+// Apply returns p with the patched fields replaced. A patched age is
+// ignored when the person has a birth date. Attributes are merged: null
+// removes one, other values replace it.
+func (in PatchPerson) Apply(p domain.Person) domain.Person {
+	if in.removed["email"] {
+		p.Email = ""
+	}
+	if in.removed["phone"] {
+		p.Phone = ""
+	}
+	if in.removed["birthDate"] {
+		p.BirthDate = time.Time{}
+	}
+	if in.removed["address"] {
+		p.Address = nil
+	}
+	if in.removed["attributes"] {
+		p.Attributes = nil
+	}
+	if in.Name != nil {
+		p.Name = *in.Name
+	}
+	if in.Age != nil {
+		p.Age = *in.Age
+	}
+	if in.Hobbies != nil {
+		p.Hobbies = *in.Hobbies
+	}
+	if in.Email != nil {
+		p.Email = strings.TrimSpace(*in.Email)
+	}
+	if in.Phone != nil {
+		p.Phone = *in.Phone
+	}
+	if in.BirthDate != nil {
+		p.BirthDate = parseDate(*in.BirthDate)
+	}
+	if in.Address != nil {
+		p.Address = in.Address.toDomain()
+	}
+	if in.Attributes != nil {
+		attrs := maps.Clone(p.Attributes)
+		if attrs == nil {
+			attrs = make(domain.Attributes, len(in.Attributes))
+		}
+		for name, v := range in.Attributes {
+			if v == nil {
+				delete(attrs, name)
+			} else {
+				attrs[name] = v
+			}
+		}
+		p.Attributes = attrs
+	}
+	return p
+}
+
+// MergePerson folds the person SourceID into the target. Strategies maps
+// fields to keepTarget (the default), keepSource or, for hobbies, union.
+type MergePerson struct {
+	SourceID   uuid.UUID         `json:"sourceId" validate:"required"`
+	Strategies map[string]string `json:"strategies" example:"name:keepTarget,hobbies:union"`
+}
+
+func (in MergePerson) DomainStrategies() domain.MergeStrategies {
+	strategies := make(domain.MergeStrategies, len(in.Strategies))
+	for field, strategy := range in.Strategies {
+		strategies[field] = domain.MergeStrategy(strategy)
+	}
+	return strategies
+}
+
+// CreateRelationship relates the person in the path to ToID: the former
+// is the Type of the latter, e.g. its parent or manager.
+type CreateRelationship struct {
+	ToID uuid.UUID `json:"toId" validate:"required"`
+	Type string    `json:"type" validate:"required,oneof=friend spouse parent manager" example:"friend"`
 }
@@ -1,24 +1,49 @@
 package dto
 
 import (
+	"math"
+	"strconv"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/lafetz/assessment/internal/core/domain"
 )
 
 type JSONPerson struct {
-	ID      uuid.UUID `json:"id"`
-	Name    string    `json:"name"`
-	Age     int32     `json:"age"`
-	Hobbies []string  `json:"hobbies"`
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Age        int32          `json:"age"`
+	Hobbies    []string       `json:"hobbies"`
+	Email      string         `json:"email,omitempty"`
+	Phone      string         `json:"phone,omitempty"`
+	BirthDate  string         `json:"birthDate,omitempty" example:"1990-04-21"`
+	Address    *JSONAddress   `json:"address,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty" swaggertype:"object"`
 }
 
 func ConvertToJSONPerson(p domain.Person) JSONPerson {
-	return JSONPerson{
-		ID:      p.ID,
-		Name:    p.Name,
-		Age:     p.Age,
-		Hobbies: p.Hobbies,
+	jp := JSONPerson{
+		ID:         p.ID,
+		Name:       p.Name,
+		Age:        p.Age,
+		Hobbies:    p.Hobbies,
+		Email:      p.Email,
+		Phone:      p.Phone,
+		Attributes: p.Attributes,
+	}
+	if !p.BirthDate.IsZero() {
+		jp.BirthDate = p.BirthDate.Format(domain.DateLayout)
+	}
+	if p.Address != nil {
+		jp.Address = &JSONAddress{
+			Street:     p.Address.Street,
+			City:       p.Address.City,
+			PostalCode: p.Address.PostalCode,
+			Region:     p.Address.Region,
+			Country:    p.Address.Country,
+		}
 	}
+	return jp
 }
 func ConvertToJSONPersonArray(persons []domain.Person) []JSONPerson {
 	jsonPersons := make([]JSONPerson, len(persons))
@@ -57,3 +82,256 @@ func ConvertToGetPersonsResponse(persons []domain.Person, meta domain.Metadata)
 		Persons: ConvertToJSONPersonArray(persons),
 	}
 }
+
+// JSONPersonEvent is a person change. Person is absent for deletions.
+type JSONPersonEvent struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	PersonID   uuid.UUID   `json:"personId"`
+	Person     *JSONPerson `json:"person,omitempty"`
+}
+
+func ConvertToJSONPersonEvent(e domain.Event) JSONPersonEvent {
+	event := JSONPersonEvent{
+		ID:         e.ID,
+		Type:       string(e.Type),
+		OccurredAt: e.OccurredAt,
+		PersonID:   e.Person.ID,
+	}
+	if e.Type != domain.EventPersonDeleted {
+		p := ConvertToJSONPerson(e.Person)
+		event.Person = &p
+	}
+	return event
+}
+
+type JSONDuplicateScores struct {
+	Name    float64 `json:"name"`
+	Age     float64 `json:"age"`
+	Hobbies float64 `json:"hobbies"`
+}
+
+// JSONDuplicate is a person that may be a duplicate, scored from 0 to 1.
+type JSONDuplicate struct {
+	Person JSONPerson          `json:"person"`
+	Score  float64             `json:"score"`
+	Scores JSONDuplicateScores `json:"scores"`
+}
+
+type GetDuplicatesResponse struct {
+	Duplicates []JSONDuplicate `json:"duplicates"`
+}
+
+func ConvertToGetDuplicatesResponse(candidates []domain.DuplicateCandidate) GetDuplicatesResponse {
+	duplicates := make([]JSONDuplicate, len(candidates))
+	for i, c := range candidates {
+		duplicates[i] = JSONDuplicate{
+			Person: ConvertToJSONPerson(c.Person),
+			Score:  round(c.Score),
+			Scores: JSONDuplicateScores{
+				Name:    round(c.NameScore),
+				Age:     round(c.AgeScore),
+				Hobbies: round(c.HobbyScore),
+			},
+		}
+	}
+	return GetDuplicatesResponse{Duplicates: duplicates}
+}
+
+// round keeps three decimals of a score.
+func round(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
+
+// JSONMerge is a person merged into the target, as it was before the merge.
+type JSONMerge struct {
+	ID         uuid.UUID         `json:"id"`
+	TargetID   uuid.UUID         `json:"targetId"`
+	Source     JSONPerson        `json:"source"`
+	Strategies map[string]string `json:"strategies"`
+	MergedAt   time.Time         `json:"mergedAt"`
+}
+
+type GetMergesResponse struct {
+	Merges []JSONMerge `json:"merges"`
+}
+
+func ConvertToGetMergesResponse(merges []domain.Merge) GetMergesResponse {
+	out := make([]JSONMerge, len(merges))
+	for i, m := range merges {
+		strategies := make(map[string]string, len(m.Strategies))
+		for field, strategy := range m.Strategies {
+			strategies[field] = string(strategy)
+		}
+		out[i] = JSONMerge{
+			ID:         m.ID,
+			TargetID:   m.TargetID,
+			Source:     ConvertToJSONPerson(m.Source),
+			Strategies: strategies,
+			MergedAt:   m.MergedAt,
+		}
+	}
+	return GetMergesResponse{Merges: out}
+}
+
+type JSONRelationship struct {
+	FromID    uuid.UUID `json:"fromId"`
+	ToID      uuid.UUID `json:"toId"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func ConvertToJSONRelationship(r domain.Relationship) JSONRelationship {
+	return JSONRelationship{FromID: r.FromID, ToID: r.ToID, Type: string(r.Type), CreatedAt: r.CreatedAt}
+}
+
+type GetRelationshipsResponse struct {
+	Relationships []JSONRelationship `json:"relationships"`
+}
+
+func ConvertToGetRelationshipsResponse(rels []domain.Relationship) GetRelationshipsResponse {
+	out := make([]JSONRelationship, len(rels))
+	for i, r := range rels {
+		out[i] = ConvertToJSONRelationship(r)
+	}
+	return GetRelationshipsResponse{Relationships: out}
+}
+
+// PathResponse lists the persons on a path in order and the relationships
+// linking each to the next.
+type PathResponse struct {
+	Length        int                `json:"length"`
+	Persons       []JSONPerson       `json:"persons"`
+	Relationships []JSONRelationship `json:"relationships"`
+}
+
+func ConvertToPathResponse(p domain.Path) PathResponse {
+	persons := make([]JSONPerson, len(p.Persons))
+	for i, person := range p.Persons {
+		persons[i] = ConvertToJSONPerson(person)
+	}
+	return PathResponse{
+		Length:        len(p.Relationships),
+		Persons:       persons,
+		Relationships: ConvertToGetRelationshipsResponse(p.Relationships).Relationships,
+	}
+}
+
+type JSONSuggestion struct {
+	Person JSONPerson `json:"person"`
+	Mutual int        `json:"mutualFriends"`
+}
+
+type FriendsOfFriendsResponse struct {
+	Suggestions []JSONSuggestion `json:"suggestions"`
+}
+
+func ConvertToFriendsOfFriendsResponse(suggestions []domain.Suggestion) FriendsOfFriendsResponse {
+	out := make([]JSONSuggestion, len(suggestions))
+	for i, s := range suggestions {
+		out[i] = JSONSuggestion{Person: ConvertToJSONPerson(s.Person), Mutual: s.Mutual}
+	}
+	return FriendsOfFriendsResponse{Suggestions: out}
+}
+
+type JSONHobby struct {
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	// Persons is the number of stored persons with the hobby.
+	Persons int `json:"persons"`
+}
+
+type GetHobbiesResponse struct {
+	Hobbies []JSONHobby `json:"hobbies"`
+}
+
+func ConvertToGetHobbiesResponse(suggestions []domain.HobbySuggestion) GetHobbiesResponse {
+	hobbies := make([]JSONHobby, len(suggestions))
+	for i, s := range suggestions {
+		hobbies[i] = JSONHobby{Name: s.Name, Category: s.Category, Persons: s.Persons}
+	}
+	return GetHobbiesResponse{Hobbies: hobbies}
+}
+
+type JSONAgeBucket struct {
+	Min int32 `json:"min"`
+	// Max is exclusive and absent for the last bucket.
+	Max   int32 `json:"max,omitempty"`
+	Count int   `json:"count"`
+}
+
+type JSONAgeStats struct {
+	Min    int32   `json:"min"`
+	Max    int32   `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	// Percentiles maps "p90" and the like to ages.
+	Percentiles map[string]float64 `json:"percentiles"`
+	Histogram   []JSONAgeBucket    `json:"histogram"`
+}
+
+type JSONHobbyCount struct {
+	Hobby string `json:"hobby"`
+	Count int    `json:"count"`
+}
+
+type JSONHobbyPair struct {
+	Hobbies [2]string `json:"hobbies"`
+	Count   int       `json:"count"`
+}
+
+type StatsResponse struct {
+	Total      int              `json:"total"`
+	Ages       JSONAgeStats     `json:"ages"`
+	TopHobbies []JSONHobbyCount `json:"topHobbies"`
+	HobbyPairs []JSONHobbyPair  `json:"hobbyPairs"`
+}
+
+func ConvertToStatsResponse(s domain.Stats) StatsResponse {
+	out := StatsResponse{
+		Total: s.Total,
+		Ages: JSONAgeStats{
+			Min:         s.Ages.Min,
+			Max:         s.Ages.Max,
+			Mean:        round(s.Ages.Mean),
+			Median:      s.Ages.Median,
+			Percentiles: make(map[string]float64, len(s.Ages.Percentiles)),
+			Histogram:   make([]JSONAgeBucket, len(s.Ages.Histogram)),
+		},
+		TopHobbies: make([]JSONHobbyCount, len(s.TopHobbies)),
+		HobbyPairs: make([]JSONHobbyPair, len(s.HobbyPairs)),
+	}
+	for p, age := range s.Ages.Percentiles {
+		out.Ages.Percentiles["p"+strconv.FormatFloat(p, 'f', -1, 64)] = age
+	}
+	for i, b := range s.Ages.Histogram {
+		out.Ages.Histogram[i] = JSONAgeBucket{Min: b.Min, Max: b.Max, Count: b.Count}
+	}
+	for i, h := range s.TopHobbies {
+		out.TopHobbies[i] = JSONHobbyCount{Hobby: h.Hobby, Count: h.Count}
+	}
+	for i, p := range s.HobbyPairs {
+		out.HobbyPairs[i] = JSONHobbyPair{Hobbies: p.Pair, Count: p.Count}
+	}
+	return out
+}
+
+// JSONSimilarPerson is a person scored from 0 to 1 on hobby similarity.
+type JSONSimilarPerson struct {
+	Person        JSONPerson `json:"person"`
+	Score         float64    `json:"score"`
+	SharedHobbies []string   `json:"sharedHobbies"`
+}
+
+type GetSimilarResponse struct {
+	Similar []JSONSimilarPerson `json:"similar"`
+}
+
+func ConvertToGetSimilarResponse(similar []domain.SimilarPerson) GetSimilarResponse {
+	out := make([]JSONSimilarPerson, len(similar))
+	for i, s := range similar {
+		out[i] = JSONSimilarPerson{Person: ConvertToJSONPerson(s.Person), Score: round(s.Score), SharedHobbies: s.SharedHobbies}
+	}
+	return GetSimilarResponse{Similar: out}
+}
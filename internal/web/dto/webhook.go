@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/lafetz/assessment/internal/webhook"
+)
+
+type CreateWebhook struct {
+	URL string `json:"url" validate:"required,http_url"`
+	// Events filters the delivered events; empty subscribes to all of them.
+	Events []string `json:"events" validate:"dive,oneof=person.created person.updated person.deleted"`
+	// Secret signs deliveries; one is generated when empty.
+	Secret string `json:"secret" validate:"omitempty,min=16"`
+}
+
+func (in CreateWebhook) ToSubscription() webhook.Subscription {
+	events := make([]domain.EventType, len(in.Events))
+	for i, e := range in.Events {
+		events[i] = domain.EventType(e)
+	}
+	return webhook.Subscription{URL: in.URL, Events: events, Secret: in.Secret}
+}
+
+type JSONWebhook struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Secret is only returned when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+func ConvertToJSONWebhook(sub webhook.Subscription, withSecret bool) JSONWebhook {
+	events := make([]string, len(sub.Events))
+	for i, e := range sub.Events {
+		events[i] = string(e)
+	}
+	w := JSONWebhook{ID: sub.ID, URL: sub.URL, Events: events, CreatedAt: sub.CreatedAt}
+	if withSecret {
+		w.Secret = sub.Secret
+	}
+	return w
+}
+
+func ConvertToJSONWebhookArray(subs []webhook.Subscription) []JSONWebhook {
+	webhooks := make([]JSONWebhook, len(subs))
+	for i, sub := range subs {
+		webhooks[i] = ConvertToJSONWebhook(sub, false)
+	}
+	return webhooks
+}
+
+type JSONDeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	DurationMs int64     `json:"durationMs"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type JSONDelivery struct {
+	ID            uuid.UUID             `json:"id"`
+	WebhookID     uuid.UUID             `json:"webhookId"`
+	EventID       uuid.UUID             `json:"eventId"`
+	EventType     string                `json:"eventType"`
+	Status        string                `json:"status"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	NextAttemptAt *time.Time            `json:"nextAttemptAt,omitempty"`
+	Attempts      []JSONDeliveryAttempt `json:"attempts"`
+}
+
+func ConvertToJSONDelivery(d webhook.Delivery) JSONDelivery {
+	attempts := make([]JSONDeliveryAttempt, len(d.Attempts))
+	for i, a := range d.Attempts {
+		attempts[i] = JSONDeliveryAttempt{At: a.At, DurationMs: a.Duration.Milliseconds(), StatusCode: a.StatusCode, Error: a.Error}
+	}
+	delivery := JSONDelivery{
+		ID:        d.ID,
+		WebhookID: d.SubscriptionID,
+		EventID:   d.EventID,
+		EventType: string(d.EventType),
+		Status:    string(d.Status),
+		CreatedAt: d.CreatedAt,
+		Attempts:  attempts,
+	}
+	if !d.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = &d.NextAttemptAt
+	}
+	return delivery
+}
+
+func ConvertToJSONDeliveryArray(deliveries []webhook.Delivery) []JSONDelivery {
+	out := make([]JSONDelivery, len(deliveries))
+	for i, d := range deliveries {
+		out[i] = ConvertToJSONDelivery(d)
+	}
+	return out
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/google/uuid"
+
+const (
+	WSSubscribe    = "subscribe"
+	WSUnsubscribe  = "unsubscribe"
+	WSPing         = "ping"
+	WSSubscribed   = "subscribed"
+	WSUnsubscribed = "unsubscribed"
+	WSEvent        = "event"
+	WSPong         = "pong"
+	WSError        = "error"
+)
+
+// WSClientMessage is sent by WebSocket clients. Subscribe uses ID, an
+// optional list of PersonIDs and an optional Filter expression; both must
+// match when given. Unsubscribe only needs ID.
+type WSClientMessage struct {
+	Type      string      `json:"type"`
+	ID        string      `json:"id,omitempty"`
+	PersonIDs []uuid.UUID `json:"personIds,omitempty"`
+	Filter    string      `json:"filter,omitempty"`
+}
+
+// WSServerMessage acknowledges client messages, reports errors and carries
+// events together with the IDs of the subscriptions they matched.
+type WSServerMessage struct {
+	Type          string           `json:"type"`
+	ID            string           `json:"id,omitempty"`
+	Subscriptions []string         `json:"subscriptions,omitempty"`
+	Event         *JSONPersonEvent `json:"event,omitempty"`
+	Message       string           `json:"message,omitempty"`
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+)
+
+// maxSchemaBytes bounds the size of attribute schemas.
+const maxSchemaBytes = 64 << 10
+
+// GetAttributeSchema godoc
+//
+//	@Summary		Get the attribute schema
+//	@Description	Get the attribute schema that the tenant's writes of custom attributes are validated against. Persons are shared by all tenants; only the schema is per tenant.
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			X-Tenant-ID		header		string	false	"Tenant, default when absent"
+//	@Param			Authorization	header		string	false	"Bearer admin token, required when one is configured"
+//	@Success		200				{object}	dto.AttributeSchema
+//	@Failure		401				{object}	string	"Missing or wrong admin token"
+//	@Failure		404			{object}	string	"The tenant has no schema"
+//	@Router			/api/v1/admin/attribute-schema [get]
+func GetAttributeSchema(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		schema, err := personSvc.GetAttributeSchema(r.Context())
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToAttributeSchema(schema)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
+// PutAttributeSchema godoc
+//
+//	@Summary		Set the attribute schema
+//	@Description	Replace the attribute schema that the tenant's writes of custom attributes are validated against. Stored persons are checked when they are next written. Persons are shared by all tenants; only the schema is per tenant.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID		header		string				false	"Tenant, default when absent"
+//	@Param			Authorization	header		string				false	"Bearer admin token, required when one is configured"
+//	@Param			schema		body		dto.AttributeSchema	true	"Attribute schema: an object of string, integer, number or boolean properties"
+//	@Success		200			{object}	dto.AttributeSchema
+//	@Failure		400			{object}	string	"Invalid JSON or unsupported keyword"
+//	@Failure		401			{object}	string	"Missing or wrong admin token"
+//	@Failure		422			{object}	customvalidator.ValidationErrorResponse	"Invalid schema"
+//	@Router			/api/v1/admin/attribute-schema [put]
+func PutAttributeSchema(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		var in dto.AttributeSchema
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSchemaBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&in); err != nil {
+			writeError(w, "Invalid schema: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+		schema, err := personSvc.SetAttributeSchema(r.Context(), in.ToSchema())
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToAttributeSchema(schema)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+const (
+	defaultDuplicateMinScore = 0.75
+	defaultDuplicateLimit    = 10
+	maxDuplicateLimit        = 100
+)
+
+// GetDuplicates godoc
+//
+//	@Summary		Find possible duplicates of a person
+//	@Description	Score every other person on normalized name, age and hobbies and return the best matches
+//	@Tags			Persons
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Param			minScore	query		number	false	"Lowest score returned, between 0 and 1"	default(0.75)
+//	@Param			limit		query		int		false	"Maximum number of candidates"				default(10)
+//	@Success		200			{object}	dto.GetDuplicatesResponse
+//	@Failure		400			{object}	string	"Invalid minScore or limit"
+//	@Failure		404			{object}	string	"Person not found"
+//	@Router			/api/v1/persons/{personId}/duplicates [get]
+func GetDuplicates(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		minScore := defaultDuplicateMinScore
+		if v := r.URL.Query().Get("minScore"); v != "" {
+			if minScore, err = strconv.ParseFloat(v, 64); err != nil || minScore < 0 || minScore > 1 {
+				writeError(w, "minScore must be a number between 0 and 1", http.StatusBadRequest)
+				return
+			}
+		}
+		limit := defaultDuplicateLimit
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxDuplicateLimit {
+				writeError(w, "limit must be between 1 and 100", http.StatusBadRequest)
+				return
+			}
+		}
+
+		candidates, err := personSvc.FindDuplicates(r.Context(), personID, minScore, limit)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToGetDuplicatesResponse(candidates)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/lafetz/assessment/internal/events"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+// sseRetry is the reconnection delay suggested to EventSource clients.
+const sseRetry = 3 * time.Second
+
+type eventFilter struct {
+	types    []domain.EventType
+	personID uuid.UUID
+}
+
+func (f eventFilter) matches(e events.Event) bool {
+	if len(f.types) > 0 && !slices.Contains(f.types, e.Type) {
+		return false
+	}
+	return f.personID == uuid.Nil || f.personID == e.Person.ID
+}
+
+func parseEventFilter(r *http.Request) (eventFilter, error) {
+	var f eventFilter
+	if types := r.URL.Query().Get("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			eventType := domain.EventType(strings.TrimSpace(t))
+			if !slices.Contains(domain.EventTypes, eventType) {
+				return f, fmt.Errorf("unknown event type %q", t)
+			}
+			f.types = append(f.types, eventType)
+		}
+	}
+	if id := r.URL.Query().Get("personId"); id != "" {
+		personID, err := uuid.Parse(id)
+		if err != nil {
+			return f, fmt.Errorf("invalid person ID %q", id)
+		}
+		f.personID = personID
+	}
+	return f, nil
+}
+
+// lastEventID reads the Last-Event-ID header sent by reconnecting
+// EventSource clients, or the lastEventId query parameter for the first
+// connection of a client resuming from a stored position.
+func lastEventID(r *http.Request) (uint64, bool) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("lastEventId")
+	}
+	seq, err := strconv.ParseUint(v, 10, 64)
+	return seq, err == nil
+}
+
+// StreamPersonEvents godoc
+//
+//	@Summary		Stream person changes
+//	@Description	Server-Sent Events stream of person.created, person.updated and person.deleted events. Event IDs increase monotonically; reconnecting with Last-Event-ID replays missed events from a bounded buffer. A "resync" event means some events could not be replayed and clients should reload the collection. Comment lines are sent as heartbeats.
+//	@Tags			Persons
+//	@Produce		text/event-stream
+//	@Param			types			query	string	false	"Comma separated event types to receive"
+//	@Param			personId		query	string	false	"Only events of this person"
+//	@Param			lastEventId		query	int		false	"Resume after this event ID, like the Last-Event-ID header"
+//	@Param			Last-Event-ID	header	int		false	"Resume after this event ID"
+//	@Success		200				{object}	dto.JSONPersonEvent
+//	@Failure		422				{object}	string	"Invalid filter"
+//	@Router			/api/v1/persons/events [get]
+func StreamPersonEvents(bus *events.Bus, heartbeat time.Duration, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		filter, err := parseEventFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		lastSeq, resume := lastEventID(r)
+		sub, backlog, complete := bus.Subscribe(lastSeq, resume)
+		defer bus.Unsubscribe(sub)
+
+		// The stream outlives the server's write timeout.
+		rc := http.NewResponseController(w)
+		_ = rc.SetWriteDeadline(time.Time{})
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+		if !complete {
+			fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+		}
+		for _, e := range backlog {
+			if filter.matches(e) {
+				if err := writeEvent(w, e); err != nil {
+					return
+				}
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			logger.ErrorContext(r.Context(), "event stream can not be flushed", "error", err)
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if !filter.matches(e) {
+					continue
+				}
+				if err := writeEvent(w, e); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e events.Event) error {
+	data, err := json.Marshal(dto.ConvertToJSONPersonEvent(e.Event))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+	return err
+}
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+)
+
+// CreateGroup godoc
+//
+//	@Summary		Create a group
+//	@Tags			Groups
+//	@Accept			json
+//	@Produce		json
+//	@Param			group	body		dto.CreateGroup	true	"Group"
+//	@Success		201		{object}	dto.JSONGroup
+//	@Failure		400		{object}	string	"Invalid input"
+//	@Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed"
+//	@Router			/api/v1/groups [post]
+func CreateGroup(groupSvc person.GroupSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		var in dto.CreateGroup
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+		group, err := groupSvc.AddGroup(r.Context(), in.ToGroup())
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusCreated, dto.ConvertToJSONGroup(group), logger)
+	}
+}
+
+// GetGroups godoc
+//
+//	@Summary		List groups
+//	@Description	List groups ordered by name
+//	@Tags			Groups
+//	@Produce		json
+//	@Param			page	query		int	false	"Page number"	default(0)
+//	@Param			size	query		int	false	"Page size"		default(10)
+//	@Success		200		{object}	dto.GetGroupsResponse
+//	@Router			/api/v1/groups [get]
+func GetGroups(groupSvc person.GroupSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		page, size := parsePage(r)
+		groups, meta, err := groupSvc.GetGroups(r.Context(), page, size)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToGetGroupsResponse(groups, meta), logger)
+	}
+}
+
+// GetGroup godoc
+//
+//	@Summary		Get a group
+//	@Tags			Groups
+//	@Produce		json
+//	@Param			groupId	path		string	true	"ID of the group"
+//	@Success		200		{object}	dto.JSONGroup
+//	@Failure		404		{object}	string	"Group not found"
+//	@Router			/api/v1/groups/{groupId} [get]
+func GetGroup(groupSvc person.GroupSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, err := uuid.Parse(r.PathValue("groupId"))
+		if err != nil {
+			http.Error(w, "Invalid group ID", http.StatusUnprocessableEntity)
+			return
+		}
+		group, err := groupSvc.GetGroup(r.Context(), groupID)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONGroup(group), logger)
+	}
+}
+
+// UpdateGroup godoc
+//
+//	@Summary		Update a group
+//	@Description	Replace the name and description of a group
+//	@Tags			Groups
+//	@Accept			json
+//	@Produce		json
+//	@Param			groupId	path		string			true	"ID of the group"
+//	@Param			group	body		dto.CreateGroup	true	"Group"
+//	@Success		200		{object}	dto.JSONGroup
+//	@Failure		400		{object}	string	"Invalid input"
+//	@Failure		404		{object}	string	"Group not found"
+//	@Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed"
+//	@Router			/api/v1/groups/{groupId} [put]
+func UpdateGroup(groupSvc person.GroupSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, err := uuid.Parse(r.PathValue("groupId"))
+		if err != nil {
+			http.Error(w, "Invalid group ID", http.StatusUnprocessableEntity)
+			return
+		}
+		var in dto.CreateGroup
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+		group := in.ToGroup()
+		group.ID = groupID
+		group, err = groupSvc.UpdateGroup(r.Context(), group)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONGroup(group), logger)
+	}
+}
+
+// DeleteGroup godoc
+//
+//	@Summary		Delete a group
+//	@Description	Delete a group and its memberships; the members are kept
+//	@Tags			Groups
+//	@Param			groupId	path	string	true	"ID of the group"
+//	@Success		204		"No Content"
+//	@Failure		404		{object}	string	"Group not found"
+//	@Router			/api/v1/groups/{groupId} [delete]
+func DeleteGroup(groupSvc person.GroupSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, err := uuid.Parse(r.PathValue("groupId"))
+		if err != nil {
+			http.Error(w, "Invalid group ID", http.StatusUnprocessableEntity)
+			return
+		}
+		if err := groupSvc.DeleteGroup(r.Context(), groupID); err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AddGroupMember godoc
+//
+//	@Summary		Add a person to a group
+//	@Tags			Groups
+//	@Accept			json
+//	@Produce		json
+//	@Param			groupId	path		string			true	"ID of the group"
+//	@Param			member	body		dto.AddMember	true	"Person and role, member by default"
+//	@Success		201		{object}	dto.JSONMember
+//	@Failure		400		{object}	string	"Invalid input"
+//	@Failure		404		{object}	string	"Group or person not found"
+//	@Failure		409		{object}	string	"Person is already a member"
+//	@Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed"
+//	@Router			/api/v1/groups/{groupId}/members [post]
+func AddGroupMember(groupSvc person.GroupSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, err := uuid.Parse(r.PathValue("groupId"))
+		if err != nil {
+			http.Error(w, "Invalid group ID", http.StatusUnprocessableEntity)
+			return
+		}
+		var in dto.AddMember
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+		member, err := groupSvc.AddMember(r.Context(), groupID, in.PersonID, in.DomainRole())
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusCreated, dto.ConvertToJSONMember(member), logger)
+	}
+}
+
+// GetGroupMembers godoc
+//
+//	@Summary		List the members of a group
+//	@Description	List the members of a group ordered by name
+//	@Tags			Groups
+//	@Produce		json
+//	@Param			groupId	path		string	true	"ID of the group"
+//	@Param			page	query		int		false	"Page number"	default(0)
+//	@Param			size	query		int		false	"Page size"		default(10)
+//	@Success		200		{object}	dto.GetMembersResponse
+//	@Failure		404		{object}	string	"Group not found"
+//	@Router			/api/v1/groups/{groupId}/members [get]
+func GetGroupMembers(groupSvc person.GroupSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, err := uuid.Parse(r.PathValue("groupId"))
+		if err != nil {
+			http.Error(w, "Invalid group ID", http.StatusUnprocessableEntity)
+			return
+		}
+		page, size := parsePage(r)
+		members, meta, err := groupSvc.GetMembers(r.Context(), groupID, page, size)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToGetMembersResponse(members, meta), logger)
+	}
+}
+
+// UpdateGroupMember godoc
+//
+//	@Summary		Change the role of a member
+//	@Tags			Groups
+//	@Accept			json
+//	@Produce		json
+//	@Param			groupId		path		string				true	"ID of the group"
+//	@Param			personId	path		string				true	"ID of the member"
+//	@Param			member		body		dto.UpdateMember	true	"Role"
+//	@Success		200			{object}	dto.JSONMember
+//	@Failure		400			{object}	string	"Invalid input"
+//	@Failure		404			{object}	string	"Not a member"
+//	@Failure		422			{object}	customvalidator.ValidationErrorResponse		"Validation failed"
+//	@Router			/api/v1/groups/{groupId}/members/{personId} [put]
+func UpdateGroupMember(groupSvc person.GroupSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, personID, ok := parseMemberPath(w, r)
+		if !ok {
+			return
+		}
+		var in dto.UpdateMember
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+		member, err := groupSvc.UpdateMember(r.Context(), groupID, personID, domain.GroupRole(in.Role))
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONMember(member), logger)
+	}
+}
+
+// RemoveGroupMember godoc
+//
+//	@Summary		Remove a person from a group
+//	@Tags			Groups
+//	@Param			groupId		path	string	true	"ID of the group"
+//	@Param			personId	path	string	true	"ID of the member"
+//	@Success		204			"No Content"
+//	@Failure		404			{object}	string	"Not a member"
+//	@Router			/api/v1/groups/{groupId}/members/{personId} [delete]
+func RemoveGroupMember(groupSvc person.GroupSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		groupID, personID, ok := parseMemberPath(w, r)
+		if !ok {
+			return
+		}
+		if err := groupSvc.RemoveMember(r.Context(), groupID, personID); err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetPersonGroups godoc
+//
+//	@Summary		List the groups of a person
+//	@Description	List the groups a person belongs to, with its role, ordered by name
+//	@Tags			Groups
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Param			page		query		int		false	"Page number"	default(0)
+//	@Param			size		query		int		false	"Page size"		default(10)
+//	@Success		200			{object}	dto.GetPersonGroupsResponse
+//	@Failure		404			{object}	string	"Person not found"
+//	@Router			/api/v1/persons/{personId}/groups [get]
+func GetPersonGroups(groupSvc person.GroupSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		page, size := parsePage(r)
+		groups, meta, err := groupSvc.GetPersonGroups(r.Context(), personID, page, size)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToGetPersonGroupsResponse(groups, meta), logger)
+	}
+}
+
+// parseMemberPath parses the group and person IDs of a member route,
+// answering 422 when either is invalid.
+func parseMemberPath(w http.ResponseWriter, r *http.Request) (groupID, personID uuid.UUID, ok bool) {
+	groupID, err := uuid.Parse(r.PathValue("groupId"))
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusUnprocessableEntity)
+		return groupID, personID, false
+	}
+	personID, err = uuid.Parse(r.PathValue("personId"))
+	if err != nil {
+		http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+		return groupID, personID, false
+	}
+	return groupID, personID, true
+}
+
+// parsePage reads the zero-based page and the page size of a list the way
+// GetPersons does.
+func parsePage(r *http.Request) (page, size int32) {
+	p, err := strconv.ParseInt(r.URL.Query().Get("page"), 10, 32)
+	if err != nil || p < 0 {
+		p = 0
+	}
+	n, err := strconv.ParseInt(r.URL.Query().Get("size"), 10, 32)
+	if err != nil || n <= 0 {
+		n = 10
+	}
+	return int32(p), int32(n)
+}
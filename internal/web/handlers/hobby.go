@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+const (
+	defaultHobbyLimit = 10
+	maxHobbyLimit     = 50
+)
+
+// GetHobbies godoc
+//
+//	@Summary		Autocomplete hobbies
+//	@Description	List catalogued and stored hobbies whose name, an alias, or a word of them starts with prefix, the ones most persons have first
+//	@Tags			Hobbies
+//	@Produce		json
+//	@Param			prefix	query		string	false	"Start of the hobby, ignoring case and accents"
+//	@Param			limit	query		int		false	"Maximum number of hobbies"	default(10)
+//	@Success		200		{object}	dto.GetHobbiesResponse
+//	@Failure		400		{object}	string	"Invalid limit"
+//	@Router			/api/v1/hobbies [get]
+func GetHobbies(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		limit := defaultHobbyLimit
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxHobbyLimit {
+				writeError(w, "limit must be between 1 and 50", http.StatusBadRequest)
+				return
+			}
+		}
+
+		hobbies, err := personSvc.SuggestHobbies(r.Context(), r.URL.Query().Get("prefix"), limit)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToGetHobbiesResponse(hobbies)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+)
+
+// MergePerson godoc
+//
+//	@Summary		Merge a person into another one
+//	@Description	Fold the source person into this one field by field, delete the source and redirect reads of it here
+//	@Tags			Persons
+//	@Accept			json
+//	@Produce		json
+//	@Param			personId	path		string			true	"ID of the person that survives"
+//	@Param			merge		body		dto.MergePerson	true	"Source person and per-field strategies"
+//	@Success		200			{object}	dto.JSONPerson
+//	@Failure		400			{object}	string	"Invalid input"
+//	@Failure		404			{object}	string	"Person not found"
+//	@Failure		409			{object}	string	"A kept value is used by another person"
+//	@Failure		422			{object}	string	"Invalid merge"
+//	@Router			/api/v1/persons/{personId}/merge [post]
+func MergePerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		targetID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		var in dto.MergePerson
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+
+		merged, err := personSvc.MergePersons(r.Context(), targetID, in.SourceID, in.DomainStrategies())
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToJSONPerson(merged)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
+// GetMerges godoc
+//
+//	@Summary		List the merges into a person
+//	@Description	Show the records merged into this person, oldest first, as they were before the merge
+//	@Tags			Persons
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Success		200			{object}	dto.GetMergesResponse
+//	@Failure		404			{object}	string	"Person not found"
+//	@Router			/api/v1/persons/{personId}/merges [get]
+func GetMerges(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		merges, err := personSvc.GetMerges(r.Context(), personID)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToGetMergesResponse(merges)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
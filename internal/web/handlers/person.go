@@ -2,14 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
-
 	"github.com/lafetz/assessment/internal/core/domain"
+
 	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
 	"github.com/lafetz/assessment/internal/web/dto"
 	customvalidator "github.com/lafetz/assessment/internal/web/validation"
 )
@@ -21,13 +24,16 @@ import (
 //	@Tags			Persons
 //	@Accept			json
 //	@Produce		json
-//	@Param			person	body		dto.CreatePerson	true	"Person data"
-//	@Success		201		{object}	domain.Person
-//	@Failure		400		{object}	string	"Invalid input"
-//	@Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed"
+//	@Param			person			body		dto.CreatePerson	true	"Person data"
+//	@Param			Idempotency-Key	header		string				false	"Replays the first response to retries with the same key"
+//	@Success		201				{object}	dto.JSONPerson
+//	@Failure		400				{object}	string	"Invalid input"
+//	@Failure		409				{object}	string	"A request with the same Idempotency-Key is in progress"
+//	@Failure		422				{object}	customvalidator.ValidationErrorResponse		"Validation failed, or Idempotency-Key reused with a different body"
 //	@Router			/api/v1/persons [post]
 func AddPerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
 		var createPerson dto.CreatePerson
 		if err := json.NewDecoder(r.Body).Decode(&createPerson); err != nil {
 			http.Error(w, "Invalid input", http.StatusBadRequest)
@@ -36,8 +42,8 @@ func AddPerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvali
 		if v.ValidateAndRespond(w, createPerson) {
 			return
 		}
-		person := domain.NewPerson(createPerson.Name, createPerson.Age, createPerson.Hobbies)
-		if _, err := personSvc.AddPerson(r.Context(), person); err != nil {
+		person, err := personSvc.AddPerson(r.Context(), createPerson.ToPerson())
+		if err != nil {
 			HandleError(err, w, logger)
 			return
 		}
@@ -56,11 +62,13 @@ func AddPerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvali
 // @Accept			json
 // @Produce		json
 // @Param			personId	path		string	true	"ID of the person"
-// @Success		200			{object}	domain.Person
+// @Success		200			{object}	dto.JSONPerson
+// @Success		308			"Person was merged; Location names the survivor"
 // @Failure		404			{object}	string	"Person not found"
 // @Router			/api/v1/persons/{personId} [get]
 func GetPersonByID(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
 		personIDStr := r.PathValue("personId")
 		personID, err := uuid.Parse(personIDStr)
 		if err != nil {
@@ -68,14 +76,19 @@ func GetPersonByID(personSvc person.PersonSvcApi, logger *slog.Logger) http.Hand
 			return
 		}
 
-		person, err := personSvc.GetPerson(r.Context(), personID)
+		p, err := personSvc.GetPerson(r.Context(), personID)
+		var merged *person.MergedError
+		if errors.As(err, &merged) {
+			http.Redirect(w, r, "/api/v1/persons/"+merged.Into.String(), http.StatusPermanentRedirect)
+			return
+		}
 		if err != nil {
 			HandleError(err, w, logger)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(dto.ConvertToJSONPerson(person)); err != nil {
+		if err := json.NewEncoder(w).Encode(dto.ConvertToJSONPerson(p)); err != nil {
 			HandleError(err, w, logger)
 		}
 	}
@@ -84,7 +97,7 @@ func GetPersonByID(personSvc person.PersonSvcApi, logger *slog.Logger) http.Hand
 // GetPersons godoc
 //
 //	@Summary		Get all persons
-//	@Description	Retrieve a list of persons with pagination support
+//	@Description	Retrieve a list of persons with pagination support. Query parameters named attributes.{name} only keep persons whose attribute has that value, e.g. attributes.department=sales or attributes.badge=42.
 //	@Tags			Persons
 //	@Accept			json
 //	@Produce		json
@@ -95,6 +108,7 @@ func GetPersonByID(personSvc person.PersonSvcApi, logger *slog.Logger) http.Hand
 //	@Router			/api/v1/persons [get]
 func GetPersons(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
 		page, err := strconv.ParseInt(r.URL.Query().Get("page"), 10, 32)
 		if err != nil {
 			page = 0
@@ -104,9 +118,9 @@ func GetPersons(personSvc person.PersonSvcApi, logger *slog.Logger) http.Handler
 			size = 10
 		}
 
-		persons, metadata, err := personSvc.GetPersons(r.Context(), int32(page), int32(size))
+		persons, metadata, err := personSvc.GetPersons(r.Context(), parsePersonFilter(r), int32(page), int32(size))
 		if err != nil {
-			logger.Error(err.Error())
+			logger.ErrorContext(r.Context(), err.Error())
 			http.Error(w, "intrnal server error", http.StatusInternalServerError)
 			return
 		}
@@ -121,19 +135,20 @@ func GetPersons(personSvc person.PersonSvcApi, logger *slog.Logger) http.Handler
 
 // UpdatePerson godoc
 // @Summary		Update an existing person
-// @Description	Update a person by their ID
+// @Description	Replace a person by their ID. email, phone, birthDate, address and attributes keep their value when they are not sent; send null to remove one
 // @Tags			Persons
 // @Accept			json
 // @Produce		json
 // @Param			personId	path		string			true	"ID of the person"
 // @Param			person		body		dto.CreatePerson	true	"Updated person data"
-// @Success		200			{object}	domain.Person
+// @Success		200			{object}	dto.JSONPerson
 // @Failure		404			{object}	string	"Person not found"
 // @Failure		400			{object}	string	"Invalid input"
 // @Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed"
 // @Router			/api/v1/persons/{personId} [put]
 func UpdatePerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
 		personIDStr := r.PathValue("personId")
 
 		personID, err := uuid.Parse(personIDStr)
@@ -150,10 +165,7 @@ func UpdatePerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customv
 		if v.ValidateAndRespond(w, updatePerson) {
 			return
 		}
-		person := domain.NewPerson(updatePerson.Name, updatePerson.Age, updatePerson.Hobbies)
-		person.ID = personID
-
-		updatedPerson, err := personSvc.UpdatePerson(r.Context(), person)
+		updatedPerson, err := personSvc.PatchPerson(r.Context(), personID, updatePerson.Apply)
 		if err != nil {
 			HandleError(err, w, logger)
 			return
@@ -167,6 +179,49 @@ func UpdatePerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customv
 	}
 }
 
+// PatchPerson godoc
+// @Summary		Partially update a person
+// @Description	Apply a JSON merge patch to a person; fields that are not sent keep their value. null removes email, phone, birthDate, address or attributes; name, age and hobbies can not be null
+// @Tags			Persons
+// @Accept			json
+// @Produce		json
+// @Param			personId	path		string			true	"ID of the person"
+// @Param			person		body		dto.PatchPerson	true	"Fields to change"
+// @Success		200			{object}	dto.JSONPerson
+// @Failure		404			{object}	string	"Person not found"
+// @Failure		400			{object}	string	"Invalid input"
+// @Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed"
+// @Router			/api/v1/persons/{personId} [patch]
+func PatchPerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+
+		var patch dto.PatchPerson
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, patch) {
+			return
+		}
+		updatedPerson, err := personSvc.PatchPerson(r.Context(), personID, patch.Apply)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(dto.ConvertToJSONPerson(updatedPerson)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
 // DeletePerson godoc
 //
 //	@Summary		Delete a person
@@ -180,6 +235,7 @@ func UpdatePerson(personSvc person.PersonSvcApi, logger *slog.Logger, v *customv
 //	@Router			/api/v1/persons/{personId} [delete]
 func DeletePerson(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
 		personIDStr := r.PathValue("personId")
 		personID, err := uuid.Parse(personIDStr)
 		if err != nil {
@@ -193,3 +249,22 @@ func DeletePerson(personSvc person.PersonSvcApi, logger *slog.Logger) http.Handl
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// attributeParam prefixes the list query parameters filtering on an
+// attribute.
+const attributeParam = "attributes."
+
+func parsePersonFilter(r *http.Request) domain.PersonFilter {
+	var filter domain.PersonFilter
+	for key, values := range r.URL.Query() {
+		name, ok := strings.CutPrefix(key, attributeParam)
+		if !ok || name == "" {
+			continue
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[name] = values[0]
+	}
+	return filter
+}
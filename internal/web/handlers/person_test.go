@@ -42,7 +42,7 @@ func (m *MockPersonSvc) GetPerson(ctx context.Context, id uuid.UUID) (domain.Per
 	}, nil
 }
 
-func (m *MockPersonSvc) GetPersons(ctx context.Context, page, size int32) ([]domain.Person, domain.Metadata, error) {
+func (m *MockPersonSvc) GetPersons(ctx context.Context, filter domain.PersonFilter, page, size int32) ([]domain.Person, domain.Metadata, error) {
 	persons := []domain.Person{
 		{ID: uuid.New(), Name: "Alice", Age: 25, Hobbies: []string{"Dancing"}},
 		{ID: uuid.New(), Name: "Bob", Age: 28, Hobbies: []string{"Cycling"}},
@@ -63,6 +63,63 @@ func (m *MockPersonSvc) DeletePerson(ctx context.Context, id uuid.UUID) error {
 func (m *MockPersonSvc) UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error) {
 	return person, nil
 }
+
+func (m *MockPersonSvc) PatchPerson(ctx context.Context, id uuid.UUID, patch func(domain.Person) domain.Person) (domain.Person, error) {
+	current, _ := m.GetPerson(ctx, id)
+	return patch(current), nil
+}
+
+func (m *MockPersonSvc) FindDuplicates(ctx context.Context, id uuid.UUID, minScore float64, limit int) ([]domain.DuplicateCandidate, error) {
+	return nil, nil
+}
+
+func (m *MockPersonSvc) MergePersons(ctx context.Context, targetID, sourceID uuid.UUID, strategies domain.MergeStrategies) (domain.Person, error) {
+	return domain.Person{ID: targetID}, nil
+}
+
+func (m *MockPersonSvc) GetMerges(ctx context.Context, targetID uuid.UUID) ([]domain.Merge, error) {
+	return nil, nil
+}
+
+func (m *MockPersonSvc) AddRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) (domain.Relationship, error) {
+	return domain.NewRelationship(from, to, t), nil
+}
+
+func (m *MockPersonSvc) DeleteRelationship(ctx context.Context, from, to uuid.UUID, t domain.RelationType) error {
+	return nil
+}
+
+func (m *MockPersonSvc) GetRelationships(ctx context.Context, id uuid.UUID) ([]domain.Relationship, error) {
+	return nil, nil
+}
+
+func (m *MockPersonSvc) ShortestPath(ctx context.Context, from, to uuid.UUID, q domain.PathQuery) (domain.Path, error) {
+	return domain.Path{}, nil
+}
+
+func (m *MockPersonSvc) FriendsOfFriends(ctx context.Context, id uuid.UUID) ([]domain.Suggestion, error) {
+	return nil, nil
+}
+
+func (m *MockPersonSvc) SuggestHobbies(ctx context.Context, prefix string, limit int) ([]domain.HobbySuggestion, error) {
+	return nil, nil
+}
+
+func (m *MockPersonSvc) SimilarPersons(ctx context.Context, id uuid.UUID, limit int) ([]domain.SimilarPerson, error) {
+	return nil, nil
+}
+
+func (m *MockPersonSvc) GetAttributeSchema(ctx context.Context) (domain.AttributeSchema, error) {
+	return domain.AttributeSchema{}, nil
+}
+
+func (m *MockPersonSvc) SetAttributeSchema(ctx context.Context, schema domain.AttributeSchema) (domain.AttributeSchema, error) {
+	return schema, nil
+}
+
+func (m *MockPersonSvc) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	return domain.Stats{}, nil
+}
 func TestAddPerson(t *testing.T) {
 	mockSvc := NewMockPersonSvc()
 	handler := handlers.AddPerson(mockSvc, slog.Default(), customvalidator.NewCustomValidator(validator.New()))
@@ -198,3 +255,46 @@ func TestDeletePerson(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusNoContent, w.Code)
 	}
 }
+
+func TestPatchPerson(t *testing.T) {
+	mockSvc := NewMockPersonSvc()
+	handler := handlers.PatchPerson(mockSvc, slog.Default(), customvalidator.NewCustomValidator(validator.New()))
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedAge  int32
+	}{
+		{"changes only sent fields", `{"age":45}`, http.StatusOK, 45},
+		{"empty patch", `{}`, http.StatusOK, 30},
+		{"invalid age", `{"age":200}`, http.StatusUnprocessableEntity, 0},
+		{"empty hobbies", `{"hobbies":[]}`, http.StatusUnprocessableEntity, 0},
+		{"malformed body", `{`, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			personID := uuid.New()
+			req := httptest.NewRequest(http.MethodPatch, "/persons/"+personID.String(), bytes.NewBufferString(tt.body))
+			req.SetPathValue("personId", personID.String())
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedCode, w.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			var response dto.JSONPerson
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Age != tt.expectedAge || response.Name != "Test Person" {
+				t.Errorf("Expected Test Person aged %d, got %s aged %d", tt.expectedAge, response.Name, response.Age)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/photo"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+const (
+	// photoField is the multipart form field holding an uploaded photo.
+	photoField = "photo"
+	// multipartOverhead is allowed on top of the photo for the multipart
+	// headers and boundaries.
+	multipartOverhead = 64 << 10
+	// photoCacheControl lets clients reuse a photo for five minutes, then
+	// revalidate it with its ETag.
+	photoCacheControl = "private, max-age=300"
+)
+
+// UploadPhoto godoc
+//
+//	@Summary		Upload a profile photo
+//	@Description	Replace the photo of a person with a JPEG, PNG or WebP image sent in the photo field of a multipart form. The type is sniffed from the content. The image is turned upright, stripped of EXIF metadata and stored with small, medium and large thumbnails.
+//	@Tags			Photos
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Param			photo		formData	file	true	"JPEG, PNG or WebP image"
+//	@Success		200			{object}	dto.JSONPhoto
+//	@Failure		400			{object}	string	"No photo field"
+//	@Failure		404			{object}	string	"Person not found"
+//	@Failure		413			{object}	string	"Photo too large"
+//	@Failure		415			{object}	string	"Not a multipart form, or not a JPEG, PNG or WebP image"
+//	@Failure		422			{object}	string	"Corrupt image or dimensions out of bounds"
+//	@Router			/api/v1/persons/{personId}/photo [put]
+func UploadPhoto(photos *photo.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, photos.MaxBytes()+multipartOverhead)
+		mr, err := r.MultipartReader()
+		if err != nil {
+			writeError(w, "body must be multipart/form-data", http.StatusUnsupportedMediaType)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				writeError(w, "photo field is required", http.StatusBadRequest)
+				return
+			}
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				HandleError(photo.ErrTooLarge, w, logger)
+				return
+			}
+			if err != nil {
+				writeError(w, "Invalid multipart body", http.StatusBadRequest)
+				return
+			}
+			if part.FormName() != photoField {
+				continue
+			}
+			p, err := photos.Upload(r.Context(), personID, part)
+			if errors.As(err, &tooLarge) {
+				err = photo.ErrTooLarge
+			}
+			if err != nil {
+				HandleError(err, w, logger)
+				return
+			}
+			writeJSON(w, http.StatusOK, dto.ConvertToJSONPhoto(p, photos.Sizes()), logger)
+			return
+		}
+	}
+}
+
+// GetPhoto godoc
+//
+//	@Summary		Get a profile photo
+//	@Description	Get the photo of a person, or one of its thumbnails. Responses carry an ETag and Last-Modified for conditional requests.
+//	@Tags			Photos
+//	@Produce		jpeg,png
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Param			size		query		string	false	"Rendition"	Enums(small, medium, large, original)	default(original)
+//	@Success		200			{file}		binary
+//	@Success		304			"Not modified"
+//	@Failure		400			{object}	string	"Unknown size"
+//	@Failure		404			{object}	string	"The person has no photo"
+//	@Router			/api/v1/persons/{personId}/photo [get]
+func GetPhoto(photos *photo.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		size := photo.SizeOriginal
+		if v := r.URL.Query().Get("size"); v != "" {
+			size = photo.Size(v)
+		}
+		if sizes := photos.Sizes(); !slices.Contains(sizes, size) {
+			names := make([]string, len(sizes))
+			for i, s := range sizes {
+				names[i] = string(s)
+			}
+			writeError(w, "size must be one of "+strings.Join(names, " "), http.StatusBadRequest)
+			return
+		}
+
+		blob, err := photos.Get(r.Context(), personID, size)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		sum := sha256.Sum256(blob.Data)
+		h := w.Header()
+		h.Set("Content-Type", blob.ContentType)
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Cache-Control", photoCacheControl)
+		h.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		http.ServeContent(w, r, "", blob.ModTime, bytes.NewReader(blob.Data))
+	}
+}
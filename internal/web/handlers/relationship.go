@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+)
+
+const (
+	defaultPathDepth = 6
+	maxPathDepth     = 10
+)
+
+// AddRelationship godoc
+//
+//	@Summary		Relate a person to another one
+//	@Description	Make this person the friend, spouse, parent or manager of another person
+//	@Tags			Relationships
+//	@Accept			json
+//	@Produce		json
+//	@Param			personId		path		string					true	"ID of the person"
+//	@Param			relationship	body		dto.CreateRelationship	true	"Related person and type"
+//	@Success		201				{object}	dto.JSONRelationship
+//	@Failure		400				{object}	string	"Invalid input"
+//	@Failure		404				{object}	string	"Person not found"
+//	@Failure		409				{object}	string	"Relationship already exists"
+//	@Failure		422				{object}	string	"Person related to itself"
+//	@Router			/api/v1/persons/{personId}/relationships [post]
+func AddRelationship(personSvc person.PersonSvcApi, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		var in dto.CreateRelationship
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+
+		rel, err := personSvc.AddRelationship(r.Context(), personID, in.ToID, domain.RelationType(in.Type))
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(dto.ConvertToJSONRelationship(rel)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
+// GetRelationships godoc
+//
+//	@Summary		List the relationships of a person
+//	@Description	List the relationships from and to this person, oldest first
+//	@Tags			Relationships
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Param			type		query		string	false	"Only relationships of this type"	Enums(friend, spouse, parent, manager)
+//	@Param			direction	query		string	false	"out: from the person, in: to the person"	Enums(out, in, both)	default(both)
+//	@Success		200			{object}	dto.GetRelationshipsResponse
+//	@Failure		400			{object}	string	"Invalid type or direction"
+//	@Failure		404			{object}	string	"Person not found"
+//	@Router			/api/v1/persons/{personId}/relationships [get]
+func GetRelationships(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		t := domain.RelationType(r.URL.Query().Get("type"))
+		if t != "" && !slices.Contains(domain.RelationTypes, t) {
+			writeError(w, "type must be one of friend spouse parent manager", http.StatusBadRequest)
+			return
+		}
+		direction := r.URL.Query().Get("direction")
+		if direction != "" && direction != "out" && direction != "in" && direction != "both" {
+			writeError(w, "direction must be one of out in both", http.StatusBadRequest)
+			return
+		}
+
+		rels, err := personSvc.GetRelationships(r.Context(), personID)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		rels = slices.DeleteFunc(rels, func(rel domain.Relationship) bool {
+			return (t != "" && rel.Type != t) ||
+				(direction == "out" && rel.FromID != personID) ||
+				(direction == "in" && rel.ToID != personID)
+		})
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToGetRelationshipsResponse(rels)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
+// DeleteRelationship godoc
+//
+//	@Summary		Delete a relationship
+//	@Description	Delete the relationship of the given type from this person to another one
+//	@Tags			Relationships
+//	@Param			personId	path	string	true	"ID of the person"
+//	@Param			type		path	string	true	"Relationship type"	Enums(friend, spouse, parent, manager)
+//	@Param			otherId		path	string	true	"ID of the related person"
+//	@Success		204
+//	@Failure		404	{object}	string	"Relationship not found"
+//	@Router			/api/v1/persons/{personId}/relationships/{type}/{otherId} [delete]
+func DeleteRelationship(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		otherID, err := uuid.Parse(r.PathValue("otherId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+
+		err = personSvc.DeleteRelationship(r.Context(), personID, otherID, domain.RelationType(r.PathValue("type")))
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetShortestPath godoc
+//
+//	@Summary		Find how two persons are connected
+//	@Description	Find a path with the fewest relationships between two persons
+//	@Tags			Relationships
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the first person"
+//	@Param			otherId		path		string	true	"ID of the last person"
+//	@Param			types		query		string	false	"Comma separated relationship types the path may follow, all by default"
+//	@Param			directed	query		bool	false	"Only follow relationships from each person to the next"	default(false)
+//	@Param			maxDepth	query		int		false	"Maximum number of relationships on the path"			default(6)
+//	@Success		200			{object}	dto.PathResponse
+//	@Failure		400			{object}	string	"Invalid types, directed or maxDepth"
+//	@Failure		404			{object}	string	"Person not found or no path"
+//	@Router			/api/v1/persons/{personId}/path/{otherId} [get]
+func GetShortestPath(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		otherID, err := uuid.Parse(r.PathValue("otherId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		q := domain.PathQuery{MaxDepth: defaultPathDepth}
+		if v := r.URL.Query().Get("types"); v != "" {
+			for _, t := range strings.Split(v, ",") {
+				t := domain.RelationType(strings.TrimSpace(t))
+				if !slices.Contains(domain.RelationTypes, t) {
+					writeError(w, "types must be among friend spouse parent manager", http.StatusBadRequest)
+					return
+				}
+				q.Types = append(q.Types, t)
+			}
+		}
+		if v := r.URL.Query().Get("directed"); v != "" {
+			if q.Directed, err = strconv.ParseBool(v); err != nil {
+				writeError(w, "directed must be true or false", http.StatusBadRequest)
+				return
+			}
+		}
+		if v := r.URL.Query().Get("maxDepth"); v != "" {
+			if q.MaxDepth, err = strconv.Atoi(v); err != nil || q.MaxDepth < 1 || q.MaxDepth > maxPathDepth {
+				writeError(w, "maxDepth must be between 1 and 10", http.StatusBadRequest)
+				return
+			}
+		}
+
+		path, err := personSvc.ShortestPath(r.Context(), personID, otherID, q)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToPathResponse(path)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
+// GetFriendsOfFriends godoc
+//
+//	@Summary		Suggest friends of friends
+//	@Description	List the friends of this person's friends who are not its friends yet, most mutual friends first
+//	@Tags			Relationships
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Success		200			{object}	dto.FriendsOfFriendsResponse
+//	@Failure		404			{object}	string	"Person not found"
+//	@Router			/api/v1/persons/{personId}/friends-of-friends [get]
+func GetFriendsOfFriends(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		suggestions, err := personSvc.FriendsOfFriends(r.Context(), personID)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToFriendsOfFriendsResponse(suggestions)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 100
+)
+
+// GetSimilar godoc
+//
+//	@Summary		Recommend persons with similar hobbies
+//	@Description	Rank the persons sharing hobbies with this one by TF-IDF cosine similarity of their hobbies, so rare shared hobbies count more. Ties go to the person closest in age.
+//	@Tags			Persons
+//	@Produce		json
+//	@Param			personId	path		string	true	"ID of the person"
+//	@Param			limit		query		int		false	"Maximum number of persons"	default(10)
+//	@Success		200			{object}	dto.GetSimilarResponse
+//	@Failure		400			{object}	string	"Invalid limit"
+//	@Failure		404			{object}	string	"Person not found"
+//	@Router			/api/v1/persons/{personId}/similar [get]
+func GetSimilar(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		personID, err := uuid.Parse(r.PathValue("personId"))
+		if err != nil {
+			http.Error(w, "Invalid person ID", http.StatusUnprocessableEntity)
+			return
+		}
+		limit := defaultSimilarLimit
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxSimilarLimit {
+				writeError(w, "limit must be between 1 and 100", http.StatusBadRequest)
+				return
+			}
+		}
+
+		similar, err := personSvc.SimilarPersons(r.Context(), personID, limit)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToGetSimilarResponse(similar)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lafetz/assessment/internal/core/domain"
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+var defaultStatsQuery = domain.StatsQuery{
+	Buckets:     []int32{18, 25, 35, 45, 55, 65},
+	Percentiles: []float64{25, 50, 75, 90, 99},
+	TopHobbies:  10,
+	TopPairs:    10,
+}
+
+const maxStatsTop = 100
+
+// GetStats godoc
+//
+//	@Summary		Aggregate statistics of persons
+//	@Description	Count persons, summarize their ages and list the most common hobbies and pairs of hobbies held together. Query parameters named attributes.{name} only count persons whose attribute has that value, as in the person list.
+//	@Tags			Persons
+//	@Produce		json
+//	@Param			buckets		query		string	false	"Comma separated ascending lower bounds of the age buckets after the first"	default(18,25,35,45,55,65)
+//	@Param			percentiles	query		string	false	"Comma separated age percentiles, each in (0, 100]"							default(25,50,75,90,99)
+//	@Param			top			query		int		false	"Number of hobbies"															default(10)
+//	@Param			pairs		query		int		false	"Number of hobby pairs"														default(10)
+//	@Success		200			{object}	dto.StatsResponse
+//	@Failure		400			{object}	string	"Invalid query"
+//	@Router			/api/v1/persons/stats [get]
+func GetStats(personSvc person.PersonSvcApi, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		q, msg := parseStatsQuery(r)
+		if msg != "" {
+			writeError(w, msg, http.StatusBadRequest)
+			return
+		}
+
+		stats, err := personSvc.Stats(r.Context(), q)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(dto.ConvertToStatsResponse(stats)); err != nil {
+			HandleError(err, w, logger)
+		}
+	}
+}
+
+// parseStatsQuery returns the query of r, or a message explaining why it
+// is invalid.
+func parseStatsQuery(r *http.Request) (domain.StatsQuery, string) {
+	q := defaultStatsQuery
+	q.Filter = parsePersonFilter(r)
+	query := r.URL.Query()
+	if v := query.Get("buckets"); v != "" {
+		q.Buckets = nil
+		for _, field := range strings.Split(v, ",") {
+			bound, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+			if err != nil || bound <= 0 || (len(q.Buckets) > 0 && int32(bound) <= q.Buckets[len(q.Buckets)-1]) {
+				return q, "buckets must be ascending positive ages"
+			}
+			q.Buckets = append(q.Buckets, int32(bound))
+		}
+	}
+	if v := query.Get("percentiles"); v != "" {
+		q.Percentiles = nil
+		for _, field := range strings.Split(v, ",") {
+			p, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil || p <= 0 || p > 100 {
+				return q, "percentiles must be numbers in (0, 100]"
+			}
+			q.Percentiles = append(q.Percentiles, p)
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  *int
+	}{{"top", &q.TopHobbies}, {"pairs", &q.TopPairs}} {
+		if v := query.Get(param.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || n > maxStatsTop {
+				return q, param.name + " must be between 0 and 100"
+			}
+			*param.dst = n
+		}
+	}
+	return q, ""
+}
@@ -8,6 +8,9 @@ import (
 	"strconv"
 
 	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/photo"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/lafetz/assessment/internal/webhook"
 )
 
 type PaginationParams struct {
@@ -37,10 +40,23 @@ func ParsePagination(r *http.Request) PaginationParams {
 
 func HandleError(err error, w http.ResponseWriter, logger *slog.Logger) {
 
+	var attrErr *person.AttributeError
 	if err != nil {
 		switch {
-		case errors.Is(err, person.ErrNotFound):
+		case errors.As(err, &attrErr):
+			writeAttributeErrors(w, attrErr)
+		case errors.Is(err, person.ErrNotFound), errors.Is(err, webhook.ErrNotFound), errors.Is(err, photo.ErrNotFound):
 			writeError(w, "not found", http.StatusNotFound)
+		case errors.Is(err, photo.ErrTooLarge):
+			writeError(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, photo.ErrUnsupportedType):
+			writeError(w, err.Error(), http.StatusUnsupportedMediaType)
+		case errors.Is(err, person.ErrNoPath):
+			writeError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, person.ErrConflict), errors.Is(err, person.ErrRelationshipExists), errors.Is(err, person.ErrMemberExists), errors.Is(err, webhook.ErrNotDeadLetter):
+			writeError(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, person.ErrInvalidMerge), errors.Is(err, person.ErrInvalidRelationship), errors.Is(err, person.ErrSelfRelationship), errors.Is(err, person.ErrInvalidRole), errors.Is(err, person.ErrInvalidSchema), errors.Is(err, photo.ErrInvalidImage), errors.Is(err, webhook.ErrForbiddenAddress):
+			writeError(w, err.Error(), http.StatusUnprocessableEntity)
 		default:
 			logger.Error(err.Error())
 			writeError(w, "internal server error", http.StatusInternalServerError)
@@ -66,3 +82,19 @@ func writeError(w http.ResponseWriter, message string, statusCode int) {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 	}
 }
+
+// writeAttributeErrors answers like a failed request validation, with the
+// rejected attributes keyed by their path in the request body.
+func writeAttributeErrors(w http.ResponseWriter, err *person.AttributeError) {
+	fields := make(map[string]string, len(err.Fields))
+	for name, msg := range err.Fields {
+		fields["attributes."+name] = msg
+	}
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := json.NewEncoder(w).Encode(customvalidator.ValidationErrorResponse{
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors:     fields,
+	}); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/lafetz/assessment/internal/webhook"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v any, logger *slog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Subscribe a webhook
+//	@Description	Register a URL receiving person events. Deliveries are signed with the secret in the X-Webhook-Signature header as t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">. The secret is only returned here.
+//	@Tags			Webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		dto.CreateWebhook	true	"Webhook"
+//	@Success		201		{object}	dto.JSONWebhook
+//	@Failure		400		{object}	string	"Invalid input"
+//	@Failure		422		{object}	customvalidator.ValidationErrorResponse		"Validation failed, or the URL names a loopback, link-local or private address"
+//	@Router			/api/v1/webhooks [post]
+func CreateWebhook(webhooks *webhook.Service, logger *slog.Logger, v *customvalidator.CustomValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		var in dto.CreateWebhook
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "Invalid input", http.StatusBadRequest)
+			return
+		}
+		if v.ValidateAndRespond(w, in) {
+			return
+		}
+		sub, err := webhooks.Subscribe(in.ToSubscription())
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusCreated, dto.ConvertToJSONWebhook(sub, true), logger)
+	}
+}
+
+// GetWebhooks godoc
+//
+//	@Summary		List webhooks
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Success		200	{array}	dto.JSONWebhook
+//	@Router			/api/v1/webhooks [get]
+func GetWebhooks(webhooks *webhook.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONWebhookArray(webhooks.Subscriptions()), logger)
+	}
+}
+
+// GetWebhook godoc
+//
+//	@Summary		Get a webhook
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"ID of the webhook"
+//	@Success		200			{object}	dto.JSONWebhook
+//	@Failure		404			{object}	string	"Webhook not found"
+//	@Router			/api/v1/webhooks/{webhookId} [get]
+func GetWebhook(webhooks *webhook.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		id, err := uuid.Parse(r.PathValue("webhookId"))
+		if err != nil {
+			http.Error(w, "Invalid webhook ID", http.StatusUnprocessableEntity)
+			return
+		}
+		sub, err := webhooks.Subscription(id)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONWebhook(sub, false), logger)
+	}
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary		Delete a webhook
+//	@Tags			Webhooks
+//	@Param			webhookId	path	string	true	"ID of the webhook"
+//	@Success		204			"No Content"
+//	@Failure		404			{object}	string	"Webhook not found"
+//	@Router			/api/v1/webhooks/{webhookId} [delete]
+func DeleteWebhook(webhooks *webhook.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		id, err := uuid.Parse(r.PathValue("webhookId"))
+		if err != nil {
+			http.Error(w, "Invalid webhook ID", http.StatusUnprocessableEntity)
+			return
+		}
+		if err := webhooks.Unsubscribe(id); err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetWebhookDeliveries godoc
+//
+//	@Summary		List recent deliveries of a webhook
+//	@Description	Newest first, with every attempt, for debugging receivers
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			webhookId	path	string	true	"ID of the webhook"
+//	@Success		200			{array}		dto.JSONDelivery
+//	@Failure		404			{object}	string	"Webhook not found"
+//	@Router			/api/v1/webhooks/{webhookId}/deliveries [get]
+func GetWebhookDeliveries(webhooks *webhook.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		id, err := uuid.Parse(r.PathValue("webhookId"))
+		if err != nil {
+			http.Error(w, "Invalid webhook ID", http.StatusUnprocessableEntity)
+			return
+		}
+		deliveries, err := webhooks.Deliveries(id)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONDeliveryArray(deliveries), logger)
+	}
+}
+
+// GetDeadLetters godoc
+//
+//	@Summary		List dead-lettered deliveries
+//	@Description	Deliveries that failed every attempt
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Success		200	{array}	dto.JSONDelivery
+//	@Router			/api/v1/webhooks/dead-letters [get]
+func GetDeadLetters(webhooks *webhook.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		writeJSON(w, http.StatusOK, dto.ConvertToJSONDeliveryArray(webhooks.DeadLetters()), logger)
+	}
+}
+
+// RedeliverDeadLetter godoc
+//
+//	@Summary		Retry a dead-lettered delivery
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Param			deliveryId	path		string	true	"ID of the delivery"
+//	@Success		202			{object}	dto.JSONDelivery
+//	@Failure		404			{object}	string	"Delivery not found"
+//	@Failure		409			{object}	string	"Delivery is not dead-lettered"
+//	@Router			/api/v1/webhooks/dead-letters/{deliveryId}/redeliver [post]
+func RedeliverDeadLetter(webhooks *webhook.Service, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		id, err := uuid.Parse(r.PathValue("deliveryId"))
+		if err != nil {
+			http.Error(w, "Invalid delivery ID", http.StatusUnprocessableEntity)
+			return
+		}
+		delivery, err := webhooks.Redeliver(id)
+		if err != nil {
+			HandleError(err, w, logger)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, dto.ConvertToJSONDelivery(delivery), logger)
+	}
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/lafetz/assessment/internal/events"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"github.com/lafetz/assessment/internal/web/dto"
+)
+
+const (
+	wsWriteTimeout     = 10 * time.Second
+	wsPongTimeout      = 60 * time.Second
+	wsPingInterval     = 30 * time.Second
+	wsMaxMessageSize   = 4 << 10
+	wsMaxSubscriptions = 100
+	// wsReplyBuffer bounds the acknowledgements waiting to be written; a
+	// client that sends faster than it reads is disconnected.
+	wsReplyBuffer = 16
+)
+
+type wsSubscription struct {
+	personIDs []uuid.UUID
+	filter    events.Filter
+}
+
+func (s wsSubscription) matches(e events.Event) bool {
+	if len(s.personIDs) > 0 && !slices.Contains(s.personIDs, e.Person.ID) {
+		return false
+	}
+	return s.filter.Matches(e.Event)
+}
+
+type wsSession struct {
+	conn    *websocket.Conn
+	logger  *slog.Logger
+	replies chan dto.WSServerMessage
+	// done is closed when the client goes away or misbehaves.
+	done chan struct{}
+
+	mu            sync.Mutex
+	subscriptions map[string]wsSubscription
+}
+
+// PersonUpdatesSocket godoc
+//
+//	@Summary		Subscribe to person changes over a WebSocket
+//	@Description	Upgrade to a WebSocket exchanging JSON messages. Send {"type":"subscribe","id":"s1","personIds":["..."],"filter":"type == person.updated && age >= 18"} and {"type":"unsubscribe","id":"s1"}; every matching change arrives once as {"type":"event","subscriptions":["s1"],"event":{...}}. Filters compare type, personId, name, age and hobby with == != < <= > >= joined by && and ||. Clients that fall behind are disconnected with close code 1013 and should reconnect.
+//	@Tags			Persons
+//	@Param			message	body	dto.WSClientMessage	false	"Messages sent after the upgrade"
+//	@Success		101		{object}	dto.WSServerMessage
+//	@Router			/api/v1/ws [get]
+func PersonUpdatesSocket(bus *events.Bus, checkOrigin func(*http.Request) bool, logger *slog.Logger) http.HandlerFunc {
+	upgrader := websocket.Upgrader{CheckOrigin: checkOrigin}
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := customlogger.FromContext(r.Context(), logger)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already responded with an error.
+			return
+		}
+		s := &wsSession{
+			conn:          conn,
+			logger:        logger,
+			replies:       make(chan dto.WSServerMessage, wsReplyBuffer),
+			done:          make(chan struct{}),
+			subscriptions: make(map[string]wsSubscription),
+		}
+		sub, _, _ := bus.Subscribe(0, false)
+		defer bus.Unsubscribe(sub)
+		defer conn.Close()
+
+		go s.read()
+		s.write(sub)
+	}
+}
+
+// read handles client messages until the connection fails.
+func (s *wsSession) read() {
+	defer close(s.done)
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg dto.WSClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			if !s.reply(dto.WSServerMessage{Type: dto.WSError, Message: "invalid message: " + err.Error()}) {
+				return
+			}
+			continue
+		}
+		if !s.reply(s.handle(msg)) {
+			return
+		}
+	}
+}
+
+func (s *wsSession) handle(msg dto.WSClientMessage) dto.WSServerMessage {
+	fail := func(message string) dto.WSServerMessage {
+		return dto.WSServerMessage{Type: dto.WSError, ID: msg.ID, Message: message}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch msg.Type {
+	case dto.WSSubscribe:
+		if msg.ID == "" {
+			return fail("subscribe needs an id")
+		}
+		if _, exists := s.subscriptions[msg.ID]; exists {
+			return fail("subscription " + msg.ID + " already exists")
+		}
+		if len(s.subscriptions) >= wsMaxSubscriptions {
+			return fail("too many subscriptions")
+		}
+		filter, err := events.ParseFilter(msg.Filter)
+		if err != nil {
+			return fail(err.Error())
+		}
+		s.subscriptions[msg.ID] = wsSubscription{personIDs: msg.PersonIDs, filter: filter}
+		return dto.WSServerMessage{Type: dto.WSSubscribed, ID: msg.ID}
+	case dto.WSUnsubscribe:
+		if _, exists := s.subscriptions[msg.ID]; !exists {
+			return fail("unknown subscription " + msg.ID)
+		}
+		delete(s.subscriptions, msg.ID)
+		return dto.WSServerMessage{Type: dto.WSUnsubscribed, ID: msg.ID}
+	case dto.WSPing:
+		return dto.WSServerMessage{Type: dto.WSPong, ID: msg.ID}
+	default:
+		return fail("unknown message type " + msg.Type)
+	}
+}
+
+// reply queues msg for the writer and reports false when the client does
+// not keep up with its own requests.
+func (s *wsSession) reply(msg dto.WSServerMessage) bool {
+	select {
+	case s.replies <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// matching returns the sorted IDs of the subscriptions e matches.
+func (s *wsSession) matching(e events.Event) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id, sub := range s.subscriptions {
+		if sub.matches(e) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// write sends replies, matching events and pings until the client goes
+// away or the event subscription ends.
+func (s *wsSession) write(sub *events.Subscription) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		var msg dto.WSServerMessage
+		select {
+		case <-s.done:
+			return
+		case msg = <-s.replies:
+		case e, ok := <-sub.C:
+			if !ok {
+				s.closeFor(sub.Err())
+				return
+			}
+			ids := s.matching(e)
+			if len(ids) == 0 {
+				continue
+			}
+			event := dto.ConvertToJSONPersonEvent(e.Event)
+			msg = dto.WSServerMessage{Type: dto.WSEvent, Subscriptions: ids, Event: &event}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+			continue
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := s.conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+// closeFor tells the client why its event subscription ended.
+func (s *wsSession) closeFor(err error) {
+	code, reason := websocket.CloseGoingAway, "server shutting down"
+	if errors.Is(err, events.ErrSlowConsumer) {
+		code, reason = websocket.CloseTryAgainLater, "slow consumer"
+		s.logger.Warn("disconnecting slow websocket consumer")
+	}
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteTimeout))
+}
@@ -0,0 +1,226 @@
+// Package idempotency makes retried POST requests safe. A request carrying
+// an Idempotency-Key header is executed once; retries with the same key
+// and body replay the stored response.
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on replayed responses.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	DefaultTTL = 24 * time.Hour
+	// MaxKeyLength bounds the keys accepted from clients.
+	MaxKeyLength = 255
+	// maxBodySize bounds the request bodies read for the fingerprint.
+	maxBodySize = 1 << 20
+)
+
+// response is a stored response, replayed on retries.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+type entry struct {
+	fingerprint [sha256.Size]byte
+	// resp is nil while the first request is running.
+	resp      *response
+	expiresAt time.Time
+}
+
+// Store keeps the responses of requests with an Idempotency-Key for TTL.
+type Store struct {
+	ttl   time.Duration
+	now   func() time.Time
+	scope func(*http.Request) string
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	nextSweep time.Time
+}
+
+type Option func(*Store)
+
+// WithScope partitions keys by the scope of each request, e.g. its tenant,
+// so that clients in different scopes never share a response.
+func WithScope(scope func(*http.Request) string) Option {
+	return func(s *Store) {
+		s.scope = scope
+	}
+}
+
+func New(ttl time.Duration, opts ...Option) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	s := &Store{ttl: ttl, now: time.Now, entries: make(map[string]*entry)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SetTTL changes how long new responses are kept.
+func (s *Store) SetTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttl = ttl
+}
+
+// Handler runs next once per key. A retry with the same key replays the
+// stored response, one with a different method, path or body is rejected
+// with 422 and one arriving while the first is still running with 409.
+// Server errors are not stored, so the request can be retried.
+func (s *Store) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > MaxKeyLength {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if s.scope != nil {
+			key = s.scope(r) + "\x00" + key
+		}
+
+		e, existing := s.reserve(key, fingerprint(r, body))
+		if existing != nil {
+			switch {
+			case existing.fingerprint != e.fingerprint:
+				writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+			case existing.resp == nil:
+				writeError(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+			default:
+				replay(w, existing.resp)
+			}
+			return
+		}
+
+		// Headers set by outer middleware, such as the request ID and CORS
+		// headers, belong to each request and are not stored.
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK, before: w.Header().Clone()}
+		// Deferred so that a panic releases the key too.
+		defer s.finish(key, e, rec)
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// reserve returns a new entry for key, or the live entry already holding
+// it. The returned entry always carries fp.
+func (s *Store) reserve(key string, fp [sha256.Size]byte) (*entry, *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	e := &entry{fingerprint: fp}
+	if existing, ok := s.entries[key]; ok && (existing.resp == nil || now.Before(existing.expiresAt)) {
+		return e, existing
+	}
+	s.entries[key] = e
+	return e, nil
+}
+
+func (s *Store) finish(key string, e *entry, rec *recorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !rec.wroteHeader || rec.status >= http.StatusInternalServerError {
+		delete(s.entries, key)
+		return
+	}
+	e.resp = &response{status: rec.status, header: rec.added(), body: rec.body.Bytes()}
+	e.expiresAt = s.now().Add(s.ttl)
+}
+
+// sweep drops expired entries at most once a minute.
+func (s *Store) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(time.Minute)
+	for key, e := range s.entries {
+		if e.resp != nil && !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func fingerprint(r *http.Request, body []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+func replay(w http.ResponseWriter, resp *response) {
+	for k, v := range resp.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"statusCode": status, "message": message})
+}
+
+// recorder passes the response through while keeping a copy of it.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	// before is the header as it was before the handler ran.
+	before http.Header
+}
+
+// added returns the headers the handler set or changed.
+func (r *recorder) added() http.Header {
+	h := make(http.Header)
+	for k, v := range r.Header() {
+		if !slices.Equal(v, r.before[k]) {
+			h[k] = slices.Clone(v)
+		}
+	}
+	return h
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,169 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func post(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/persons", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// counting answers status with the number of calls so far.
+func counting(calls *atomic.Int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+	})
+}
+
+func TestHandler_ReplaysResponse(t *testing.T) {
+	var calls atomic.Int32
+	h := New(time.Hour).Handler(counting(&calls, http.StatusCreated))
+
+	first := post(h, "key-1", `{"name":"a"}`)
+	second := post(h, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	assert.Empty(t, first.Header().Get(ReplayedHeader))
+}
+
+func TestHandler_DifferentBody(t *testing.T) {
+	var calls atomic.Int32
+	h := New(time.Hour).Handler(counting(&calls, http.StatusCreated))
+
+	post(h, "key-1", `{"name":"a"}`)
+	rec := post(h, "key-1", `{"name":"b"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHandler_WithoutKey(t *testing.T) {
+	var calls atomic.Int32
+	h := New(time.Hour).Handler(counting(&calls, http.StatusCreated))
+
+	post(h, "", `{}`)
+	post(h, "", `{}`)
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestHandler_KeyTooLong(t *testing.T) {
+	var calls atomic.Int32
+	h := New(time.Hour).Handler(counting(&calls, http.StatusCreated))
+
+	rec := post(h, strings.Repeat("k", MaxKeyLength+1), `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Zero(t, calls.Load())
+}
+
+func TestHandler_ServerErrorsAreNotStored(t *testing.T) {
+	var calls atomic.Int32
+	h := New(time.Hour).Handler(counting(&calls, http.StatusServiceUnavailable))
+
+	post(h, "key-1", `{}`)
+	rec := post(h, "key-1", `{}`)
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Empty(t, rec.Header().Get(ReplayedHeader))
+}
+
+func TestHandler_InProgress(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := New(time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		post(h, "key-1", `{}`)
+	}()
+	<-started
+	rec := post(h, "key-1", `{}`)
+	close(release)
+	<-done
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, http.StatusCreated, post(h, "key-1", `{}`).Code)
+}
+
+func TestHandler_Expiry(t *testing.T) {
+	var calls atomic.Int32
+	s := New(time.Hour)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	h := s.Handler(counting(&calls, http.StatusCreated))
+
+	post(h, "key-1", `{}`)
+	now = now.Add(time.Hour)
+	post(h, "key-1", `{"other":true}`)
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Len(t, s.entries, 1)
+}
+
+func TestHandler_OuterHeadersAreNotReplayed(t *testing.T) {
+	var calls atomic.Int32
+	inner := New(time.Hour).Handler(counting(&calls, http.StatusCreated))
+	requestID := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID++
+		w.Header().Set("X-Request-ID", strconv.Itoa(requestID))
+		inner.ServeHTTP(w, r)
+	})
+
+	post(h, "key-1", `{}`)
+	rec := post(h, "key-1", `{}`)
+
+	assert.Equal(t, "true", rec.Header().Get(ReplayedHeader))
+	assert.Equal(t, "2", rec.Header().Get("X-Request-ID"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
+func TestHandler_Scope(t *testing.T) {
+	var calls atomic.Int32
+	h := New(time.Hour, WithScope(func(r *http.Request) string {
+		return r.Header.Get("X-Tenant-ID")
+	})).Handler(counting(&calls, http.StatusCreated))
+	postAs := func(tenant, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/persons", strings.NewReader(body))
+		req.Header.Set(Header, "key-1")
+		req.Header.Set("X-Tenant-ID", tenant)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	postAs("acme", `{"name":"a"}`)
+	rec := postAs("globex", `{"name":"b"}`)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get(ReplayedHeader))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "true", postAs("acme", `{"name":"a"}`).Header().Get(ReplayedHeader))
+}
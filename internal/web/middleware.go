@@ -1,38 +1,142 @@
 package web
 
 import (
+	"bufio"
+	"crypto/subtle"
 	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+
+	person "github.com/lafetz/assessment/internal/core/service"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (app *App) recoverPanic(next http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				logger := customlogger.FromContext(r.Context(), app.logger)
 				w.Header().Set("Connection:", "close")
 				var errorMessage string
 				if e, ok := err.(error); ok {
 					errorMessage = e.Error()
-					app.logger.Error(errorMessage)
+					logger.ErrorContext(r.Context(), errorMessage)
 					http.Error(w, "internal server error", http.StatusInternalServerError)
 				} else {
 					errorMessage = fmt.Sprintf("panic: %v", err)
-					app.logger.Error(errorMessage)
+					logger.ErrorContext(r.Context(), errorMessage)
 				}
 			}
 		}()
 		next.ServeHTTP(w, r)
 	}
 }
-func (app *App) enableCORS(next http.Handler) http.Handler {
+
+// responseRecorder captures the status code and body size written by a
+// handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades through; the connection is recorded as
+// switching protocols.
+func (rec *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(rec.ResponseWriter).Hijack()
+	if err == nil {
+		rec.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+func (app *App) instrument(method, route string, next http.Handler) http.Handler {
+	if app.metrics == nil {
+		return next
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", ("*"))
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		w.Header().Set("Access-Control-Max-Age", "3600")
+		done := app.metrics.RequestStarted(method, route)
+		rec := newResponseRecorder(w)
+		defer func() {
+			done(rec.status)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// traceRoute starts a server span for every request, continuing the trace
+// from an incoming traceparent header.
+func (app *App) traceRoute(method, route string, next http.Handler) http.Handler {
+	return otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRoute(route))
+		next.ServeHTTP(w, r)
+	}), method+" "+route)
+}
+
+// validTenant matches the tenant IDs accepted in TenantHeader.
+var validTenant = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// TenantHeader names the tenant a request acts for; requests without it
+// act for person.DefaultTenant.
+const TenantHeader = "X-Tenant-ID"
+
+// RequestTenant returns the tenant a request acts for. Use it as the
+// idempotency.WithScope of the App's store so that tenants never share
+// stored responses.
+func RequestTenant(r *http.Request) string {
+	return person.TenantFromContext(r.Context())
+}
+
+// withTenant carries the tenant named by TenantHeader in the request
+// context.
+func (app *App) withTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(TenantHeader)
+		if tenant == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !validTenant.MatchString(tenant) {
+			http.Error(w, "Invalid tenant ID", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(person.WithTenant(r.Context(), tenant)))
+	})
+}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusNoContent)
+// requireAdmin rejects requests without the admin token, when one is set.
+func (app *App) requireAdmin(next http.Handler) http.Handler {
+	if app.adminToken == "" {
+		return next
+	}
+	want := []byte("Bearer " + app.adminToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 		next.ServeHTTP(w, r)
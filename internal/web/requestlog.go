@@ -0,0 +1,94 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	customlogger "github.com/lafetz/assessment/internal/logger"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+type requestIDKey struct{}
+
+// RequestID returns the ID assigned to the request that ctx belongs to.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID accepts client supplied IDs made of printable ASCII so they
+// can be echoed back and logged safely.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// logRequest assigns or propagates the request ID, stores a request scoped
+// logger in the context and writes one access log line per request. Routes
+// listed in accessLogSampling only log the given fraction of successful
+// requests; failures are always logged.
+func (app *App) logRequest(method, route string, next http.Handler) http.Handler {
+	key := method + " " + route
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		logger := app.logger.With(
+			slog.String("request_id", id),
+			slog.String("method", method),
+			slog.String("route", route),
+			slog.String("remote_ip", remoteIP(r)),
+		)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = customlogger.WithContext(ctx, logger)
+
+		rec := newResponseRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		if rec.status < http.StatusBadRequest {
+			if sampling := app.accessLogSampling.Load(); sampling != nil {
+				if rate, ok := (*sampling)[key]; ok && rand.Float64() >= rate {
+					return
+				}
+			}
+		}
+		level := slog.LevelInfo
+		if rec.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(ctx, level, "request completed",
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Int("bytes", rec.bytes),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
@@ -1,6 +1,10 @@
 package web
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
+
 	_ "github.com/lafetz/assessment/docs"
 	"github.com/lafetz/assessment/internal/web/handlers"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
@@ -10,10 +14,89 @@ func (a *App) initAppRoutes() {
 	a.Router.HandleFunc("GET /swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
-	a.Router.HandleFunc("GET /api/v1/persons", a.recoverPanic(a.enableCORS(handlers.GetPersons(a.PersonSvc, a.logger))))
-	a.Router.HandleFunc("GET /api/v1/persons/{personId}", a.recoverPanic(a.enableCORS(handlers.GetPersonByID(a.PersonSvc, a.logger))))
-	a.Router.HandleFunc("POST /api/v1/persons", a.recoverPanic(a.enableCORS(handlers.AddPerson(a.PersonSvc, a.logger, a.validate))))
-	a.Router.HandleFunc("PUT /api/v1/persons/{personId}", a.recoverPanic(a.enableCORS(handlers.UpdatePerson(a.PersonSvc, a.logger, a.validate))))
-	a.Router.HandleFunc("DELETE /api/v1/persons/{personId}", a.recoverPanic(a.enableCORS(handlers.DeletePerson(a.PersonSvc, a.logger))))
-	a.Router.HandleFunc("/", a.recoverPanic(a.enableCORS(handlers.NotFound())))
+	a.Router.HandleFunc("GET /healthz", a.recoverPanic(a.health.LivenessHandler()))
+	a.Router.HandleFunc("GET /readyz", a.recoverPanic(a.health.ReadinessHandler()))
+	if a.metrics != nil {
+		a.Router.Handle("GET /metrics", a.metrics.Handler())
+	}
+	a.handle(http.MethodGet, "/api/v1/persons", handlers.GetPersons(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/persons/stats", handlers.GetStats(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/hobbies", handlers.GetHobbies(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/admin/attribute-schema", a.requireAdmin(handlers.GetAttributeSchema(a.PersonSvc, a.logger)))
+	a.handle(http.MethodPut, "/api/v1/admin/attribute-schema", a.requireAdmin(handlers.PutAttributeSchema(a.PersonSvc, a.logger, a.validate)))
+	if a.events != nil {
+		a.handle(http.MethodGet, "/api/v1/persons/events", handlers.StreamPersonEvents(a.events, a.eventHeartbeat, a.logger))
+		a.handle(http.MethodGet, "/api/v1/ws", handlers.PersonUpdatesSocket(a.events, a.checkWebSocketOrigin, a.logger))
+	}
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}", handlers.GetPersonByID(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}/duplicates", handlers.GetDuplicates(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}/similar", handlers.GetSimilar(a.PersonSvc, a.logger))
+	a.handle(http.MethodPost, "/api/v1/persons/{personId}/merge", handlers.MergePerson(a.PersonSvc, a.logger, a.validate))
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}/merges", handlers.GetMerges(a.PersonSvc, a.logger))
+	a.handle(http.MethodPost, "/api/v1/persons/{personId}/relationships", handlers.AddRelationship(a.PersonSvc, a.logger, a.validate))
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}/relationships", handlers.GetRelationships(a.PersonSvc, a.logger))
+	a.handle(http.MethodDelete, "/api/v1/persons/{personId}/relationships/{type}/{otherId}", handlers.DeleteRelationship(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}/path/{otherId}", handlers.GetShortestPath(a.PersonSvc, a.logger))
+	a.handle(http.MethodGet, "/api/v1/persons/{personId}/friends-of-friends", handlers.GetFriendsOfFriends(a.PersonSvc, a.logger))
+	a.handle(http.MethodPost, "/api/v1/persons", handlers.AddPerson(a.PersonSvc, a.logger, a.validate))
+	a.handle(http.MethodPut, "/api/v1/persons/{personId}", handlers.UpdatePerson(a.PersonSvc, a.logger, a.validate))
+	a.handle(http.MethodPatch, "/api/v1/persons/{personId}", handlers.PatchPerson(a.PersonSvc, a.logger, a.validate))
+	a.handle(http.MethodDelete, "/api/v1/persons/{personId}", handlers.DeletePerson(a.PersonSvc, a.logger))
+	if a.groups != nil {
+		a.handle(http.MethodPost, "/api/v1/groups", handlers.CreateGroup(a.groups, a.logger, a.validate))
+		a.handle(http.MethodGet, "/api/v1/groups", handlers.GetGroups(a.groups, a.logger))
+		a.handle(http.MethodGet, "/api/v1/groups/{groupId}", handlers.GetGroup(a.groups, a.logger))
+		a.handle(http.MethodPut, "/api/v1/groups/{groupId}", handlers.UpdateGroup(a.groups, a.logger, a.validate))
+		a.handle(http.MethodDelete, "/api/v1/groups/{groupId}", handlers.DeleteGroup(a.groups, a.logger))
+		a.handle(http.MethodPost, "/api/v1/groups/{groupId}/members", handlers.AddGroupMember(a.groups, a.logger, a.validate))
+		a.handle(http.MethodGet, "/api/v1/groups/{groupId}/members", handlers.GetGroupMembers(a.groups, a.logger))
+		a.handle(http.MethodPut, "/api/v1/groups/{groupId}/members/{personId}", handlers.UpdateGroupMember(a.groups, a.logger, a.validate))
+		a.handle(http.MethodDelete, "/api/v1/groups/{groupId}/members/{personId}", handlers.RemoveGroupMember(a.groups, a.logger))
+		a.handle(http.MethodGet, "/api/v1/persons/{personId}/groups", handlers.GetPersonGroups(a.groups, a.logger))
+	}
+	if a.photos != nil {
+		a.handle(http.MethodPut, "/api/v1/persons/{personId}/photo", handlers.UploadPhoto(a.photos, a.logger))
+		a.handle(http.MethodGet, "/api/v1/persons/{personId}/photo", handlers.GetPhoto(a.photos, a.logger))
+	}
+	if a.webhooks != nil {
+		a.handle(http.MethodPost, "/api/v1/webhooks", handlers.CreateWebhook(a.webhooks, a.logger, a.validate))
+		a.handle(http.MethodGet, "/api/v1/webhooks", handlers.GetWebhooks(a.webhooks, a.logger))
+		a.handle(http.MethodGet, "/api/v1/webhooks/dead-letters", handlers.GetDeadLetters(a.webhooks, a.logger))
+		a.handle(http.MethodPost, "/api/v1/webhooks/dead-letters/{deliveryId}/redeliver", handlers.RedeliverDeadLetter(a.webhooks, a.logger))
+		a.handle(http.MethodGet, "/api/v1/webhooks/{webhookId}", handlers.GetWebhook(a.webhooks, a.logger))
+		a.handle(http.MethodDelete, "/api/v1/webhooks/{webhookId}", handlers.DeleteWebhook(a.webhooks, a.logger))
+		a.handle(http.MethodGet, "/api/v1/webhooks/{webhookId}/deliveries", handlers.GetWebhookDeliveries(a.webhooks, a.logger))
+	}
+	a.Router.HandleFunc("/", a.recoverPanic(a.cors.Handler(handlers.NotFound())))
+}
+
+// checkWebSocketOrigin accepts WebSocket upgrades from the same origin,
+// from clients that send no Origin (non-browser clients) and from the
+// WebSocket origins of the CORS policy. A CORS policy allowing "*" does not
+// open WebSockets to every site.
+func (a *App) checkWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return a.cors.AllowsWebSocketOrigin(origin)
+}
+
+// handle registers handler for method and path and records the method with
+// the CORS policy. The first registration of a path also registers its
+// preflight handler. Handlers act for the tenant named by TenantHeader.
+// POST handlers honour Idempotency-Key when enabled.
+func (a *App) handle(method, path string, handler http.Handler) {
+	if len(a.cors.AllowedMethods(path)) == 0 {
+		a.Router.HandleFunc(http.MethodOptions+" "+path, a.recoverPanic(a.cors.Preflight(path)))
+	}
+	a.cors.AllowMethod(path, method)
+	if method == http.MethodPost && a.idempotency != nil {
+		handler = a.idempotency.Handler(handler)
+	}
+	handler = a.withTenant(handler)
+	a.Router.Handle(method+" "+path, a.traceRoute(method, path, a.logRequest(method, path, a.instrument(method, path, a.recoverPanic(a.cors.Handler(handler))))))
 }
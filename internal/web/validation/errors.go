@@ -26,6 +26,26 @@ func errorMsgs(tag string, value string) string {
 		return "can not be greater than " + value
 	case "gte":
 		return "can not be less than " + value
+	case "http_url":
+		return "must be an http or https URL"
+	case "eq":
+		return "must be " + value
+	case "oneof":
+		return "must be one of " + value
+	case "min":
+		return "must have at least " + value + " element(s) or character(s)"
+	case "max":
+		return "must have at most " + value + " element(s) or character(s)"
+	case "required_without":
+		return "This field is required unless " + strings.ToLower(value[:1]) + value[1:] + " is set"
+	case "email":
+		return "must be a valid email address"
+	case "e164":
+		return "must be an E.164 phone number, e.g. +14155552671"
+	case "birthdate":
+		return "must be a past date formatted as YYYY-MM-DD"
+	case "iso3166_1_alpha2":
+		return "must be an ISO 3166-1 alpha-2 country code"
 	}
 	return ""
 }
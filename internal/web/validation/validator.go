@@ -3,24 +3,50 @@ package customvalidator
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/lafetz/assessment/internal/core/domain"
 )
 
 type CustomValidator struct {
-	validate *validator.Validate
+	validate  *validator.Validate
+	onFailure func(field, rule string)
 }
 
-func NewCustomValidator(validate *validator.Validate) *CustomValidator {
-	return &CustomValidator{
+// Option configures optional behaviour of the CustomValidator.
+type Option func(*CustomValidator)
+
+// WithFailureObserver registers fn to be called for every field that fails
+// validation, e.g. to count failures.
+func WithFailureObserver(fn func(field, rule string)) Option {
+	return func(v *CustomValidator) {
+		v.onFailure = fn
+	}
+}
+
+func NewCustomValidator(validate *validator.Validate, opts ...Option) *CustomValidator {
+	// Registering a known tag only fails for an empty name or function.
+	_ = validate.RegisterValidation("birthdate", validBirthDate)
+	v := &CustomValidator{
 		validate: validate,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 func (v *CustomValidator) ValidateAndRespond(w http.ResponseWriter, input interface{}) bool {
 	err := v.validate.Struct(input)
 	if err != nil {
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			if v.onFailure != nil {
+				for _, fe := range validationErrors {
+					v.onFailure(strings.ToLower(fe.Field()), fe.Tag())
+				}
+			}
 			errors := ValidateModel(validationErrors)
 			w.WriteHeader(http.StatusUnprocessableEntity)
 			if err := json.NewEncoder(w).Encode(ValidationErrorResponse{
@@ -39,3 +65,14 @@ type ValidationErrorResponse struct {
 	StatusCode int         `json:"statusCode"`
 	Errors     interface{} `json:"errors"`
 }
+
+// validBirthDate accepts YYYY-MM-DD dates that are not in the future and
+// at most 150 years in the past.
+func validBirthDate(fl validator.FieldLevel) bool {
+	d, err := time.Parse(domain.DateLayout, fl.Field().String())
+	if err != nil {
+		return false
+	}
+	now := time.Now().UTC()
+	return !d.After(now) && domain.AgeOn(d, now) <= 150
+}
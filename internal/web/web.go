@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -9,11 +10,21 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	person "github.com/lafetz/assessment/internal/core/service"
+	"github.com/lafetz/assessment/internal/events"
+	"github.com/lafetz/assessment/internal/health"
+	"github.com/lafetz/assessment/internal/metrics"
+	"github.com/lafetz/assessment/internal/photo"
+	"github.com/lafetz/assessment/internal/web/cors"
+	"github.com/lafetz/assessment/internal/web/idempotency"
 	customvalidator "github.com/lafetz/assessment/internal/web/validation"
+	"github.com/lafetz/assessment/internal/webhook"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 //	@title			Persons Api
@@ -33,27 +44,228 @@ type App struct {
 	logger    *slog.Logger
 	PersonSvc person.PersonSvcApi
 	validate  *customvalidator.CustomValidator
+	cors      *cors.Policy
+	metrics   *metrics.Metrics
+	health    *health.Health
+	// drainDelay is how long the app keeps serving after reporting not
+	// ready, giving load balancers time to stop routing traffic to it.
+	drainDelay time.Duration
+	// accessLogSampling maps "METHOD /route" to the fraction of successful
+	// requests that are written to the access log.
+	accessLogSampling atomic.Pointer[map[string]float64]
+	timeouts          Timeouts
+	tlsConfig         *tls.Config
+	disableHTTP2      bool
+	h2c               bool
+	webhooks          *webhook.Service
+	groups            person.GroupSvcApi
+	photos            *photo.Service
+	events            *events.Bus
+	eventHeartbeat    time.Duration
+	idempotency       *idempotency.Store
+	adminToken        string
 }
 
-func NewApp(port int, logger *slog.Logger, personSvc person.PersonSvcApi, validate *customvalidator.CustomValidator) *App {
+// Timeouts bounds the lifetime of connections and of graceful shutdown.
+type Timeouts struct {
+	Read     time.Duration
+	Write    time.Duration
+	Idle     time.Duration
+	Shutdown time.Duration
+}
+
+var defaultTimeouts = Timeouts{
+	Read:     10 * time.Second,
+	Write:    30 * time.Second,
+	Idle:     time.Minute,
+	Shutdown: 5 * time.Second,
+}
+
+// Option configures optional parts of the App.
+type Option func(*App)
+
+// WithCORS replaces the default policy, which allows any origin without
+// credentials.
+func WithCORS(policy *cors.Policy) Option {
+	return func(a *App) {
+		a.cors = policy
+	}
+}
+
+// WithAdminToken requires "Authorization: Bearer <token>" on the admin
+// endpoints. Without it they are open.
+func WithAdminToken(token string) Option {
+	return func(a *App) {
+		a.adminToken = token
+	}
+}
+
+// WithMetrics instruments every route and serves the collectors on /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(a *App) {
+		a.metrics = m
+	}
+}
+
+// WithHealth serves readiness from h instead of an App without checks.
+func WithHealth(h *health.Health) Option {
+	return func(a *App) {
+		a.health = h
+	}
+}
+
+// WithDrainDelay sets how long Run waits between failing readiness and
+// shutting the server down.
+func WithDrainDelay(d time.Duration) Option {
+	return func(a *App) {
+		a.drainDelay = d
+	}
+}
+
+// WithAccessLogSampling only logs the given fraction of successful requests
+// for each "METHOD /route" key, e.g. {"GET /api/v1/persons": 0.1}.
+func WithAccessLogSampling(sampling map[string]float64) Option {
+	return func(a *App) {
+		a.SetAccessLogSampling(sampling)
+	}
+}
+
+// SetAccessLogSampling replaces the access log sampling rates of a running
+// App.
+func (a *App) SetAccessLogSampling(sampling map[string]float64) {
+	a.accessLogSampling.Store(&sampling)
+}
+
+// WithTimeouts overrides the server timeouts.
+func WithTimeouts(t Timeouts) Option {
+	return func(a *App) {
+		a.timeouts = t
+	}
+}
+
+// WithTLS serves HTTPS using cfg, which must provide a certificate through
+// Certificates or GetCertificate.
+func WithTLS(cfg *tls.Config) Option {
+	return func(a *App) {
+		a.tlsConfig = cfg
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2 over TLS. It is enabled by default.
+func WithHTTP2(enabled bool) Option {
+	return func(a *App) {
+		a.disableHTTP2 = !enabled
+	}
+}
+
+// WithH2C accepts HTTP/2 over cleartext connections when TLS is not
+// configured.
+func WithH2C(enabled bool) Option {
+	return func(a *App) {
+		a.h2c = enabled
+	}
+}
+
+// WithWebhooks serves the webhook subscription API backed by s.
+func WithWebhooks(s *webhook.Service) Option {
+	return func(a *App) {
+		a.webhooks = s
+	}
+}
+
+// WithGroups serves the group API backed by s.
+func WithGroups(s person.GroupSvcApi) Option {
+	return func(a *App) {
+		a.groups = s
+	}
+}
+
+// WithPhotos serves person photos stored by s.
+func WithPhotos(s *photo.Service) Option {
+	return func(a *App) {
+		a.photos = s
+	}
+}
+
+// WithEvents streams the person events published on bus at
+// /api/v1/persons/events.
+func WithEvents(bus *events.Bus) Option {
+	return func(a *App) {
+		a.events = bus
+	}
+}
+
+// WithEventHeartbeat sets how often idle event streams send a heartbeat
+// comment so that proxies keep the connection open.
+func WithEventHeartbeat(d time.Duration) Option {
+	return func(a *App) {
+		a.eventHeartbeat = d
+	}
+}
+
+// WithIdempotency makes POST requests with an Idempotency-Key header safe
+// to retry by replaying their responses from s.
+func WithIdempotency(s *idempotency.Store) Option {
+	return func(a *App) {
+		a.idempotency = s
+	}
+}
+
+func NewApp(port int, logger *slog.Logger, personSvc person.PersonSvcApi, validate *customvalidator.CustomValidator, opts ...Option) *App {
 	a := &App{
 		Router:    http.NewServeMux(),
 		logger:    logger,
 		port:      port,
 		PersonSvc: personSvc,
 		validate:  validate,
+		timeouts:  defaultTimeouts,
+		// Below the 30 to 60 second idle timeouts common in proxies.
+		eventHeartbeat: 15 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.cors == nil {
+		a.cors, _ = cors.New(cors.Config{AllowedOrigins: []string{"*"}})
+	}
+	if a.health == nil {
+		a.health = health.New(health.DefaultCheckTimeout)
 	}
 	a.initAppRoutes()
 	return a
 }
-func (a *App) Run() error {
 
+// Handler returns the root handler of the App. Without TLS and with h2c
+// enabled it also accepts HTTP/2 over cleartext, for deployments behind a
+// TLS terminating proxy.
+func (a *App) Handler() http.Handler {
+	if a.h2c && a.tlsConfig == nil {
+		return h2c.NewHandler(a.Router, &http2.Server{IdleTimeout: a.timeouts.Idle})
+	}
+	return a.Router
+}
+
+func (a *App) newServer() *http.Server {
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", strconv.Itoa(a.port)),
-		Handler:      a.Router,
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		Handler:      a.Handler(),
+		IdleTimeout:  a.timeouts.Idle,
+		ReadTimeout:  a.timeouts.Read,
+		WriteTimeout: a.timeouts.Write,
+		TLSConfig:    a.tlsConfig,
+	}
+	if a.disableHTTP2 {
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	return srv
+}
+
+func (a *App) Run() error {
+
+	srv := a.newServer()
+	if a.events != nil {
+		// Streams never finish on their own; end them so Shutdown can.
+		srv.RegisterOnShutdown(a.events.Close)
 	}
 
 	shutdownError := make(chan error)
@@ -64,12 +276,23 @@ func (a *App) Run() error {
 		<-quit
 
 		a.logger.Info("shutting down server")
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		a.health.Shutdown()
+		if a.drainDelay > 0 {
+			a.logger.Info("draining traffic", "delay", a.drainDelay.String())
+			time.Sleep(a.drainDelay)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), a.timeouts.Shutdown)
 		defer cancel()
 
 		shutdownError <- srv.Shutdown(ctx)
 	}()
-	err := srv.ListenAndServe()
+	var err error
+	if srv.TLSConfig != nil {
+		a.logger.Info("serving TLS", "http2", !a.disableHTTP2)
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
 
 	if !errors.Is(err, http.ErrServerClosed) {
 		return err
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for webhook URLs reaching loopback,
+// link-local, private or other non-public addresses, which would let a
+// subscriber make the server call internal services.
+var ErrForbiddenAddress = errors.New("webhook: address is not public")
+
+// sharedAddressSpace (RFC 6598) is used by carrier-grade NAT and by some
+// cloud metadata services.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// addressPolicy decides which addresses deliveries may connect to.
+type addressPolicy struct {
+	// allowed networks are reachable even when not public, e.g. loopback
+	// in local development.
+	allowed []netip.Prefix
+}
+
+func (p addressPolicy) check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, prefix := range p.allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+	return nil
+}
+
+// control runs after the host name is resolved and right before
+// connecting, so a name can not pass as public when subscribed and resolve
+// to an internal address when delivered to. It also covers redirects.
+func (p addressPolicy) control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	return p.check(addrPort.Addr())
+}
+
+// checkURL rejects URLs naming a forbidden address directly, so that the
+// subscriber learns it at once rather than from failed deliveries.
+func (p addressPolicy) checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return p.check(netip.IPv6Loopback())
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.check(addr)
+	}
+	return nil
+}
+
+// client returns an HTTP client that only connects to addresses allowed
+// by p. It ignores proxy settings, since a proxy would connect on its
+// behalf without the check.
+func (p addressPolicy) client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: p.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Run delivers queued events until ctx is cancelled. Deliveries still
+// pending at that point are not retried.
+func (s *Service) Run(ctx context.Context) {
+	defer close(s.done)
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					s.attempt(ctx, id)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// enqueue hands a delivery to the workers without blocking the caller.
+func (s *Service) enqueue(id uuid.UUID) {
+	select {
+	case s.queue <- id:
+	default:
+		go func() {
+			select {
+			case s.queue <- id:
+			case <-s.done:
+			}
+		}()
+	}
+}
+
+// attempt sends a delivery once and schedules a retry or dead-letters it
+// on failure.
+func (s *Service) attempt(ctx context.Context, id uuid.UUID) {
+	sub, payload, eventType, ok := s.pending(id)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	status, err := s.send(ctx, sub, id, eventType, payload)
+	attempt := Attempt{At: start.UTC(), Duration: time.Since(start), StatusCode: status}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	s.mu.Lock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	d.Attempts = append(d.Attempts, attempt)
+	d.budget--
+	var retryIn time.Duration
+	switch {
+	case err == nil:
+		d.Status = StatusSucceeded
+		d.NextAttemptAt = time.Time{}
+	case d.budget <= 0:
+		d.Status = StatusDead
+		d.NextAttemptAt = time.Time{}
+	default:
+		retryIn = s.backoff(s.cfg.MaxAttempts - d.budget - 1)
+		d.NextAttemptAt = time.Now().UTC().Add(retryIn)
+	}
+	finalStatus := d.Status
+	s.mu.Unlock()
+
+	logger := s.logger.With("delivery_id", id, "subscription_id", sub.ID, "event", eventType)
+	switch {
+	case err == nil:
+		logger.DebugContext(ctx, "webhook delivered", "status", status)
+	case finalStatus == StatusDead:
+		logger.WarnContext(ctx, "webhook delivery moved to dead-letter list", "error", err)
+	default:
+		logger.InfoContext(ctx, "webhook delivery failed, retrying", "error", err, "retry_in", retryIn)
+		time.AfterFunc(retryIn, func() { s.enqueue(id) })
+	}
+}
+
+// pending returns what is needed to send a delivery, or false if it or its
+// subscription was removed meanwhile.
+func (s *Service) pending(id uuid.UUID) (Subscription, []byte, string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.deliveries[id]
+	if !ok || d.Status != StatusPending {
+		return Subscription{}, nil, "", false
+	}
+	sub, ok := s.subscriptions[d.SubscriptionID]
+	return sub, d.payload, string(d.EventType), ok
+}
+
+func (s *Service) send(ctx context.Context, sub Subscription, id uuid.UUID, eventType string, payload []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "persons-api-webhooks")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, id.String())
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, time.Now(), payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given failed attempt (0-based):
+// exponential with jitter, capped at MaxBackoff.
+func (s *Service) backoff(attempt int) time.Duration {
+	d := s.cfg.MinBackoff << attempt
+	if d <= 0 || d > s.cfg.MaxBackoff {
+		d = s.cfg.MaxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
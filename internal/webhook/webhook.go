@@ -0,0 +1,372 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+)
+
+var (
+	ErrNotFound      = errors.New("webhook: not found")
+	ErrNotDeadLetter = errors.New("webhook: delivery is not in the dead-letter list")
+)
+
+const (
+	// maxDeliveriesPerSubscription bounds the delivery history kept for
+	// debugging; the oldest succeeded, then dead, deliveries are dropped
+	// first.
+	maxDeliveriesPerSubscription = 100
+	queueSize                    = 1024
+)
+
+type Subscription struct {
+	ID  uuid.UUID
+	URL string
+	// Events filters the delivered events; empty means every event.
+	Events    []domain.EventType
+	Secret    string
+	CreatedAt time.Time
+}
+
+func (s Subscription) wants(t domain.EventType) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, t)
+}
+
+type DeliveryStatus string
+
+const (
+	StatusPending   DeliveryStatus = "pending"
+	StatusSucceeded DeliveryStatus = "succeeded"
+	// StatusDead marks deliveries that used up their attempts and wait in
+	// the dead-letter list for a manual redelivery.
+	StatusDead DeliveryStatus = "dead"
+)
+
+type Attempt struct {
+	At         time.Time
+	Duration   time.Duration
+	StatusCode int
+	Error      string
+}
+
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      domain.EventType
+	CreatedAt      time.Time
+	Status         DeliveryStatus
+	Attempts       []Attempt
+	NextAttemptAt  time.Time
+	payload        []byte
+	// budget is the number of attempts left before the delivery is dead.
+	budget int
+}
+
+type Config struct {
+	// MaxAttempts is how often a delivery is tried before it is moved to the
+	// dead-letter list.
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// Timeout bounds a single attempt.
+	Timeout time.Duration
+	Workers int
+	// AllowedNetworks may be delivered to although they are not public,
+	// e.g. 127.0.0.0/8 for a receiver on the same host in development.
+	AllowedNetworks []netip.Prefix
+}
+
+var DefaultConfig = Config{
+	MaxAttempts: 6,
+	MinBackoff:  time.Second,
+	MaxBackoff:  10 * time.Minute,
+	Timeout:     10 * time.Second,
+	Workers:     4,
+}
+
+// Service stores webhook subscriptions and delivers person events to them.
+// It implements person.EventPublisher; deliveries start once Run is called.
+type Service struct {
+	cfg        Config
+	logger     *slog.Logger
+	httpClient *http.Client
+	addresses  addressPolicy
+
+	mu            sync.RWMutex
+	subscriptions map[uuid.UUID]Subscription
+	deliveries    map[uuid.UUID]*Delivery
+	bySub         map[uuid.UUID][]uuid.UUID
+
+	queue chan uuid.UUID
+	done  chan struct{}
+}
+
+func New(cfg Config, logger *slog.Logger) *Service {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultConfig.MaxAttempts
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultConfig.MinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(cfg.MinBackoff, DefaultConfig.MaxBackoff)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultConfig.Timeout
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultConfig.Workers
+	}
+	addresses := addressPolicy{allowed: cfg.AllowedNetworks}
+	return &Service{
+		cfg:           cfg,
+		logger:        logger,
+		httpClient:    addresses.client(cfg.Timeout),
+		addresses:     addresses,
+		subscriptions: make(map[uuid.UUID]Subscription),
+		deliveries:    make(map[uuid.UUID]*Delivery),
+		bySub:         make(map[uuid.UUID][]uuid.UUID),
+		queue:         make(chan uuid.UUID, queueSize),
+		done:          make(chan struct{}),
+	}
+}
+
+// Subscribe registers sub and returns it with its ID. A random secret is
+// generated when sub.Secret is empty. URLs naming a non-public address
+// fail with ErrForbiddenAddress; host names are checked on every delivery.
+func (s *Service) Subscribe(sub Subscription) (Subscription, error) {
+	if err := s.addresses.checkURL(sub.URL); err != nil {
+		return Subscription{}, err
+	}
+	sub.ID = uuid.New()
+	sub.CreatedAt = time.Now().UTC()
+	if sub.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return Subscription{}, err
+		}
+		sub.Secret = hex.EncodeToString(b)
+	}
+	s.mu.Lock()
+	s.subscriptions[sub.ID] = sub
+	s.mu.Unlock()
+	return sub, nil
+}
+
+// Subscriptions returns every subscription, oldest first.
+func (s *Service) Subscriptions() []Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subs := make([]Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subs = append(subs, sub)
+	}
+	slices.SortFunc(subs, func(a, b Subscription) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return subs
+}
+
+func (s *Service) Subscription(id uuid.UUID) (Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return Subscription{}, ErrNotFound
+	}
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription and its delivery history. Deliveries
+// in flight are abandoned.
+func (s *Service) Unsubscribe(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscriptions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.subscriptions, id)
+	for _, deliveryID := range s.bySub[id] {
+		delete(s.deliveries, deliveryID)
+	}
+	delete(s.bySub, id)
+	return nil
+}
+
+// Deliveries returns the recent deliveries of a subscription, newest first.
+func (s *Service) Deliveries(subscriptionID uuid.UUID) ([]Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.subscriptions[subscriptionID]; !ok {
+		return nil, ErrNotFound
+	}
+	ids := s.bySub[subscriptionID]
+	deliveries := make([]Delivery, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, s.snapshot(ids[i]))
+	}
+	return deliveries, nil
+}
+
+// DeadLetters returns the deliveries that used up their attempts.
+func (s *Service) DeadLetters() []Delivery {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var dead []Delivery
+	for id, d := range s.deliveries {
+		if d.Status == StatusDead {
+			dead = append(dead, s.snapshot(id))
+		}
+	}
+	slices.SortFunc(dead, func(a, b Delivery) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return dead
+}
+
+// Redeliver moves a dead delivery back to pending with a fresh set of
+// attempts.
+func (s *Service) Redeliver(id uuid.UUID) (Delivery, error) {
+	s.mu.Lock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		s.mu.Unlock()
+		return Delivery{}, ErrNotFound
+	}
+	if d.Status != StatusDead {
+		s.mu.Unlock()
+		return Delivery{}, ErrNotDeadLetter
+	}
+	d.Status = StatusPending
+	d.budget = s.cfg.MaxAttempts
+	d.NextAttemptAt = time.Now().UTC()
+	snapshot := s.snapshot(id)
+	s.mu.Unlock()
+
+	s.enqueue(id)
+	return snapshot, nil
+}
+
+// snapshot copies a delivery so callers can read it without the lock.
+// s.mu must be held.
+func (s *Service) snapshot(id uuid.UUID) Delivery {
+	d := *s.deliveries[id]
+	d.Attempts = slices.Clone(d.Attempts)
+	return d
+}
+
+type eventPayload struct {
+	ID         uuid.UUID        `json:"id"`
+	Type       domain.EventType `json:"type"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Data       personPayload    `json:"data"`
+}
+
+type personPayload struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name,omitempty"`
+	Age        int32           `json:"age,omitempty"`
+	Hobbies    []string        `json:"hobbies,omitempty"`
+	Email      string          `json:"email,omitempty"`
+	Phone      string          `json:"phone,omitempty"`
+	BirthDate  string          `json:"birthDate,omitempty"`
+	Address    *addressPayload `json:"address,omitempty"`
+	Attributes map[string]any  `json:"attributes,omitempty"`
+}
+
+type addressPayload struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postalCode"`
+	Region     string `json:"region,omitempty"`
+	Country    string `json:"country"`
+}
+
+func newPersonPayload(p domain.Person) personPayload {
+	payload := personPayload{
+		ID:         p.ID,
+		Name:       p.Name,
+		Age:        p.Age,
+		Hobbies:    p.Hobbies,
+		Email:      p.Email,
+		Phone:      p.Phone,
+		Attributes: p.Attributes,
+	}
+	if !p.BirthDate.IsZero() {
+		payload.BirthDate = p.BirthDate.Format(domain.DateLayout)
+	}
+	if a := p.Address; a != nil {
+		payload.Address = &addressPayload{Street: a.Street, City: a.City, PostalCode: a.PostalCode, Region: a.Region, Country: a.Country}
+	}
+	return payload
+}
+
+// Publish queues a delivery of event for every matching subscription.
+func (s *Service) Publish(ctx context.Context, event domain.Event) {
+	payload, err := json.Marshal(eventPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       newPersonPayload(event.Person),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to encode webhook event", "event_id", event.ID, "error", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	var queued []uuid.UUID
+	s.mu.Lock()
+	for _, sub := range s.subscriptions {
+		if !sub.wants(event.Type) {
+			continue
+		}
+		d := &Delivery{
+			ID:             uuid.New(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			CreatedAt:      now,
+			Status:         StatusPending,
+			NextAttemptAt:  now,
+			payload:        payload,
+			budget:         s.cfg.MaxAttempts,
+		}
+		s.deliveries[d.ID] = d
+		s.bySub[sub.ID] = append(s.bySub[sub.ID], d.ID)
+		s.trim(sub.ID)
+		queued = append(queued, d.ID)
+	}
+	s.mu.Unlock()
+
+	for _, id := range queued {
+		s.enqueue(id)
+	}
+}
+
+// trim drops the oldest finished deliveries of a subscription beyond the
+// history limit: succeeded ones first, then dead ones, so that the
+// dead-letter list cannot grow without bound. Pending deliveries are kept.
+// s.mu must be held.
+func (s *Service) trim(subscriptionID uuid.UUID) {
+	ids := s.bySub[subscriptionID]
+	for _, status := range []DeliveryStatus{StatusSucceeded, StatusDead} {
+		for i := 0; len(ids) > maxDeliveriesPerSubscription && i < len(ids); {
+			if s.deliveries[ids[i]].Status == status {
+				delete(s.deliveries, ids[i])
+				ids = slices.Delete(ids, i, i+1)
+				continue
+			}
+			i++
+		}
+	}
+	s.bySub[subscriptionID] = ids
+}
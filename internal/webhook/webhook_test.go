@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lafetz/assessment/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	event     string
+	delivery  string
+	signature string
+	body      []byte
+}
+
+// receiver records requests and answers the first failures of them with 500.
+func receiver(t *testing.T, failures int32) (*httptest.Server, func() []received) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []received
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, received{r.Header.Get(EventHeader), r.Header.Get(DeliveryHeader), r.Header.Get(SignatureHeader), body})
+		mu.Unlock()
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), got...)
+	}
+}
+
+// loopback lets tests deliver to httptest servers.
+var loopback = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+
+func newService(t *testing.T, maxAttempts int) *Service {
+	t.Helper()
+	s := New(Config{MaxAttempts: maxAttempts, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, AllowedNetworks: loopback}, slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.Run(ctx)
+	return s
+}
+
+func TestDeliver(t *testing.T) {
+	server, requests := receiver(t, 0)
+	s := newService(t, 3)
+	sub, err := s.Subscribe(Subscription{URL: server.URL, Events: []domain.EventType{domain.EventPersonCreated}, Secret: "s3cret"})
+	require.NoError(t, err)
+
+	person := domain.NewPerson("Alice", 30, []string{"chess"})
+	s.Publish(context.Background(), domain.NewEvent(domain.EventPersonUpdated, person))
+	s.Publish(context.Background(), domain.NewEvent(domain.EventPersonCreated, person))
+
+	require.Eventually(t, func() bool { return len(requests()) == 1 }, time.Second, time.Millisecond)
+	got := requests()[0]
+	assert.Equal(t, string(domain.EventPersonCreated), got.event)
+	assert.Contains(t, string(got.body), `"name":"Alice"`)
+	assert.NoError(t, Verify("s3cret", got.signature, got.body, time.Minute))
+	assert.ErrorIs(t, Verify("other", got.signature, got.body, time.Minute), ErrInvalidSignature)
+
+	require.Eventually(t, func() bool {
+		deliveries, _ := s.Deliveries(sub.ID)
+		return len(deliveries) == 1 && deliveries[0].Status == StatusSucceeded
+	}, time.Second, time.Millisecond)
+}
+
+func TestDeliver_Retry(t *testing.T) {
+	server, requests := receiver(t, 2)
+	s := newService(t, 3)
+	sub, err := s.Subscribe(Subscription{URL: server.URL})
+	require.NoError(t, err)
+	assert.Len(t, sub.Secret, 64, "a secret is generated when none is given")
+
+	s.Publish(context.Background(), domain.NewEvent(domain.EventPersonDeleted, domain.Person{ID: uuid.New()}))
+
+	require.Eventually(t, func() bool {
+		deliveries, _ := s.Deliveries(sub.ID)
+		return len(deliveries) == 1 && deliveries[0].Status == StatusSucceeded
+	}, time.Second, time.Millisecond)
+	deliveries, _ := s.Deliveries(sub.ID)
+	attempts := deliveries[0].Attempts
+	require.Len(t, attempts, 3)
+	assert.Equal(t, http.StatusInternalServerError, attempts[0].StatusCode)
+	assert.NotEmpty(t, attempts[0].Error)
+	assert.Equal(t, http.StatusNoContent, attempts[2].StatusCode)
+
+	all := requests()
+	assert.Equal(t, all[0].delivery, all[2].delivery, "retries keep the delivery ID")
+}
+
+func TestDeliver_DeadLetter(t *testing.T) {
+	server, requests := receiver(t, 3)
+	s := newService(t, 2)
+	_, err := s.Subscribe(Subscription{URL: server.URL})
+	require.NoError(t, err)
+
+	s.Publish(context.Background(), domain.NewEvent(domain.EventPersonCreated, domain.NewPerson("Bob", 40, []string{"go"})))
+
+	require.Eventually(t, func() bool { return len(s.DeadLetters()) == 1 }, time.Second, time.Millisecond)
+	dead := s.DeadLetters()[0]
+	assert.Len(t, dead.Attempts, 2)
+	assert.Len(t, requests(), 2)
+
+	_, err = s.Redeliver(dead.ID)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(requests()) == 4 && len(s.DeadLetters()) == 0 }, time.Second, time.Millisecond)
+
+	_, err = s.Redeliver(dead.ID)
+	assert.ErrorIs(t, err, ErrNotDeadLetter)
+}
+
+func TestDeliver_DeadLettersTrimmed(t *testing.T) {
+	server, _ := receiver(t, math.MaxInt32)
+	s := newService(t, 1)
+	sub, err := s.Subscribe(Subscription{URL: server.URL})
+	require.NoError(t, err)
+
+	var events []uuid.UUID
+	publish := func(n int) {
+		for range n {
+			event := domain.NewEvent(domain.EventPersonCreated, domain.NewPerson("Bob", 40, []string{"go"}))
+			events = append(events, event.ID)
+			s.Publish(context.Background(), event)
+		}
+		require.Eventually(t, func() bool {
+			dead := s.DeadLetters()
+			return len(dead) == maxDeliveriesPerSubscription || len(dead) == len(events)
+		}, 5*time.Second, time.Millisecond)
+	}
+	publish(maxDeliveriesPerSubscription)
+	publish(5)
+
+	require.Eventually(t, func() bool {
+		deliveries, _ := s.Deliveries(sub.ID)
+		for _, d := range deliveries {
+			if d.Status != StatusDead {
+				return false
+			}
+		}
+		return len(deliveries) == maxDeliveriesPerSubscription
+	}, 5*time.Second, time.Millisecond)
+	kept := make(map[uuid.UUID]bool)
+	for _, d := range s.DeadLetters() {
+		kept[d.EventID] = true
+	}
+	for i, id := range events {
+		assert.Equal(t, i >= 5, kept[id], "event %d", i)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	s := New(DefaultConfig, slog.Default())
+	sub, err := s.Subscribe(Subscription{URL: "http://example.com/hook"})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Unsubscribe(sub.ID))
+	assert.ErrorIs(t, s.Unsubscribe(sub.ID), ErrNotFound)
+	_, err = s.Deliveries(sub.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, s.Subscriptions())
+}
+
+func TestAddressPolicy(t *testing.T) {
+	p := addressPolicy{allowed: []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")}}
+	for addr, allowed := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"10.1.2.3":         true,
+		"10.2.0.1":         false,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"169.254.169.254":  false,
+		"192.168.1.1":      false,
+		"172.16.0.1":       false,
+		"100.100.100.200":  false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+		"0.0.0.0":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		err := p.check(netip.MustParseAddr(addr))
+		if allowed {
+			assert.NoError(t, err, addr)
+		} else {
+			assert.ErrorIs(t, err, ErrForbiddenAddress, addr)
+		}
+	}
+}
+
+func TestSubscribe_ForbiddenAddress(t *testing.T) {
+	s := New(DefaultConfig, slog.Default())
+	for _, url := range []string{"http://127.0.0.1:8080/hook", "http://localhost/hook", "http://[::1]/hook", "http://169.254.169.254/latest/meta-data"} {
+		_, err := s.Subscribe(Subscription{URL: url})
+		assert.ErrorIs(t, err, ErrForbiddenAddress, url)
+	}
+}
+
+func TestDeliver_ForbiddenAtDialTime(t *testing.T) {
+	server, requests := receiver(t, 0)
+	s := New(Config{MaxAttempts: 1, MinBackoff: time.Millisecond}, slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.Run(ctx)
+	// Stands in for a host name that passed Subscribe and resolves to
+	// loopback by the time of delivery.
+	sub := Subscription{ID: uuid.New(), URL: server.URL, CreatedAt: time.Now()}
+	s.mu.Lock()
+	s.subscriptions[sub.ID] = sub
+	s.mu.Unlock()
+
+	s.Publish(context.Background(), domain.NewEvent(domain.EventPersonCreated, domain.NewPerson("Eve", 30, []string{"go"})))
+
+	require.Eventually(t, func() bool { return len(s.DeadLetters()) == 1 }, time.Second, time.Millisecond)
+	assert.Contains(t, s.DeadLetters()[0].Attempts[0].Error, "not public")
+	assert.Empty(t, requests())
+}